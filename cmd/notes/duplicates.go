@@ -0,0 +1,55 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	dbstorage "github.com/Snoop-Duck/ToDoList/internal/infrastructure/db-storage"
+)
+
+const duplicatesCommand = "email-duplicates"
+
+var errEmailDuplicates = errors.New("case-insensitive email duplicates found")
+
+type emailDuplicatesReport struct {
+	Groups     int                    `json:"groups"`
+	Duplicates []users.EmailDuplicate `json:"duplicates"`
+}
+
+// runEmailDuplicates реализует подкоманду `notes email-duplicates [-db dsn]`: перед миграцией
+// с уникальным индексом по lower(email) она показывает аккаунты, которые нужно объединить вручную.
+func runEmailDuplicates(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(duplicatesCommand, flag.ContinueOnError)
+	dsn := fs.String("db", cmp.Or(os.Getenv("DB_CONNECTION_STRING"), defaultDSN), "postgres connection string")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	storage, err := dbstorage.New(context.Background(), *dsn)
+	if err != nil {
+		return err
+	}
+	defer storage.Close()
+
+	duplicates, err := storage.FindEmailDuplicates()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(emailDuplicatesReport{Groups: len(duplicates), Duplicates: duplicates}); err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: %d", errEmailDuplicates, len(duplicates))
+	}
+	return nil
+}
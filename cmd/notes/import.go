@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note"
+)
+
+const importCommand = "import"
+
+var errImportOwner = errors.New("-uid is required: imported notes need an owner")
+
+// runImport реализует подкоманду `notes import -uid <uid> -format csv -file todo.csv [-dry-run]`.
+func runImport(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(importCommand, flag.ContinueOnError)
+	uid := fs.String("uid", "", "owner of the imported notes (required)")
+	format := fs.String("format", "", "import format: csv, json or md")
+	file := fs.String("file", "", "path to the file to import, stdin if empty")
+	mapping := fs.String("map", "", "csv column mapping, e.g. title:Name,status:State")
+	dryRun := fs.Bool("dry-run", false, "report notes without creating them")
+	storage := fs.String("storage", "storage/notes.json", "path to the notes storage file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *uid == "" {
+		return errImportOwner
+	}
+
+	importFormat, err := notes.ParseImportFormat(*format)
+	if err != nil {
+		return err
+	}
+	columns, err := notes.ParseColumnMapping(*mapping)
+	if err != nil {
+		return err
+	}
+
+	in := io.Reader(os.Stdin)
+	if *file != "" {
+		f, openErr := os.Open(*file)
+		if openErr != nil {
+			return openErr
+		}
+		defer f.Close()
+		in = f
+	}
+
+	noteService := note.New(inmemory.NewNotes(false, *storage))
+	report, err := noteService.ImportNotes(in, notes.ImportOptions{
+		UID:     *uid,
+		Format:  importFormat,
+		DryRun:  *dryRun,
+		Mapping: columns,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -9,15 +9,22 @@ import (
 	"strconv"
 	"syscall"
 	"time"
+	// База часовых поясов встраивается в бинарник: в alpine-образе её нет.
+	_ "time/tzdata"
 
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi"
+	"github.com/Snoop-Duck/ToDoList/internal/metrics"
 	"github.com/Snoop-Duck/ToDoList/internal/server"
 	"github.com/Snoop-Duck/ToDoList/internal/services"
+	"github.com/Snoop-Duck/ToDoList/internal/services/webhook"
 
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 
 	dbstorage "github.com/Snoop-Duck/ToDoList/internal/infrastructure/db-storage"
 	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/mailer"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/oidc"
 
 	"github.com/Snoop-Duck/ToDoList/internal"
 	logger "github.com/Snoop-Duck/ToDoList/pkg"
@@ -28,6 +35,18 @@ import (
 
 const (
 	contextTimeout = 5 * time.Second
+	purgeInterval  = time.Hour
+	// auditCapacity — сколько последних событий аудита хранится без базы данных.
+	auditCapacity = 10000
+	// eventHistory — сколько последних событий заметок можно дочитать по Last-Event-ID,
+	// eventBuffer — сколько непрочитанных событий может накопить клиент потока.
+	eventHistory = 1000
+	eventBuffer  = 64
+	defaultDSN   = "postgres://user:password@db:5432/notes?sslmode=disable"
+
+	// Метки хранилищ в метриках операций репозиториев.
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
 )
 
 func gracefulShutdown(cancel context.CancelFunc) {
@@ -68,6 +87,53 @@ func setupDatabase(log logger.Logger, dns string) (server.Repository, *gorm.DB,
 	return repoUser, db, nil
 }
 
+// storageName возвращает метку хранилища пользователей для метрик.
+func storageName(repoUser server.Repository) string {
+	if _, ok := repoUser.(*dbstorage.DBStorage); ok {
+		return storagePostgres
+	}
+	return storageMemory
+}
+
+// registerMetrics добавляет к метрикам статистику пула соединений и число заметок по статусам.
+func registerMetrics(notesAPI *server.NotesAPI, repoUser server.Repository) {
+	if dbRepo, ok := repoUser.(*dbstorage.DBStorage); ok {
+		metrics.Registry.MustRegister(metrics.NewPoolCollector(dbRepo.PoolStat))
+	}
+	metrics.Registry.MustRegister(metrics.NewCountCollector(
+		"notes", "Notes by status.", "status", notesAPI.NoteService().CountByStatus,
+	))
+}
+
+// setupRateLimits выбирает хранилище лимитов; без базы данных у каждой реплики свой бюджет.
+func setupRateLimits(log logger.Logger, cfg internal.RateLimitConfig, repoUser server.Repository) server.RateLimitStore {
+	switch cfg.Store {
+	case "postgres":
+		if dbRateLimits, ok := repoUser.(server.RateLimitStore); ok {
+			return dbRateLimits
+		}
+		log.Warn().Msg("database is unavailable. Use in memory rate limits")
+	case "memory":
+	default:
+		log.Warn().Str("store", cfg.Store).Msg("unknown rate limit store. Use in memory rate limits")
+	}
+	return inmemory.NewRateLimits()
+}
+
+func setupMailer(log logger.Logger, cfg internal.MailConfig) server.Mailer {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTP(cfg.SMTPAddr, cfg.From, cfg.SMTPUser, cfg.SMTPPassword)
+	case "memory":
+		return mailer.NewMemory()
+	case "file":
+		return mailer.NewFile(cfg.Dir, cfg.From)
+	default:
+		log.Warn().Str("driver", cfg.Driver).Msg("unknown mailer driver. Use file mailer")
+		return mailer.NewFile(cfg.Dir, cfg.From)
+	}
+}
+
 func startSyncService(ctx context.Context, db *gorm.DB, log logger.Logger) {
 	syncService := services.New(db, "storage/notes.json")
 
@@ -91,10 +157,75 @@ func startSyncService(ctx context.Context, db *gorm.DB, log logger.Logger) {
 	}()
 }
 
+// startDeletionPurge периодически удаляет аккаунты, срок отмены удаления которых истёк.
+func startDeletionPurge(ctx context.Context, notesAPI *server.NotesAPI, log logger.Logger) {
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				purged, err := notesAPI.PurgeDeletedUsers()
+				if err != nil {
+					log.Error().Err(err).Msg("failed to purge deleted users")
+				}
+				if purged > 0 {
+					log.Info().Int("count", purged).Msg("deleted users purged")
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// startPurge периодически удаляет устаревшие записи: what — что именно, для журнала.
+func startPurge(ctx context.Context, log logger.Logger, what string, purge func() (int, error)) {
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				purged, err := purge()
+				if err != nil {
+					log.Error().Err(err).Msgf("failed to purge %s", what)
+				}
+				if purged > 0 {
+					log.Debug().Int("count", purged).Msgf("%s purged", what)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// setupWebhooks включает вебхуки, только если задан ключ шифрования секретов подписок;
+// без него /webhooks отвечает 503.
+func setupWebhooks(log logger.Logger, cfg *internal.Config, repoUser server.Repository) *webhook.Service {
+	if cfg.EncryptionKey == "" {
+		log.Warn().Msg("webhooks are disabled: NOTES_ENCRYPTION_KEY is not set")
+		return nil
+	}
+	var store webhook.Store = inmemory.NewWebhooks()
+	if dbWebhooks, ok := repoUser.(webhook.Store); ok {
+		store = dbWebhooks
+	}
+	return webhook.New(store,
+		webhook.WithEncryptionKey([]byte(cfg.EncryptionKey)),
+		webhook.WithLogger(log),
+	)
+}
+
 func runServer(
 	ctx context.Context,
 	cfg *internal.Config,
 	notesAPI *server.NotesAPI,
+	grpcAPI *grpcapi.Server,
+	webhooks *webhook.Service,
 	repoUser server.Repository,
 	db *gorm.DB,
 	log logger.Logger,
@@ -109,6 +240,21 @@ func runServer(
 		return nil
 	})
 
+	if grpcAPI != nil {
+		group.Go(func() error {
+			log.Info().Str("address", cfg.Host+":"+strconv.Itoa(cfg.GRPCPort)).Msg("gRPC server starting")
+			return grpcAPI.Run()
+		})
+	}
+
+	if webhooks != nil {
+		// Run внутри группы: остановка дождётся отправляемых доставок.
+		group.Go(func() error {
+			webhooks.Run(gCtx)
+			return nil
+		})
+	}
+
 	group.Go(func() error {
 		<-gCtx.Done()
 
@@ -118,6 +264,9 @@ func runServer(
 		if err := notesAPI.Stop(shutdownCtx); err != nil {
 			log.Error().Err(err).Msg("failed to stop server gracefully")
 		}
+		if grpcAPI != nil {
+			grpcAPI.Stop(shutdownCtx)
+		}
 
 		if err := repoUser.Close(); err != nil {
 			log.Error().Err(err).Msg("failed to close user repository")
@@ -137,6 +286,23 @@ func runServer(
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == importCommand {
+		if err := runImport(os.Args[2:], os.Stdout); err != nil {
+			log := logger.Get()
+			log.Error().Err(err).Msg("import failed")
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == duplicatesCommand {
+		if err := runEmailDuplicates(os.Args[2:], os.Stdout); err != nil {
+			log := logger.Get()
+			log.Error().Err(err).Msg("email duplicates check failed")
+			os.Exit(1)
+		}
+		return
+	}
+
 	cfg, err := internal.ReadConfig()
 	if err != nil {
 		panic(err)
@@ -153,7 +319,7 @@ func main() {
 
 	dns := os.Getenv("DB_CONNECTION_STRING")
 	if dns == "" {
-		dns = "postgres://user:password@db:5432/notes?sslmode=disable"
+		dns = defaultDSN
 	}
 
 	repoUser, db, setupErr := setupDatabase(log, dns)
@@ -168,9 +334,56 @@ func main() {
 		startSyncService(ctx, db, log)
 	}
 
-	notesAPI := server.New(cfg, repoUser, repoNote)
+	var attempts server.AttemptStore = inmemory.NewAttempts()
+	if dbAttempts, ok := repoUser.(server.AttemptStore); ok {
+		attempts = dbAttempts
+	}
+
+	var auditLog server.AuditLog = inmemory.NewAuditLog(auditCapacity)
+	if dbAuditLog, ok := repoUser.(server.AuditLog); ok {
+		auditLog = dbAuditLog
+	}
+
+	var idempotencyStore server.IdempotencyStore = inmemory.NewIdempotency()
+	if dbIdempotency, ok := repoUser.(server.IdempotencyStore); ok {
+		idempotencyStore = dbIdempotency
+	}
+
+	opts := []server.Option{
+		server.WithMailer(setupMailer(log, cfg.Mail)),
+		server.WithAttemptStore(attempts),
+		server.WithAuditLog(auditLog),
+		server.WithIdempotencyStore(idempotencyStore),
+		server.WithRateLimitStore(setupRateLimits(log, cfg.RateLimit, repoUser)),
+		server.WithEventBus(inmemory.NewEventBus(eventHistory, eventBuffer)),
+	}
+	if cfg.SigningKey == "" {
+		log.Warn().Msg("email verification, 2fa and stream tickets are disabled: NOTES_SIGNING_KEY is not set")
+	}
+	webhooks := setupWebhooks(log, cfg, repoUser)
+	if webhooks != nil {
+		opts = append(opts, server.WithWebhooks(webhooks))
+	}
+	if cfg.OIDC.Issuer != "" {
+		opts = append(opts, server.WithOIDC(oidc.New(cfg.OIDC)))
+	}
+
+	notesAPI := server.New(cfg,
+		server.InstrumentRepository(repoUser, storageName(repoUser)),
+		server.InstrumentNotes(repoNote, storageMemory),
+		opts...,
+	)
+	registerMetrics(notesAPI, repoUser)
+	startDeletionPurge(ctx, notesAPI, log)
+	startPurge(ctx, log, "idempotency keys", notesAPI.PurgeIdempotencyKeys)
+	startPurge(ctx, log, "rate limit buckets", notesAPI.PurgeRateLimits)
+
+	var grpcAPI *grpcapi.Server
+	if cfg.GRPCPort != 0 {
+		grpcAPI = grpcapi.New(cfg, notesAPI.NoteService(), notesAPI.UserService(), notesAPI)
+	}
 
-	if runErr := runServer(ctx, cfg, notesAPI, repoUser, db, log); runErr != nil {
+	if runErr := runServer(ctx, cfg, notesAPI, grpcAPI, webhooks, repoUser, db, log); runErr != nil {
 		if !errors.Is(runErr, http.ErrServerClosed) {
 			log.Error().Err(runErr).Msg("service stopped with error")
 			return
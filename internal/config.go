@@ -3,29 +3,128 @@ package internal
 import (
 	"cmp"
 	"flag"
+	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Host      string
-	Port      int
+	Host string
+	Port int
+	// GRPCPort — порт gRPC API; 0 отключает его.
+	GRPCPort  int
 	Debug     bool
 	DBConnStr string
+	BaseURL   string
+	Mail      MailConfig
+	// Admins — адреса пользователей с доступом к /admin.
+	Admins []string
+	OIDC   OIDCConfig
+	// EncryptionKey шифрует секреты 2FA и вебхуков в хранилище; задаётся только через окружение.
+	EncryptionKey string
+	// SigningKey подписывает ссылки подтверждения почты, токены второго шага входа и билеты
+	// потока событий; задаётся только через окружение. Без него подтверждение почты и 2FA отключены.
+	SigningKey string
+	// DeletionGrace — срок, в течение которого удаление аккаунта можно отменить.
+	DeletionGrace time.Duration
+	// ValidateRequests включает проверку запросов по спецификации OpenAPI.
+	ValidateRequests bool
+	// IdempotencyTTL — сколько хранится ответ на запрос с Idempotency-Key.
+	IdempotencyTTL time.Duration
+	RateLimit      RateLimitConfig
+	// MetricsToken открывает /metrics; задаётся только через окружение. Без него метрики отключены.
+	MetricsToken string
+	// TrustedProxies — адреса и подсети прокси, чьим X-Forwarded-For можно верить.
+	// Пустой список означает, что адрес клиента берётся из соединения.
+	TrustedProxies []string
+}
+
+// RateLimitConfig задаёт ограничение частоты запросов по группам маршрутов:
+// users, notes, boards, v1, webhooks и admin. Группа без лимита не ограничивается.
+type RateLimitConfig struct {
+	// Store выбирает хранилище корзин: postgres (общий бюджет для всех реплик) или memory.
+	Store  string
+	Groups map[string]RateLimit
+}
+
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	// Burst — сколько запросов можно сделать разом; по умолчанию равен Requests.
+	Burst int
+}
+
+type MailConfig struct {
+	// Driver выбирает реализацию почты: smtp, file или memory.
+	Driver       string
+	From         string
+	SMTPAddr     string
+	SMTPUser     string
+	SMTPPassword string
+	Dir          string
+	VerifyGrace  time.Duration
+}
+
+// OIDCConfig описывает внешнего провайдера входа. Пустой Issuer отключает вход через OIDC.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
 }
 
 const (
-	defaultHost = "0.0.0.0"
-	defaultPort = 8080
-	defaultDB   = "postgres://user.password@localhost:5432/notes?sslmode=disable"
+	defaultHost        = "0.0.0.0"
+	defaultPort        = 8080
+	defaultGRPCPort    = 9090
+	defaultDB          = "postgres://user.password@localhost:5432/notes?sslmode=disable"
+	defaultBaseURL     = "http://localhost:8080"
+	defaultMailDriver  = "file"
+	defaultMailFrom    = "no-reply@todolist.local"
+	defaultMailDir     = "storage/mail"
+	defaultVerifyGrace = 72 * time.Hour
+
+	defaultDeletionGrace  = 7 * 24 * time.Hour
+	defaultIdempotencyTTL = 24 * time.Hour
+
+	defaultRateLimitStore = "postgres"
+	defaultRateLimits     = "users=60/1m,notes=300/1m,boards=300/1m,v1=300/1m,webhooks=60/1m,admin=60/1m"
+	// rateLimitsOff отключает ограничение частоты запросов.
+	rateLimitsOff = "off"
 )
 
 func ReadConfig() (*Config, error) {
 	var cfg Config
+	var rateLimits, trustedProxies string
 	flag.StringVar(&cfg.Host, "host", defaultHost, "flag for configure host")
 	flag.IntVar(&cfg.Port, "port", defaultPort, "flag for configure port")
+	flag.IntVar(&cfg.GRPCPort, "grpc-port", defaultGRPCPort, "gRPC API port, 0 disables it")
 	flag.BoolVar(&cfg.Debug, "debug", false, "enable debug logger level")
 	flag.StringVar(&cfg.DBConnStr, "db", defaultDB, "flag for configure db connection string")
+	flag.StringVar(&cfg.BaseURL, "base-url", defaultBaseURL, "public URL used in links sent to users")
+	flag.StringVar(&cfg.Mail.Driver, "mailer", defaultMailDriver, "mail driver: smtp, file or memory")
+	flag.StringVar(&cfg.Mail.From, "mail-from", defaultMailFrom, "sender address for outgoing mail")
+	flag.StringVar(&cfg.Mail.SMTPAddr, "smtp-addr", "", "smtp server address host:port")
+	flag.StringVar(&cfg.Mail.Dir, "mail-dir", defaultMailDir, "directory for the file mail driver")
+	flag.StringVar(&cfg.OIDC.Issuer, "oidc-issuer", "", "OpenID Connect issuer URL")
+	flag.StringVar(&cfg.OIDC.ClientID, "oidc-client-id", "", "OpenID Connect client id")
+	flag.DurationVar(&cfg.Mail.VerifyGrace, "verify-grace", defaultVerifyGrace,
+		"how long unverified users may access notes")
+	flag.DurationVar(&cfg.DeletionGrace, "deletion-grace", defaultDeletionGrace,
+		"how long a requested account deletion can be cancelled")
+	flag.DurationVar(&cfg.IdempotencyTTL, "idempotency-ttl", defaultIdempotencyTTL,
+		"how long responses to requests with an Idempotency-Key are replayed")
+	flag.StringVar(&cfg.RateLimit.Store, "rate-limit-store", defaultRateLimitStore,
+		"rate limit store: postgres or memory")
+	flag.StringVar(&rateLimits, "rate-limits", defaultRateLimits,
+		"per route group limits as group=requests/period[:burst], comma separated, or off")
+	flag.BoolVar(&cfg.ValidateRequests, "validate-requests", false,
+		"reject requests that do not match the OpenAPI specification")
+	flag.StringVar(&trustedProxies, "trusted-proxies", "",
+		"comma separated proxy addresses or CIDRs whose X-Forwarded-For is trusted")
 
 	flag.Parse()
 
@@ -42,9 +141,185 @@ func ReadConfig() (*Config, error) {
 		cfg.Port = portInt
 	}
 
+	if cfg.GRPCPort == defaultGRPCPort {
+		grpcPort, err := strconv.Atoi(cmp.Or(os.Getenv("NOTES_GRPC_PORT"), strconv.Itoa(defaultGRPCPort)))
+		if err != nil {
+			return nil, err
+		}
+		cfg.GRPCPort = grpcPort
+	}
+
 	if cfg.DBConnStr == defaultDB {
 		cfg.DBConnStr = cmp.Or(os.Getenv("NOTES_DB"), defaultDB)
 	}
 
+	if err := readMailConfig(&cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.DeletionGrace == defaultDeletionGrace {
+		grace := cmp.Or(os.Getenv("NOTES_DELETION_GRACE"), defaultDeletionGrace.String())
+		graceDuration, err := time.ParseDuration(grace)
+		if err != nil {
+			return nil, err
+		}
+		cfg.DeletionGrace = graceDuration
+	}
+
+	if cfg.IdempotencyTTL == defaultIdempotencyTTL {
+		ttl := cmp.Or(os.Getenv("NOTES_IDEMPOTENCY_TTL"), defaultIdempotencyTTL.String())
+		ttlDuration, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, err
+		}
+		cfg.IdempotencyTTL = ttlDuration
+	}
+
+	if err := readRateLimitConfig(&cfg, rateLimits); err != nil {
+		return nil, err
+	}
+
+	if !cfg.ValidateRequests {
+		if validate := os.Getenv("NOTES_VALIDATE_REQUESTS"); validate != "" {
+			validateBool, err := strconv.ParseBool(validate)
+			if err != nil {
+				return nil, err
+			}
+			cfg.ValidateRequests = validateBool
+		}
+	}
+
+	proxies, err := ParseTrustedProxies(cmp.Or(trustedProxies, os.Getenv("NOTES_TRUSTED_PROXIES")))
+	if err != nil {
+		return nil, err
+	}
+	cfg.TrustedProxies = proxies
+
+	cfg.EncryptionKey = os.Getenv("NOTES_ENCRYPTION_KEY")
+	cfg.SigningKey = os.Getenv("NOTES_SIGNING_KEY")
+	cfg.MetricsToken = os.Getenv("NOTES_METRICS_TOKEN")
+	readOIDCConfig(&cfg)
+
+	if admins := os.Getenv("NOTES_ADMINS"); admins != "" {
+		for _, admin := range strings.Split(admins, ",") {
+			if admin = strings.TrimSpace(admin); admin != "" {
+				cfg.Admins = append(cfg.Admins, admin)
+			}
+		}
+	}
+
 	return &cfg, nil
 }
+
+func readMailConfig(cfg *Config) error {
+	if cfg.BaseURL == defaultBaseURL {
+		cfg.BaseURL = cmp.Or(os.Getenv("NOTES_BASE_URL"), defaultBaseURL)
+	}
+	if cfg.Mail.Driver == defaultMailDriver {
+		cfg.Mail.Driver = cmp.Or(os.Getenv("NOTES_MAILER"), defaultMailDriver)
+	}
+	if cfg.Mail.From == defaultMailFrom {
+		cfg.Mail.From = cmp.Or(os.Getenv("NOTES_MAIL_FROM"), defaultMailFrom)
+	}
+	if cfg.Mail.SMTPAddr == "" {
+		cfg.Mail.SMTPAddr = os.Getenv("NOTES_SMTP_ADDR")
+	}
+	if cfg.Mail.Dir == defaultMailDir {
+		cfg.Mail.Dir = cmp.Or(os.Getenv("NOTES_MAIL_DIR"), defaultMailDir)
+	}
+	// Учётные данные SMTP читаются только из окружения, чтобы не светить их в списке процессов.
+	cfg.Mail.SMTPUser = os.Getenv("NOTES_SMTP_USER")
+	cfg.Mail.SMTPPassword = os.Getenv("NOTES_SMTP_PASSWORD")
+
+	if cfg.Mail.VerifyGrace == defaultVerifyGrace {
+		grace := cmp.Or(os.Getenv("NOTES_VERIFY_GRACE"), defaultVerifyGrace.String())
+		graceDuration, err := time.ParseDuration(grace)
+		if err != nil {
+			return err
+		}
+		cfg.Mail.VerifyGrace = graceDuration
+	}
+	return nil
+}
+
+func readOIDCConfig(cfg *Config) {
+	if cfg.OIDC.Issuer == "" {
+		cfg.OIDC.Issuer = os.Getenv("NOTES_OIDC_ISSUER")
+	}
+	if cfg.OIDC.ClientID == "" {
+		cfg.OIDC.ClientID = os.Getenv("NOTES_OIDC_CLIENT_ID")
+	}
+	cfg.OIDC.ClientSecret = os.Getenv("NOTES_OIDC_CLIENT_SECRET")
+	cfg.OIDC.RedirectURL = cmp.Or(
+		os.Getenv("NOTES_OIDC_REDIRECT_URL"),
+		strings.TrimRight(cfg.BaseURL, "/")+"/users/oidc/callback",
+	)
+}
+
+func readRateLimitConfig(cfg *Config, rateLimits string) error {
+	if cfg.RateLimit.Store == defaultRateLimitStore {
+		cfg.RateLimit.Store = cmp.Or(os.Getenv("NOTES_RATE_LIMIT_STORE"), defaultRateLimitStore)
+	}
+	if rateLimits == defaultRateLimits {
+		rateLimits = cmp.Or(os.Getenv("NOTES_RATE_LIMITS"), defaultRateLimits)
+	}
+	groups, err := ParseRateLimits(rateLimits)
+	if err != nil {
+		return err
+	}
+	cfg.RateLimit.Groups = groups
+	return nil
+}
+
+// ParseRateLimits разбирает лимиты вида "notes=300/1m,users=60/1m:10"; "off" отключает их.
+func ParseRateLimits(spec string) (map[string]RateLimit, error) {
+	groups := make(map[string]RateLimit)
+	if spec = strings.TrimSpace(spec); spec == rateLimitsOff || spec == "" {
+		return groups, nil
+	}
+	for _, item := range strings.Split(spec, ",") {
+		group, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || group == "" {
+			return nil, fmt.Errorf("invalid rate limit %q: expected group=requests/period", item)
+		}
+		value, burst, hasBurst := strings.Cut(value, ":")
+		requests, period, ok := strings.Cut(value, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q: expected group=requests/period", item)
+		}
+
+		var limit RateLimit
+		var err error
+		if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: requests must be a positive number", item)
+		}
+		if limit.Per, err = time.ParseDuration(period); err != nil || limit.Per <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: period must be a positive duration", item)
+		}
+		if hasBurst {
+			if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+				return nil, fmt.Errorf("invalid rate limit %q: burst must be a positive number", item)
+			}
+		}
+		groups[group] = limit
+	}
+	return groups, nil
+}
+
+// ParseTrustedProxies разбирает список вида "10.0.0.1, 172.16.0.0/12".
+func ParseTrustedProxies(spec string) ([]string, error) {
+	var proxies []string
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if _, err := netip.ParsePrefix(item); err != nil {
+			if _, err = netip.ParseAddr(item); err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: expected an address or CIDR", item)
+			}
+		}
+		proxies = append(proxies, item)
+	}
+	return proxies, nil
+}
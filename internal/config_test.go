@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,10 +32,17 @@ func TestReadConfig(t *testing.T) {
 			env: nil,
 			want: want{
 				cfg: Config{
-					Host:      "23.233.43.9",
-					Port:      777,
-					Debug:     false,
-					DBConnStr: "mockDbDSN",
+					Host:           "23.233.43.9",
+					Port:           777,
+					GRPCPort:       defaultGRPCPort,
+					Debug:          false,
+					DBConnStr:      "mockDbDSN",
+					BaseURL:        defaultBaseURL,
+					Mail:           defaultMailConfig(),
+					OIDC:           OIDCConfig{RedirectURL: defaultBaseURL + "/users/oidc/callback"},
+					DeletionGrace:  defaultDeletionGrace,
+					IdempotencyTTL: defaultIdempotencyTTL,
+					RateLimit:      defaultRateLimitConfig(),
 				},
 				err: nil,
 			},
@@ -49,10 +57,80 @@ func TestReadConfig(t *testing.T) {
 			},
 			want: want{
 				cfg: Config{
-					Host:      "73.133.73.97",
-					Port:      1111,
-					Debug:     true,
+					Host:           "73.133.73.97",
+					Port:           1111,
+					GRPCPort:       defaultGRPCPort,
+					Debug:          true,
+					DBConnStr:      "mockDbDSN",
+					BaseURL:        defaultBaseURL,
+					Mail:           defaultMailConfig(),
+					OIDC:           OIDCConfig{RedirectURL: defaultBaseURL + "/users/oidc/callback"},
+					DeletionGrace:  defaultDeletionGrace,
+					IdempotencyTTL: defaultIdempotencyTTL,
+					RateLimit:      defaultRateLimitConfig(),
+				},
+				err: nil,
+			},
+		},
+		{
+			name:  "mail config from envs",
+			flags: []string{"test", "--mailer", "smtp"},
+			env: func() {
+				t.Setenv("NOTES_HOST", "10.0.0.1")
+				t.Setenv("NOTES_PORT", "2222")
+				t.Setenv("NOTES_GRPC_PORT", "0")
+				t.Setenv("NOTES_DB", "mockDbDSN")
+				t.Setenv("NOTES_BASE_URL", "https://notes.example.com")
+				t.Setenv("NOTES_SMTP_ADDR", "smtp.example.com:587")
+				t.Setenv("NOTES_SMTP_USER", "mailer")
+				t.Setenv("NOTES_SMTP_PASSWORD", "secret")
+				t.Setenv("NOTES_VERIFY_GRACE", "24h")
+				t.Setenv("NOTES_ADMINS", "root@example.com, ops@example.com")
+				t.Setenv("NOTES_ENCRYPTION_KEY", "totp-key")
+				t.Setenv("NOTES_OIDC_ISSUER", "https://id.example.com")
+				t.Setenv("NOTES_OIDC_CLIENT_ID", "notes")
+				t.Setenv("NOTES_OIDC_CLIENT_SECRET", "oidc-secret")
+				t.Setenv("NOTES_DELETION_GRACE", "0s")
+				t.Setenv("NOTES_VALIDATE_REQUESTS", "true")
+				t.Setenv("NOTES_IDEMPOTENCY_TTL", "1h")
+				t.Setenv("NOTES_RATE_LIMIT_STORE", "memory")
+				t.Setenv("NOTES_RATE_LIMITS", "notes=10/1s:20")
+				t.Setenv("NOTES_TRUSTED_PROXIES", "10.0.0.2, 172.16.0.0/12")
+				t.Setenv("NOTES_METRICS_TOKEN", "scrape")
+			},
+			want: want{
+				cfg: Config{
+					Host:      "10.0.0.1",
+					Port:      2222,
+					GRPCPort:  0,
 					DBConnStr: "mockDbDSN",
+					BaseURL:   "https://notes.example.com",
+					Mail: MailConfig{
+						Driver:       "smtp",
+						From:         defaultMailFrom,
+						SMTPAddr:     "smtp.example.com:587",
+						SMTPUser:     "mailer",
+						SMTPPassword: "secret",
+						Dir:          defaultMailDir,
+						VerifyGrace:  24 * time.Hour,
+					},
+					Admins:        []string{"root@example.com", "ops@example.com"},
+					EncryptionKey: "totp-key",
+					OIDC: OIDCConfig{
+						Issuer:       "https://id.example.com",
+						ClientID:     "notes",
+						ClientSecret: "oidc-secret",
+						RedirectURL:  "https://notes.example.com/users/oidc/callback",
+					},
+					DeletionGrace:    0,
+					ValidateRequests: true,
+					IdempotencyTTL:   time.Hour,
+					RateLimit: RateLimitConfig{
+						Store:  "memory",
+						Groups: map[string]RateLimit{"notes": {Requests: 10, Per: time.Second, Burst: 20}},
+					},
+					MetricsToken:   "scrape",
+					TrustedProxies: []string{"10.0.0.2", "172.16.0.0/12"},
 				},
 				err: nil,
 			},
@@ -96,3 +174,59 @@ func TestReadConfig(t *testing.T) {
 		})
 	}
 }
+
+func defaultMailConfig() MailConfig {
+	return MailConfig{
+		Driver:      defaultMailDriver,
+		From:        defaultMailFrom,
+		Dir:         defaultMailDir,
+		VerifyGrace: defaultVerifyGrace,
+	}
+}
+
+func defaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Store: defaultRateLimitStore,
+		Groups: map[string]RateLimit{
+			"users":    {Requests: 60, Per: time.Minute},
+			"notes":    {Requests: 300, Per: time.Minute},
+			"boards":   {Requests: 300, Per: time.Minute},
+			"v1":       {Requests: 300, Per: time.Minute},
+			"webhooks": {Requests: 60, Per: time.Minute},
+			"admin":    {Requests: 60, Per: time.Minute},
+		},
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	groups, err := ParseRateLimits(" users=5/1s:10, notes=100/1h")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]RateLimit{
+		"users": {Requests: 5, Per: time.Second, Burst: 10},
+		"notes": {Requests: 100, Per: time.Hour},
+	}, groups)
+
+	groups, err = ParseRateLimits(rateLimitsOff)
+	assert.NoError(t, err)
+	assert.Empty(t, groups)
+
+	for _, spec := range []string{"notes", "notes=10", "notes=0/1m", "notes=10/0s", "notes=10/1m:x", "=10/1m"} {
+		_, err = ParseRateLimits(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies(" 10.0.0.1, 172.16.0.0/12,::1 ")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "172.16.0.0/12", "::1"}, proxies)
+
+	proxies, err = ParseTrustedProxies("")
+	assert.NoError(t, err)
+	assert.Nil(t, proxies)
+
+	for _, spec := range []string{"proxy.local", "10.0.0.0/33", "10.0.0"} {
+		_, err = ParseTrustedProxies(spec)
+		assert.Error(t, err, spec)
+	}
+}
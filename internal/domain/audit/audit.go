@@ -0,0 +1,175 @@
+// Package audit описывает журнал событий безопасности. Записи связаны в цепочку
+// хэшей: изменение или удаление любой записи обнаруживается при проверке.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+var (
+	ErrChainBroken   = errors.New("audit chain broken")
+	ErrNotConfigured = errors.New("audit log not configured")
+)
+
+type EventType string
+
+const (
+	EventRegister          EventType = "user.register"
+	EventLogin             EventType = "auth.login"
+	EventLoginSecondFactor EventType = "auth.login_2fa"
+	EventLoginExternal     EventType = "auth.login_oidc"
+	EventPasswordReset     EventType = "auth.password_reset"
+	EventTOTPEnabled       EventType = "auth.2fa_enable"
+	EventTOTPDisabled      EventType = "auth.2fa_disable"
+	EventTokenCreated      EventType = "token.create"
+	EventTokenRevoked      EventType = "token.revoke"
+	EventSessionRevoked    EventType = "session.revoke"
+	EventDeletionRequested EventType = "user.deletion_request"
+	EventDeletionCanceled  EventType = "user.deletion_cancel"
+	EventUserPurged        EventType = "user.purge"
+	EventUserDeleted       EventType = "admin.user_delete"
+	EventUserUnlocked      EventType = "admin.user_unlock"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+	// OutcomePending — пароль верен, вход ждёт второго фактора.
+	OutcomePending Outcome = "pending"
+)
+
+// OutcomeOf переводит результат операции в исход события.
+func OutcomeOf(err error) Outcome {
+	if err != nil {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// Event — запись журнала. Seq, PrevHash и Hash заполняет хранилище при добавлении.
+type Event struct {
+	Seq       int64     `json:"seq"`
+	Type      EventType `json:"type"`
+	ActorUID  string    `json:"actor_uid,omitempty"`
+	Target    string    `json:"target,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	Outcome   Outcome   `json:"outcome"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	PrevHash  string    `json:"prev_hash"`
+	Hash      string    `json:"hash"`
+}
+
+// ComputeHash считает SHA-256 от всех полей события, кроме самого Hash.
+func (e Event) ComputeHash() string {
+	// Массив JSON однозначно разделяет поля, в отличие от склейки через разделитель.
+	payload, _ := json.Marshal([]any{
+		e.Seq, e.PrevHash, e.Type, e.ActorUID, e.Target, e.IP, e.Outcome, e.Detail,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain продолжает цепочку после prev; для первой записи prev — пустое событие.
+// Время округляется до микросекунд, чтобы хэш сходился после чтения из PostgreSQL.
+func Chain(prev, event Event) Event {
+	event.Seq = prev.Seq + 1
+	event.PrevHash = prev.Hash
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	event.CreatedAt = event.CreatedAt.UTC().Truncate(time.Microsecond)
+	event.Hash = event.ComputeHash()
+	return event
+}
+
+// Verifier проверяет записи по одной в порядке возрастания Seq. Цепочка должна начинаться
+// с записи 1, иначе удаление первых записей прошло бы незамеченным. Tail задают для
+// журналов, которые хранят только хвост (кольцевой буфер): там первая проверенная запись
+// принимается как начало цепочки.
+type Verifier struct {
+	Tail    bool
+	prev    *Event
+	checked int
+}
+
+func (v *Verifier) Check(event Event) error {
+	if event.Hash != event.ComputeHash() {
+		return fmt.Errorf("%w: event %d was modified", ErrChainBroken, event.Seq)
+	}
+	switch {
+	case v.prev == nil && !v.Tail && event.Seq != 1:
+		return fmt.Errorf("%w: events 1-%d are missing", ErrChainBroken, event.Seq-1)
+	case v.prev == nil && event.Seq == 1 && event.PrevHash != ``:
+		return fmt.Errorf("%w: event 1 has a predecessor", ErrChainBroken)
+	case v.prev != nil && event.Seq != v.prev.Seq+1:
+		return fmt.Errorf("%w: events %d-%d are missing", ErrChainBroken, v.prev.Seq+1, event.Seq-1)
+	case v.prev != nil && event.PrevHash != v.prev.Hash:
+		return fmt.Errorf("%w: event %d does not follow event %d", ErrChainBroken, event.Seq, v.prev.Seq)
+	}
+	v.prev = &event
+	v.checked++
+	return nil
+}
+
+// Report — результат проверки цепочки.
+type Report struct {
+	Valid   bool   `json:"valid"`
+	Checked int    `json:"checked"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (v *Verifier) Report(err error) Report {
+	report := Report{Valid: err == nil, Checked: v.checked}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report
+}
+
+// Filter отбирает события для GET /admin/audit; пустые поля не ограничивают выборку.
+type Filter struct {
+	Type     EventType `form:"type"`
+	ActorUID string    `form:"actor"`
+	Target   string    `form:"target"`
+	IP       string    `form:"ip"`
+	Outcome  Outcome   `form:"outcome"`
+	Since    time.Time `form:"since"   time_format:"2006-01-02T15:04:05Z07:00"`
+	Until    time.Time `form:"until"   time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit    int       `form:"limit"`
+}
+
+// PageSize возвращает Limit в допустимых границах.
+func (f Filter) PageSize() int {
+	if f.Limit <= 0 {
+		return DefaultLimit
+	}
+	return min(f.Limit, MaxLimit)
+}
+
+func (f Filter) Match(e Event) bool {
+	switch {
+	case f.Type != `` && e.Type != f.Type,
+		f.ActorUID != `` && e.ActorUID != f.ActorUID,
+		f.Target != `` && e.Target != f.Target,
+		f.IP != `` && e.IP != f.IP,
+		f.Outcome != `` && e.Outcome != f.Outcome,
+		!f.Since.IsZero() && e.CreatedAt.Before(f.Since),
+		!f.Until.IsZero() && !e.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chain(t *testing.T, n int) []Event {
+	t.Helper()
+	events := make([]Event, 0, n)
+	prev := Event{}
+	for range n {
+		prev = Chain(prev, Event{Type: EventLogin, ActorUID: "uid-1", Outcome: OutcomeSuccess})
+		events = append(events, prev)
+	}
+	return events
+}
+
+func verify(events []Event) error {
+	var verifier Verifier
+	for _, event := range events {
+		if err := verifier.Check(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestChain(t *testing.T) {
+	events := chain(t, 3)
+
+	assert.Equal(t, int64(1), events[0].Seq)
+	assert.Empty(t, events[0].PrevHash)
+	assert.Equal(t, events[0].Hash, events[1].PrevHash)
+	assert.Equal(t, events[0].CreatedAt, events[0].CreatedAt.Truncate(time.Microsecond))
+	require.NoError(t, verify(events))
+}
+
+func TestVerifierDetectsTampering(t *testing.T) {
+	t.Run("modified event", func(t *testing.T) {
+		events := chain(t, 3)
+		events[1].Outcome = OutcomeFailure
+		assert.ErrorIs(t, verify(events), ErrChainBroken)
+	})
+
+	t.Run("rehashed event", func(t *testing.T) {
+		events := chain(t, 3)
+		events[1].ActorUID = "uid-2"
+		events[1].Hash = events[1].ComputeHash()
+		assert.ErrorIs(t, verify(events), ErrChainBroken)
+	})
+
+	t.Run("deleted event", func(t *testing.T) {
+		events := chain(t, 3)
+		assert.ErrorIs(t, verify([]Event{events[0], events[2]}), ErrChainBroken)
+	})
+
+	t.Run("deleted head", func(t *testing.T) {
+		events := chain(t, 3)
+		assert.ErrorIs(t, verify(events[1:]), ErrChainBroken)
+	})
+
+	t.Run("tail of the chain", func(t *testing.T) {
+		events := chain(t, 3)
+		verifier := Verifier{Tail: true}
+		for _, event := range events[1:] {
+			require.NoError(t, verifier.Check(event))
+		}
+	})
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	event := Event{Type: EventLogin, ActorUID: "uid-1", IP: "10.0.0.1", Outcome: OutcomeFailure, CreatedAt: now}
+
+	assert.True(t, Filter{}.Match(event))
+	assert.True(t, Filter{Type: EventLogin, Outcome: OutcomeFailure, Since: now.Add(-time.Minute)}.Match(event))
+	assert.False(t, Filter{ActorUID: "uid-2"}.Match(event))
+	assert.False(t, Filter{Until: now}.Match(event))
+	assert.Equal(t, DefaultLimit, Filter{}.PageSize())
+	assert.Equal(t, MaxLimit, Filter{Limit: MaxLimit + 1}.PageSize())
+}
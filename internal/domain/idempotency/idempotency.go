@@ -0,0 +1,74 @@
+// Package idempotency описывает повтор запросов с заголовком Idempotency-Key: первый
+// запрос выполняется, а его ответ сохраняется и отдаётся повторам с тем же ключом.
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	Header = "Idempotency-Key"
+	// ReplayedHeader отмечает ответ, взятый из сохранённого.
+	ReplayedHeader = "Idempotent-Replayed"
+	MaxKeyLength   = 255
+	DefaultTTL     = 24 * time.Hour
+	// LockTimeout — сколько ключ считается занятым выполняющимся запросом. Если процесс
+	// упадёт, не сохранив ответ, ключ освободится по истечении этого срока.
+	LockTimeout = time.Minute
+)
+
+var (
+	ErrKeyTooLong = errors.New("idempotency key is too long")
+	ErrKeyReused  = errors.New("idempotency key was already used with a different request body")
+	ErrInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// StoredHeaders — заголовки ответа, которые сохраняются и повторяются вместе с телом.
+// Authorization сюда не входит: выданный JWT не должен лежать в хранилище ключей,
+// поэтому повтор регистрации приходит без токена.
+var StoredHeaders = []string{"Content-Type", "Location"} //nolint:gochecknoglobals // its ok
+
+// Record — ключ и ответ на запрос с ним. Пока Status равен 0, запрос выполняется.
+type Record struct {
+	Key         string
+	Fingerprint string
+	Status      int
+	Headers     map[string]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r Record) Completed() bool {
+	return r.Status != 0
+}
+
+func (r Record) Expired(now time.Time) bool {
+	return !r.ExpiresAt.After(now)
+}
+
+// Replayable сообщает, можно ли отдать сохранённый ответ на запрос с телом fingerprint.
+func (r Record) Replayable(fingerprint string) error {
+	if r.Fingerprint != fingerprint {
+		return ErrKeyReused
+	}
+	if !r.Completed() {
+		return ErrInProgress
+	}
+	return nil
+}
+
+// Cacheable сообщает, сохранять ли ответ: после ошибки сервера запрос можно повторить заново.
+func Cacheable(status int) bool {
+	return status < http.StatusInternalServerError
+}
+
+// Fingerprint считает отпечаток тела запроса, с которым сравниваются повторы.
+func Fingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,7 @@
+package mail
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,48 @@
+package notes
+
+import "time"
+
+const DefaultBoard = "default"
+
+type BoardColumn struct {
+	Status string               `json:"status"`
+	Count  int                  `json:"count"`
+	Notes  []NoteResponseFormat `json:"notes"`
+}
+
+type Board struct {
+	Name    string        `json:"name"`
+	Total   int           `json:"total"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// BoardMoveRequest переносит заметку в колонку Status между соседями After и Before.
+type BoardMoveRequest struct {
+	Status string `json:"status"`
+	After  string `json:"after"`
+	Before string `json:"before"`
+}
+
+// NewBoard раскладывает заметки по колонкам статусов, сохраняя их порядок.
+// Время создания заметок выводится в часовом поясе loc.
+func NewBoard(name string, notesSlice []Note, loc *time.Location) Board {
+	columns := make([]BoardColumn, len(Statuses))
+	for i, status := range Statuses {
+		columns[i] = BoardColumn{Status: status, Notes: []NoteResponseFormat{}}
+	}
+
+	for _, note := range notesSlice {
+		if !note.Status.Valid() {
+			continue
+		}
+		column := &columns[note.Status]
+		column.Notes = append(column.Notes, NoteResponse(note, loc))
+		column.Count++
+	}
+
+	board := Board{Name: name, Columns: columns}
+	for _, column := range columns {
+		board.Total += column.Count
+	}
+	return board
+}
@@ -0,0 +1,39 @@
+package notes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoard(t *testing.T) {
+	board := NewBoard(DefaultBoard, []Note{
+		{NID: "1", Status: New, Position: "a"},
+		{NID: "2", Status: Active, Position: "b"},
+		{NID: "3", Status: New, Position: "c"},
+		{NID: "4", Status: -1},
+	}, time.UTC)
+
+	assert.Equal(t, DefaultBoard, board.Name)
+	assert.Equal(t, 3, board.Total)
+	require.Len(t, board.Columns, len(Statuses))
+
+	assert.Equal(t, "New", board.Columns[New].Status)
+	assert.Equal(t, 2, board.Columns[New].Count)
+	assert.Equal(t, "1", board.Columns[New].Notes[0].NID)
+	assert.Equal(t, "3", board.Columns[New].Notes[1].NID)
+
+	assert.Equal(t, 1, board.Columns[Active].Count)
+	assert.Empty(t, board.Columns[Inactive].Notes)
+}
+
+func TestNewBoardLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	created := time.Date(2025, 5, 1, 22, 30, 0, 0, time.UTC)
+
+	board := NewBoard(DefaultBoard, []Note{{NID: "1", Status: New, CreatedAt: created}}, loc)
+
+	assert.Equal(t, "2025-05-02T03:30:00+05:00", board.Columns[New].Notes[0].CreatedAt)
+}
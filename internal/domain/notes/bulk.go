@@ -0,0 +1,61 @@
+package notes
+
+const MaxBulkOperations = 500
+
+type BulkAction string
+
+const (
+	BulkCreate BulkAction = "create"
+	BulkUpdate BulkAction = "update"
+	BulkDelete BulkAction = "delete"
+	BulkStatus BulkAction = "status"
+)
+
+type BulkMode string
+
+const (
+	// BulkAtomic применяет пакет целиком или не применяет ничего.
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort применяет каждую операцию независимо.
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// BulkOperation - одна операция пакета.
+// Для create и update используется Note, для status - Status, для update, delete и status - NID.
+type BulkOperation struct {
+	Action BulkAction `json:"action"`
+	NID    string     `json:"nid"`
+	Note   Note       `json:"note"`
+	Status string     `json:"status"`
+}
+
+type BulkRequest struct {
+	Mode       BulkMode        `json:"mode"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+type BulkResult struct {
+	Index  int        `json:"index"`
+	Action BulkAction `json:"action"`
+	NID    string     `json:"nid"`
+	Error  string     `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Mode      BulkMode     `json:"mode"`
+	Applied   bool         `json:"applied"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+func ParseBulkMode(mode string) (BulkMode, error) {
+	switch BulkMode(mode) {
+	case ``, BulkAtomic:
+		return BulkAtomic, nil
+	case BulkBestEffort:
+		return BulkBestEffort, nil
+	default:
+		return ``, ErrInvalidBulkMode
+	}
+}
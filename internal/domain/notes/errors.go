@@ -6,4 +6,20 @@ var (
 	ErrNoteNotFound      = errors.New("note not found")
 	ErrNoNotesAvailable  = errors.New("no notes available")
 	ErrNoteAlreadyExists = errors.New("note already exists")
+	ErrInvalidPosition   = errors.New("invalid position")
+	ErrInvalidSort       = errors.New("invalid sort")
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrBoardNotFound     = errors.New("board not found")
+	ErrInvalidBulkMode   = errors.New("invalid bulk mode")
+	ErrInvalidBulkAction = errors.New("invalid bulk action")
+	ErrBulkTooLarge      = errors.New("too many bulk operations")
+	ErrBulkEmpty         = errors.New("no bulk operations")
+	ErrBulkFailed        = errors.New("bulk operations failed")
+	ErrEmptyTitle        = errors.New("empty title")
+	ErrInvalidPriority   = errors.New("invalid priority")
+
+	ErrInvalidImportFormat = errors.New("invalid import format")
+	ErrInvalidMapping      = errors.New("invalid column mapping")
+	ErrInvalidImport       = errors.New("invalid import data")
+	ErrInvalidExportFormat = errors.New("invalid export format")
 )
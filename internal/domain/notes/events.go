@@ -0,0 +1,95 @@
+package notes
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventCreated       EventType = "note.created"
+	EventUpdated       EventType = "note.updated"
+	EventDeleted       EventType = "note.deleted"
+	EventStatusChanged EventType = "note.status_changed"
+)
+
+//nolint:gochecknoglobals // its ok
+var EventTypes = []EventType{EventCreated, EventUpdated, EventDeleted, EventStatusChanged}
+
+func (t EventType) Valid() bool {
+	return slices.Contains(EventTypes, t)
+}
+
+var (
+	// ErrEventsExpired — событий после Last-Event-ID уже нет в истории, клиенту нужно перечитать заметки.
+	ErrEventsExpired = errors.New("events after last event id are no longer available")
+	// ErrSubscriberLagging — подписчик не успевал читать события и был отключён.
+	ErrSubscriberLagging = errors.New("subscriber is too slow")
+)
+
+// Event — изменение заметки. ID назначает шина, он растёт монотонно.
+type Event struct {
+	ID   uint64
+	Type EventType
+	NID  string
+	// UID — владелец заметки; у заметок общей доски он пуст.
+	UID  string
+	Note Note
+	// PrevStatus заполняется только для EventStatusChanged.
+	PrevStatus Status
+	At         time.Time
+}
+
+// VisibleTo сообщает, может ли пользователь uid видеть событие: заметки общей доски видны всем,
+// личные — только владельцу, как в /api/v1.
+func (e Event) VisibleTo(uid string) bool {
+	return e.UID == `` || e.UID == uid
+}
+
+// ChangeEvent выбирает тип события изменения: смена статуса важнее прочих правок.
+func ChangeEvent(before, after Note) Event {
+	event := Event{Type: EventUpdated, NID: after.NID, UID: after.UID, Note: after}
+	if before.Status != after.Status {
+		event.Type = EventStatusChanged
+		event.PrevStatus = before.Status
+	}
+	return event
+}
+
+// Subscription — подписка на события. Канал Events закрывается при Close или отключении
+// отстающего подписчика; причину сообщает Err.
+type Subscription interface {
+	Events() <-chan Event
+	Err() error
+	Close()
+}
+
+// EventPayload — событие в том виде, в каком оно уходит клиентам потока.
+type EventPayload struct {
+	ID         uint64              `json:"id,omitempty"`
+	Type       EventType           `json:"type"`
+	NID        string              `json:"nid"`
+	Note       *NoteResponseFormat `json:"note,omitempty"`
+	PrevStatus string              `json:"prev_status,omitempty"`
+	At         string              `json:"at"`
+}
+
+// NewEventPayload форматирует событие; время приводится к часовому поясу loc.
+func NewEventPayload(event Event, loc *time.Location) EventPayload {
+	payload := EventPayload{
+		ID:   event.ID,
+		Type: event.Type,
+		NID:  event.NID,
+		At:   event.At.In(loc).Format(time.RFC3339),
+	}
+	if event.Type != EventDeleted {
+		note := NoteResponse(event.Note, loc)
+		payload.Note = &note
+	}
+	if event.Type == EventStatusChanged {
+		payload.PrevStatus = event.PrevStatus.String()
+	}
+	return payload
+}
@@ -0,0 +1,39 @@
+package notes
+
+import (
+	"strings"
+	"time"
+)
+
+// ExportVersion - версия схемы JSON-экспорта, которую понимает импорт.
+const ExportVersion = 1
+
+type ExportFormat string
+
+const (
+	ExportCSV      ExportFormat = "csv"
+	ExportMarkdown ExportFormat = "md"
+	ExportICS      ExportFormat = "ics"
+	ExportJSON     ExportFormat = "json"
+)
+
+type ExportDocument struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Notes      []Note    `json:"notes"`
+}
+
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(format)) {
+	case ExportCSV:
+		return ExportCSV, nil
+	case ExportMarkdown, "markdown":
+		return ExportMarkdown, nil
+	case ExportICS:
+		return ExportICS, nil
+	case ExportJSON, ``:
+		return ExportJSON, nil
+	default:
+		return ``, ErrInvalidExportFormat
+	}
+}
@@ -0,0 +1,83 @@
+package notes
+
+import "strings"
+
+type ImportFormat string
+
+const (
+	ImportCSV      ImportFormat = "csv"
+	ImportJSON     ImportFormat = "json"
+	ImportMarkdown ImportFormat = "md"
+)
+
+// Поля заметки, которые можно сопоставить с колонками CSV.
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldStatus      = "status"
+	FieldPriority    = "priority"
+)
+
+type ImportOptions struct {
+	// UID - владелец создаваемых заметок; дубликаты ищутся только среди его заметок.
+	UID    string
+	Format ImportFormat
+	DryRun bool
+	// Mapping сопоставляет поле заметки с заголовком колонки CSV.
+	Mapping map[string]string
+}
+
+type ImportItem struct {
+	Line      int    `json:"line"`
+	NID       string `json:"nid,omitempty"`
+	Title     string `json:"title"`
+	Status    string `json:"status,omitempty"`
+	Duplicate bool   `json:"duplicate"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Format     ImportFormat `json:"format"`
+	DryRun     bool         `json:"dry_run"`
+	Created    int          `json:"created"`
+	Duplicates int          `json:"duplicates"`
+	Failed     int          `json:"failed"`
+	Items      []ImportItem `json:"items"`
+}
+
+func ParseImportFormat(format string) (ImportFormat, error) {
+	switch ImportFormat(strings.ToLower(format)) {
+	case ImportCSV:
+		return ImportCSV, nil
+	case ImportJSON:
+		return ImportJSON, nil
+	case ImportMarkdown, "markdown":
+		return ImportMarkdown, nil
+	default:
+		return ``, ErrInvalidImportFormat
+	}
+}
+
+// ParseColumnMapping разбирает сопоставление вида "title:Name,status:State".
+func ParseColumnMapping(mapping string) (map[string]string, error) {
+	columns := make(map[string]string)
+	if strings.TrimSpace(mapping) == `` {
+		return columns, nil
+	}
+
+	for _, pair := range strings.Split(mapping, ",") {
+		field, column, ok := strings.Cut(pair, ":")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || column == `` {
+			return nil, ErrInvalidMapping
+		}
+		switch field {
+		case FieldTitle, FieldDescription, FieldStatus, FieldPriority:
+			columns[field] = column
+		default:
+			return nil, ErrInvalidMapping
+		}
+	}
+	return columns, nil
+}
@@ -4,27 +4,69 @@ import "time"
 
 type Note struct {
 	NID         string    `json:"nid"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      Status    `json:"status"`
+	Title       string    `json:"title"       validate:"notblank,max=200"`
+	Description string    `json:"description" validate:"max=10000"`
+	Status      Status    `json:"status"      validate:"valid"`
+	Priority    Priority  `json:"priority"    validate:"valid"`
+	Position    string    `json:"position"`
 	CreatedAt   time.Time `json:"created_at"`
-	UID         string    `json:"uid"`
+	UID         string    `json:"uid"         validate:"isdefault"`
 	Deleted     bool      `json:"deleted"`
 }
+
+// MoveRequest описывает перемещение заметки между соседями.
+// After - заметка, которая окажется перед перемещаемой, Before - после неё.
+type MoveRequest struct {
+	After  string `json:"after"`
+	Before string `json:"before"`
+}
+
 type NoteResponseFormat struct {
+	NID         string `json:"nid"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Status      string `json:"status"`
+	Priority    string `json:"priority"`
+	Position    string `json:"position"`
 	CreatedAt   string `json:"created_at"`
 	UID         string `json:"uid"`
 }
 
-func NoteResponse(note Note) NoteResponseFormat {
+// NoteResponse форматирует заметку для ответа; время приводится к часовому поясу loc.
+func NoteResponse(note Note, loc *time.Location) NoteResponseFormat {
 	return NoteResponseFormat{
+		NID:         note.NID,
 		Title:       note.Title,
 		Description: note.Description,
 		Status:      note.Status.String(),
-		CreatedAt:   note.CreatedAt.Format(time.RFC3339),
+		Priority:    note.Priority.String(),
+		Position:    note.Position,
+		CreatedAt:   note.CreatedAt.In(loc).Format(time.RFC3339),
 		UID:         note.UID,
 	}
 }
+
+// NotePatch — частичное изменение заметки: nil-поля не меняются.
+type NotePatch struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Status      *Status   `json:"status"`
+	Priority    *Priority `json:"priority"`
+}
+
+// Apply возвращает note с изменёнными полями патча.
+func (p NotePatch) Apply(note Note) Note {
+	if p.Title != nil {
+		note.Title = *p.Title
+	}
+	if p.Description != nil {
+		note.Description = *p.Description
+	}
+	if p.Status != nil {
+		note.Status = *p.Status
+	}
+	if p.Priority != nil {
+		note.Priority = *p.Priority
+	}
+	return note
+}
@@ -0,0 +1,35 @@
+package notes
+
+type Priority int
+
+const (
+	Low = iota
+	Medium
+	High
+	Urgent
+)
+
+var Priorities = []string{"Low", "Medium", "High", "Urgent"} //nolint:gochecknoglobals // its ok
+
+func (p Priority) String() string {
+	return Priorities[p]
+}
+
+func (p Priority) Valid() bool {
+	return p >= 0 && int(p) < len(Priorities)
+}
+
+func ParsePriority(priority string) Priority {
+	switch priority {
+	case "Low":
+		return Low
+	case "Medium":
+		return Medium
+	case "High":
+		return High
+	case "Urgent":
+		return Urgent
+	default:
+		return -1
+	}
+}
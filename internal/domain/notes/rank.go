@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	rankDigits       = "0123456789abcdefghijklmnopqrstuvwxyz"
+	initialRankWidth = 13
+)
+
+// RankBetween возвращает лексикографический ранг строго между lower и upper.
+// Пустой lower означает начало списка, пустой upper - конец списка.
+// Соседние заметки не перенумеровываются, поэтому перемещение стоит O(1).
+func RankBetween(lower, upper string) (string, error) {
+	if !validRank(lower) || !validRank(upper) {
+		return ``, ErrInvalidPosition
+	}
+	if upper != `` && lower >= upper {
+		return ``, ErrInvalidPosition
+	}
+
+	var rank strings.Builder
+	bounded := upper != ``
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(lower) {
+			lo = strings.IndexByte(rankDigits, lower[i])
+		}
+		hi := len(rankDigits)
+		if bounded {
+			hi = strings.IndexByte(rankDigits, upper[i])
+		}
+
+		switch {
+		case hi-lo > 1:
+			rank.WriteByte(rankDigits[(lo+hi)/2]) //nolint:mnd // середина интервала
+			return rank.String(), nil
+		case hi-lo == 1:
+			bounded = false
+		}
+		rank.WriteByte(rankDigits[lo])
+	}
+}
+
+func validRank(rank string) bool {
+	for i := range len(rank) {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(rank, "0")
+}
+
+// InitialRank выдаёт ранг новой заметке так, чтобы она оказалась в конце списка.
+func InitialRank(t time.Time) string {
+	rank := strconv.FormatInt(t.UnixNano(), len(rankDigits))
+	return strings.Repeat("0", initialRankWidth-len(rank)) + rank + "i"
+}
@@ -0,0 +1,92 @@
+package notes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name  string
+		lower string
+		upper string
+	}{
+		{"empty list", "", ""},
+		{"to the top", "", "i"},
+		{"to the bottom", "i", ""},
+		{"between neighbours", "5", "6"},
+		{"lower is prefix of upper", "a", "a5"},
+		{"tight top", "", "01"},
+		{"max digit", "z", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, err := RankBetween(tt.lower, tt.upper)
+			require.NoError(t, err)
+			assert.Greater(t, rank, tt.lower)
+			if tt.upper != "" {
+				assert.Less(t, rank, tt.upper)
+			}
+		})
+	}
+
+	t.Run("invalid bounds", func(t *testing.T) {
+		_, err := RankBetween("b", "a")
+		assert.ErrorIs(t, err, ErrInvalidPosition)
+
+		_, err = RankBetween("a", "a")
+		assert.ErrorIs(t, err, ErrInvalidPosition)
+
+		_, err = RankBetween("A", "")
+		assert.ErrorIs(t, err, ErrInvalidPosition)
+	})
+
+	t.Run("repeated inserts stay ordered", func(t *testing.T) {
+		lower, upper := "", ""
+		for range 100 {
+			rank, err := RankBetween(lower, upper)
+			require.NoError(t, err)
+			if upper != "" {
+				require.Less(t, rank, upper)
+			}
+			require.Greater(t, rank, lower)
+			upper = rank
+		}
+	})
+}
+
+func TestInitialRank(t *testing.T) {
+	now := time.Now()
+	first := InitialRank(now)
+	second := InitialRank(now.Add(time.Millisecond))
+
+	assert.Less(t, first, second)
+
+	rank, err := RankBetween(first, second)
+	require.NoError(t, err)
+	assert.Greater(t, rank, first)
+	assert.Less(t, rank, second)
+}
+
+func TestPriorityOperations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Priority
+	}{
+		{"Low", Low},
+		{"Medium", Medium},
+		{"High", High},
+		{"Urgent", Urgent},
+		{"Invalid", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParsePriority(tt.input))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package notes
+
+type SortBy string
+
+const (
+	SortDefault  SortBy = ""
+	SortPriority SortBy = "priority"
+	SortPosition SortBy = "position"
+)
+
+func ParseSortBy(sortBy string) (SortBy, error) {
+	switch SortBy(sortBy) {
+	case SortDefault, SortPriority, SortPosition:
+		return SortBy(sortBy), nil
+	default:
+		return SortDefault, ErrInvalidSort
+	}
+}
+
+// Less сравнивает заметки в порядке, заданном sortBy.
+// Для приоритета: сначала более срочные, затем по позиции.
+func (s SortBy) Less(a, b Note) bool {
+	if s == SortPriority && a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
@@ -15,6 +15,10 @@ func (s Status) String() string {
 	return Statuses[s]
 }
 
+func (s Status) Valid() bool {
+	return s >= 0 && int(s) < len(Statuses)
+}
+
 func ParseStatus(status string) Status {
 	switch status {
 	case "New":
@@ -0,0 +1,101 @@
+// Package ratelimit описывает ограничение частоты запросов корзиной токенов: корзина
+// вмещает Burst токенов, каждый запрос забирает один, а пополняется она со скоростью
+// Requests за Per.
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	LimitHeader     = "RateLimit-Limit"
+	RemainingHeader = "RateLimit-Remaining"
+	ResetHeader     = "RateLimit-Reset"
+	PolicyHeader    = "RateLimit-Policy"
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	// Burst — ёмкость корзины; если не задана, равна Requests.
+	Burst int
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Per > 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate возвращает пополнение корзины в токенах за секунду.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// RefillTime — за сколько пустая корзина наполняется целиком; при Burst больше Requests это дольше Per.
+func (l Limit) RefillTime() time.Duration {
+	return l.wait(l.capacity())
+}
+
+// Policy возвращает значение заголовка RateLimit-Policy, например "100;w=60".
+func (l Limit) Policy() string {
+	return strconv.Itoa(l.Requests) + ";w=" + strconv.Itoa(int(math.Ceil(l.Per.Seconds())))
+}
+
+// Bucket — состояние корзины одного ключа. Новая корзина (нулевой UpdatedAt) полна.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Decision — результат запроса токена.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset — через сколько корзина наполнится полностью.
+	Reset time.Duration
+	// RetryAfter — через сколько появится следующий токен; ноль, если запрос пропущен.
+	RetryAfter time.Duration
+}
+
+// Take пополняет корзину на время, прошедшее с прошлого запроса, и забирает из неё токен.
+func (l Limit) Take(bucket Bucket, now time.Time) (Bucket, Decision) {
+	capacity := l.capacity()
+	tokens := capacity
+	if !bucket.UpdatedAt.IsZero() {
+		elapsed := max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+		tokens = min(capacity, bucket.Tokens+elapsed*l.rate())
+	}
+
+	decision := Decision{Limit: int(capacity)}
+	if tokens >= 1 {
+		tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = l.wait(1 - tokens)
+	}
+	decision.Remaining = int(tokens)
+	decision.Reset = l.wait(capacity - tokens)
+	return Bucket{Tokens: tokens, UpdatedAt: now}, decision
+}
+
+// wait возвращает время, за которое в корзину добавится tokens токенов.
+func (l Limit) wait(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate() * float64(time.Second))
+}
+
+// Seconds округляет длительность вверх до целых секунд для заголовков.
+func Seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
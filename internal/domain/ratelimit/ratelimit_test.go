@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimit_Take(t *testing.T) {
+	limit := Limit{Requests: 2, Per: time.Second}
+	now := time.Now()
+
+	bucket, decision := limit.Take(Bucket{}, now)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Limit)
+	assert.Equal(t, 1, decision.Remaining)
+	assert.Equal(t, 500*time.Millisecond, decision.Reset)
+
+	bucket, decision = limit.Take(bucket, now)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+
+	bucket, decision = limit.Take(bucket, now)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	assert.Equal(t, time.Second, decision.Reset)
+
+	_, decision = limit.Take(bucket, now.Add(500*time.Millisecond))
+	assert.True(t, decision.Allowed, "bucket refills over time")
+	assert.Equal(t, 0, decision.Remaining)
+}
+
+func TestLimit_TakeBurst(t *testing.T) {
+	limit := Limit{Requests: 1, Per: time.Minute, Burst: 3}
+	now := time.Now()
+
+	var bucket Bucket
+	var decision Decision
+	for range 3 {
+		bucket, decision = limit.Take(bucket, now)
+		assert.True(t, decision.Allowed)
+	}
+	bucket, decision = limit.Take(bucket, now)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, time.Minute, decision.RetryAfter)
+
+	_, decision = limit.Take(bucket, now.Add(time.Hour))
+	assert.Equal(t, 2, decision.Remaining, "refill is capped by burst")
+	assert.Equal(t, "1;w=60", limit.Policy())
+}
+
+func TestLimit_RefillTime(t *testing.T) {
+	assert.Equal(t, time.Minute, Limit{Requests: 60, Per: time.Minute}.RefillTime())
+	assert.Equal(t, 3*time.Minute, Limit{Requests: 1, Per: time.Minute, Burst: 3}.RefillTime())
+}
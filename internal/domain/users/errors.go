@@ -3,8 +3,33 @@ package users
 import "errors"
 
 var (
-	ErrInvalidUserCreds = errors.New("invalid creds")
-	ErrUserAlredyExists = errors.New("user alredy exists")
-	ErrUserNotFound     = errors.New("user not found")
-	ErrNoUsersAvailable = errors.New("no users avaible")
+	ErrInvalidUserCreds     = errors.New("invalid creds")
+	ErrUserAlredyExists     = errors.New("user alredy exists")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrNoUsersAvailable     = errors.New("no users avaible")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrEmailNotVerified     = errors.New("email not verified")
+	ErrAlreadyVerified      = errors.New("email already verified")
+	ErrMailerNotSet         = errors.New("mailer not configured")
+	ErrEmptyPassword        = errors.New("empty password")
+	ErrTokenRevoked         = errors.New("token revoked")
+	ErrTooManyAttempts      = errors.New("too many login attempts")
+	ErrEmptyUnlock          = errors.New("email or ip required")
+	ErrInvalidCode          = errors.New("invalid code")
+	ErrTOTPEnabled          = errors.New("2fa already enabled")
+	ErrTOTPNotEnrolled      = errors.New("2fa not enrolled")
+	ErrTOTPNotConfigured    = errors.New("2fa not configured")
+	ErrTokenNotFound        = errors.New("token not found")
+	ErrEmptyTokenName       = errors.New("empty token name")
+	ErrInvalidScope         = errors.New("invalid scope")
+	ErrTokenExpired         = errors.New("token expired")
+	ErrInsufficientScope    = errors.New("insufficient scope")
+	ErrSecondFactorRequired = errors.New("second factor required")
+	ErrIdentityNotFound     = errors.New("identity not found")
+	ErrOIDCNotConfigured    = errors.New("oidc not configured")
+	ErrIdentityNoEmail      = errors.New("identity has no email")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	ErrPreferencesNotFound  = errors.New("preferences not found")
+	ErrSigningKeyNotSet     = errors.New("signing key not configured")
 )
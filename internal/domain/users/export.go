@@ -0,0 +1,25 @@
+package users
+
+import (
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+// Export — машиночитаемый архив всех данных пользователя. Пароль и секреты 2FA не выгружаются.
+type Export struct {
+	ExportedAt       time.Time      `json:"exported_at"`
+	User             User           `json:"user"`
+	TwoFactorEnabled bool           `json:"two_factor_enabled"`
+	Preferences      Preferences    `json:"preferences"`
+	Notes            []notes.Note   `json:"notes"`
+	Sessions         []Session      `json:"sessions"`
+	AccessTokens     []AccessToken  `json:"access_tokens"`
+	Identities       []Identity     `json:"identities"`
+	LoginAttempts    *LoginAttempts `json:"login_attempts,omitempty"`
+}
+
+// DeletionRequest — ответ на запрос удаления аккаунта.
+type DeletionRequest struct {
+	DeleteAt time.Time `json:"delete_at"`
+}
@@ -0,0 +1,11 @@
+package users
+
+// Identity связывает пользователя с учётной записью внешнего провайдера OIDC.
+type Identity struct {
+	Issuer        string `json:"issuer"`
+	Subject       string `json:"subject"`
+	UID           string `json:"-"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+}
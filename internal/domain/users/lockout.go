@@ -0,0 +1,29 @@
+package users
+
+import "time"
+
+// LoginAttempts — счётчик неудачных входов по ключу (аккаунт или IP).
+type LoginAttempts struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+type UnlockRequest struct {
+	Email string `json:"email"`
+	IP    string `json:"ip"`
+}
+
+// LockoutError сообщает, через сколько можно повторить вход.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
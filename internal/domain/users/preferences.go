@@ -0,0 +1,70 @@
+package users
+
+import (
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+const (
+	DefaultTimezone  = "UTC"
+	DefaultLocale    = "en"
+	DefaultWeekStart = "monday"
+)
+
+// Preferences — настройки профиля, которые сервер учитывает при выдаче данных.
+type Preferences struct {
+	Timezone      string       `json:"timezone"       validate:"timezone"`
+	Locale        string       `json:"locale"         validate:"bcp47_language_tag"`
+	DefaultStatus notes.Status `json:"default_status" validate:"valid"`
+	DefaultSort   notes.SortBy `json:"default_sort"   validate:"omitempty,oneof=priority position"`
+	WeekStart     string       `json:"week_start"     validate:"oneof=monday sunday saturday"`
+}
+
+// PreferencesPatch — частичное обновление настроек: nil-поля не меняются.
+type PreferencesPatch struct {
+	Timezone      *string       `json:"timezone"`
+	Locale        *string       `json:"locale"`
+	DefaultStatus *notes.Status `json:"default_status"`
+	DefaultSort   *notes.SortBy `json:"default_sort"`
+	WeekStart     *string       `json:"week_start"`
+}
+
+func DefaultPreferences() Preferences {
+	return Preferences{
+		Timezone:      DefaultTimezone,
+		Locale:        DefaultLocale,
+		DefaultStatus: notes.New,
+		DefaultSort:   notes.SortDefault,
+		WeekStart:     DefaultWeekStart,
+	}
+}
+
+// Location возвращает часовой пояс пользователя; неизвестный пояс заменяется на UTC.
+func (p Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Apply возвращает prefs с изменёнными полями патча.
+func (p PreferencesPatch) Apply(prefs Preferences) Preferences {
+	if p.Timezone != nil {
+		prefs.Timezone = *p.Timezone
+	}
+	if p.Locale != nil {
+		prefs.Locale = *p.Locale
+	}
+	if p.DefaultStatus != nil {
+		prefs.DefaultStatus = *p.DefaultStatus
+	}
+	if p.DefaultSort != nil {
+		prefs.DefaultSort = *p.DefaultSort
+	}
+	if p.WeekStart != nil {
+		prefs.WeekStart = *p.WeekStart
+	}
+	return prefs
+}
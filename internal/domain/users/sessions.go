@@ -0,0 +1,19 @@
+package users
+
+import "time"
+
+// Session создаётся при каждом входе; её ID записывается в JWT (claim jti).
+type Session struct {
+	ID         string    `json:"id"`
+	UID        string    `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func (s Session) Expired(now time.Time) bool {
+	return now.After(s.ExpiresAt)
+}
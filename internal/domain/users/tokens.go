@@ -0,0 +1,75 @@
+package users
+
+import (
+	"slices"
+	"time"
+)
+
+// AccessTokenPrefix отличает персональные токены от JWT в заголовке Authorization.
+const AccessTokenPrefix = "tdl_"
+
+type Scope string
+
+const (
+	ScopeNotesRead  Scope = "notes:read"
+	ScopeNotesWrite Scope = "notes:write"
+)
+
+//nolint:gochecknoglobals // its ok
+var Scopes = []Scope{ScopeNotesRead, ScopeNotesWrite}
+
+func (s Scope) Valid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// AccessToken — персональный токен для скриптов и CI. Сам токен не хранится, только его хэш.
+type AccessToken struct {
+	ID        string     `json:"id"`
+	UID       string     `json:"-"`
+	Name      string     `json:"name"`
+	Hash      string     `json:"-"`
+	Scopes    []Scope    `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (t AccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && now.After(*t.ExpiresAt)
+}
+
+func (t AccessToken) HasScope(scope Scope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+type AccessTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []Scope    `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAccessToken отдаётся один раз при создании — потом токен узнать нельзя.
+type CreatedAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
+}
+
+// Principal — пользователь, от имени которого выполняется запрос.
+// Scopes ограничивают персональный токен; вход по JWT даёт полный доступ.
+type Principal struct {
+	UID       string
+	SessionID string
+	Scopes    []Scope
+	// AccessToken — запрос подписан персональным токеном, а не JWT.
+	AccessToken bool
+}
+
+func (p Principal) Can(scope Scope) bool {
+	return !p.AccessToken || slices.Contains(p.Scopes, scope)
+}
+
+// StreamTicket — короткоживущий билет для GET /notes/stream в параметре ticket:
+// браузерные EventSource и WebSocket не умеют передавать заголовок Authorization.
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
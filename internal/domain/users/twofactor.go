@@ -0,0 +1,38 @@
+package users
+
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// SecondFactorRequest обменивает pending-токен и код на полноценный JWT.
+type SecondFactorRequest struct {
+	PendingToken string `json:"pending_token"`
+	Code         string `json:"code"`
+}
+
+// SecondFactorError возвращается из входа по паролю, когда нужен второй шаг.
+type SecondFactorError struct {
+	PendingToken string
+}
+
+func (e *SecondFactorError) Error() string {
+	return ErrSecondFactorRequired.Error()
+}
+
+func (e *SecondFactorError) Is(target error) bool {
+	return target == ErrSecondFactorRequired
+}
+
+type PendingLogin struct {
+	TwoFactorRequired bool   `json:"2fa_required"`
+	PendingToken      string `json:"pending_token"`
+}
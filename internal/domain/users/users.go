@@ -1,13 +1,110 @@
 package users
 
+import (
+	"strings"
+	"time"
+)
+
 type User struct {
-	UID      string `json:"uid"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	UID       string    `json:"uid"`
+	Name      string    `json:"name"     validate:"notblank,max=100"`
+	Email     string    `json:"email"    validate:"required,email,max=254"`
+	Password  string    `json:"password" validate:"password"`
+	Verified  bool      `json:"verified"`
+	CreatedAt time.Time `json:"created_at"`
+	// PasswordChangedAt — токены, выданные раньше, считаются отозванными.
+	PasswordChangedAt time.Time `json:"-"`
+	TOTP              TOTP      `json:"-"`
+	// DeleteAt — время окончательного удаления аккаунта, запрошенного пользователем.
+	DeleteAt *time.Time `json:"delete_at,omitempty"`
+}
+
+// TOTP — настройки двухфакторной аутентификации. Secret хранится зашифрованным,
+// RecoveryCodes — только хэши. LastStep — последний принятый интервал: коды не новее
+// него отклоняются, поэтому подсмотренный код нельзя использовать второй раз.
+type TOTP struct {
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+	LastStep      int64
+}
+
+// Profile — данные аккаунта, которые пользователь видит о себе.
+type Profile struct {
+	UID              string     `json:"uid"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	Verified         bool       `json:"verified"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DeleteAt         *time.Time `json:"delete_at,omitempty"`
+}
+
+// ProfilePatch — частичное изменение профиля: nil-поля не меняются.
+type ProfilePatch struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+func NewProfile(user User) Profile {
+	return Profile{
+		UID:              user.UID,
+		Name:             user.Name,
+		Email:            user.Email,
+		Verified:         user.Verified,
+		TwoFactorEnabled: user.TOTP.Enabled,
+		CreatedAt:        user.CreatedAt,
+		DeleteAt:         user.DeleteAt,
+	}
 }
 
 type UserRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email"    validate:"required,email,max=254"`
+	Password string `json:"password" validate:"max=1024"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password" validate:"password"`
+}
+
+// ResetToken хранит только хэш токена сброса пароля.
+type ResetToken struct {
+	Hash      string
+	UID       string
+	ExpiresAt time.Time
+}
+
+// VerificationOverdue сообщает, что льготный период без подтверждения почты истёк.
+func (u User) VerificationOverdue(grace time.Duration, now time.Time) bool {
+	return !u.Verified && now.After(u.CreatedAt.Add(grace))
+}
+
+// TokenRevoked сообщает, что токен выдан до последней смены пароля. iat в JWT хранится
+// с точностью до секунды; токены той же секунды отзываются удалением сессий при сбросе.
+func (u User) TokenRevoked(issuedAt time.Time) bool {
+	return issuedAt.Before(u.PasswordChangedAt.Truncate(time.Second))
+}
+
+// NormalizeEmail приводит адрес к виду, в котором он хранится и ищется: адреса
+// различаются без учёта регистра.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// DuplicateAccount — аккаунт из группы адресов, совпадающих без учёта регистра.
+type DuplicateAccount struct {
+	UID       string    `json:"uid"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmailDuplicate — группа аккаунтов с одним адресом в разном регистре.
+type EmailDuplicate struct {
+	Email    string             `json:"email"`
+	Accounts []DuplicateAccount `json:"accounts"`
 }
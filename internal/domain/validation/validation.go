@@ -0,0 +1,148 @@
+// Package validation проверяет DTO по правилам из тегов `validate` и
+// возвращает ошибки по полям в виде, пригодном для ответа API.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	passwordMinLength = 8
+	// passwordMaxLength — ограничение bcrypt, чтобы политика не менялась при переходе на хэши.
+	passwordMaxLength = 72
+)
+
+// FieldError описывает нарушение одного правила.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors — все нарушения правил для одного объекта.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		parts = append(parts, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+var validate = newValidator() //nolint:gochecknoglobals // its ok
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == `` || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	// Ошибки регистрации возможны только при пустом имени тега.
+	_ = v.RegisterValidation("notblank", notBlank)
+	_ = v.RegisterValidation("password", password)
+	_ = v.RegisterValidation("valid", valid)
+	return v
+}
+
+// Struct проверяет v и возвращает nil, если правила соблюдены.
+func Struct(v any) error {
+	return convert(validate.Struct(v))
+}
+
+// StructExcept проверяет v, пропуская перечисленные поля (имена полей Go).
+func StructExcept(v any, fields ...string) error {
+	return convert(validate.StructExcept(v, fields...))
+}
+
+func convert(err error) error {
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	result := make(Errors, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		result = append(result, newFieldError(fieldErr))
+	}
+	return result
+}
+
+func newFieldError(fieldErr validator.FieldError) FieldError {
+	result := FieldError{Field: fieldPath(fieldErr.Namespace()), Code: fieldErr.Tag()}
+	switch fieldErr.Tag() {
+	case "required", "notblank":
+		result.Code = "required"
+		result.Message = "must not be empty"
+	case "email":
+		result.Message = "must be a valid email address"
+	case "max":
+		result.Code = "too_long"
+		result.Message = fmt.Sprintf("must be at most %s characters", fieldErr.Param())
+	case "min":
+		result.Code = "too_short"
+		result.Message = fmt.Sprintf("must be at least %s characters", fieldErr.Param())
+	case "password":
+		result.Code = "weak_password"
+		result.Message = fmt.Sprintf("must be %d-%d characters long and contain a letter and a digit",
+			passwordMinLength, passwordMaxLength)
+	case "valid", "oneof", "timezone", "bcp47_language_tag":
+		result.Code = "invalid_value"
+		result.Message = "has an unsupported value"
+	case "isdefault":
+		result.Code = "not_allowed"
+		result.Message = "must not be set by the client"
+	default:
+		result.Message = "is invalid"
+	}
+	return result
+}
+
+// fieldPath убирает имя корневой структуры: "Note.title" -> "title".
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func notBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ``
+}
+
+func password(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	length := len([]rune(value))
+	if length < passwordMinLength || length > passwordMaxLength {
+		return false
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
+
+// valid принимает перечисления с методом Valid, например notes.Status.
+func valid(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(interface{ Valid() bool })
+	return ok && value.Valid()
+}
@@ -0,0 +1,57 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldCodes(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var fieldErrs validation.Errors
+	require.ErrorAs(t, err, &fieldErrs)
+	codes := make(map[string]string, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		assert.NotEmpty(t, fieldErr.Message)
+		codes[fieldErr.Field] = fieldErr.Code
+	}
+	return codes
+}
+
+func TestUserRules(t *testing.T) {
+	require.NoError(t, validation.Struct(users.User{Name: "Bob", Email: "bob@example.com", Password: "secret123"}))
+
+	err := validation.Struct(users.User{Name: "  ", Email: "bob", Password: "short1"})
+	assert.Equal(t, map[string]string{
+		"name":     "required",
+		"email":    "email",
+		"password": "weak_password",
+	}, fieldCodes(t, err))
+
+	err = validation.Struct(users.User{Name: "Bob", Email: "bob@example.com", Password: "onlyletters"})
+	assert.Equal(t, map[string]string{"password": "weak_password"}, fieldCodes(t, err))
+
+	require.NoError(t, validation.StructExcept(users.User{Name: "Bob", Email: "bob@example.com"}, "Password"))
+}
+
+func TestNoteRules(t *testing.T) {
+	require.NoError(t, validation.Struct(notes.Note{Title: "Buy milk", Status: notes.Active, Priority: notes.High}))
+
+	err := validation.Struct(notes.Note{
+		Title:    strings.Repeat("a", 201),
+		Status:   notes.Status(42),
+		Priority: notes.Priority(-1),
+		UID:      "someone-else",
+	})
+	assert.Equal(t, map[string]string{
+		"title":    "too_long",
+		"status":   "invalid_value",
+		"priority": "invalid_value",
+		"uid":      "not_allowed",
+	}, fieldCodes(t, err))
+}
@@ -0,0 +1,165 @@
+// Package webhooks описывает подписки пользователей на изменения заметок и доставки
+// событий по ним. Доставки подписываются HMAC-SHA256 и повторяются с растущей паузой.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+	// MaxPerUser ограничивает число подписок одного пользователя.
+	MaxPerUser = 20
+
+	// Заголовки запроса доставки. Подпись считается от "<timestamp>.<тело>", поэтому
+	// перехваченный запрос нельзя повторить с другим временем.
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignaturePrefix = "sha256="
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrTooManyWebhooks  = errors.New("too many webhooks")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+	ErrDeliveryNotDead  = errors.New("only dead deliveries can be retried")
+	// ErrForbiddenAddress — адрес подписки указывает во внутреннюю сеть.
+	ErrForbiddenAddress = errors.New("webhook address is not public")
+)
+
+// Webhook — подписка пользователя. Секрет хранится зашифрованным и отдаётся только при создании.
+type Webhook struct {
+	ID  string `json:"id"`
+	UID string `json:"-"`
+	URL string `json:"url"`
+	// Events — на какие события подписка; пустой список означает все.
+	Events    []notes.EventType `json:"events"`
+	Secret    string            `json:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Subscribed сообщает, нужно ли доставить событие: владелец подписки должен видеть заметку.
+func (w Webhook) Subscribed(event notes.Event) bool {
+	return event.VisibleTo(w.UID) && (len(w.Events) == 0 || slices.Contains(w.Events, event.Type))
+}
+
+type WebhookRequest struct {
+	URL    string            `json:"url"    validate:"required,http_url,max=2048"`
+	Events []notes.EventType `json:"events" validate:"dive,valid"`
+	// Secret можно не задавать: тогда он будет сгенерирован.
+	Secret string `json:"secret" validate:"omitempty,min=16,max=256"`
+}
+
+// CreatedWebhook отдаётся один раз при создании — потом секрет узнать нельзя.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type DeliveryStatus string
+
+const (
+	StatusPending   DeliveryStatus = "pending"
+	StatusDelivered DeliveryStatus = "delivered"
+	// StatusDead — попытки исчерпаны, доставка лежит в списке недоставленных.
+	StatusDead DeliveryStatus = "dead"
+)
+
+// Delivery — отправка одного события по одной подписке. Тело фиксируется при постановке
+// в очередь, поэтому все попытки отправляют одно и то же.
+type Delivery struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	UID           string          `json:"-"`
+	Event         notes.EventType `json:"event"`
+	NID           string          `json:"nid"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastAttemptAt *time.Time      `json:"last_attempt_at,omitempty"`
+	ResponseCode  int             `json:"response_code,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Envelope — тело запроса доставки. DeliveryID одинаков у всех попыток и позволяет
+// получателю отбросить повтор.
+type Envelope struct {
+	DeliveryID string             `json:"delivery_id"`
+	WebhookID  string             `json:"webhook_id"`
+	Event      notes.EventPayload `json:"event"`
+}
+
+// DeliveryFilter отбирает историю доставок; пустые поля не ограничивают выборку.
+type DeliveryFilter struct {
+	UID       string         `form:"-"`
+	WebhookID string         `form:"-"`
+	Status    DeliveryStatus `form:"status"`
+	Limit     int            `form:"limit"`
+}
+
+// PageSize возвращает Limit в допустимых границах.
+func (f DeliveryFilter) PageSize() int {
+	if f.Limit <= 0 {
+		return DefaultLimit
+	}
+	return min(f.Limit, MaxLimit)
+}
+
+func (f DeliveryFilter) Match(d Delivery) bool {
+	switch {
+	case f.UID != `` && d.UID != f.UID,
+		f.WebhookID != `` && d.WebhookID != f.WebhookID,
+		f.Status != `` && d.Status != f.Status:
+		return false
+	}
+	return true
+}
+
+// Sign считает подпись доставки для заголовка X-Webhook-Signature.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись; пригодится получателям, написанным на Go.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// RetryPolicy задаёт повторы: пауза после n-й неудачи — BaseDelay * 2^(n-1), но не больше MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy даёт получателю около двух часов на восстановление.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 8, BaseDelay: time.Minute, MaxDelay: time.Hour}
+}
+
+// Backoff возвращает паузу перед следующей попыткой после attempts неудачных.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
@@ -0,0 +1,40 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"delivery_id":"1"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	assert.Equal(t, "sha256=", signature[:len(SignaturePrefix)])
+	assert.True(t, Verify("secret", 1700000000, body, signature))
+	assert.False(t, Verify("secret", 1700000001, body, signature))
+	assert.False(t, Verify("other", 1700000000, body, signature))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 8, BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}
+
+	assert.Equal(t, time.Minute, policy.Backoff(1))
+	assert.Equal(t, 2*time.Minute, policy.Backoff(2))
+	assert.Equal(t, 8*time.Minute, policy.Backoff(4))
+	assert.Equal(t, 10*time.Minute, policy.Backoff(5))
+	assert.Equal(t, 10*time.Minute, policy.Backoff(100))
+}
+
+func TestWebhook_Subscribed(t *testing.T) {
+	all := Webhook{UID: "alice"}
+	deletes := Webhook{UID: "alice", Events: []notes.EventType{notes.EventDeleted}}
+
+	assert.True(t, all.Subscribed(notes.Event{Type: notes.EventCreated, UID: "alice"}))
+	assert.True(t, all.Subscribed(notes.Event{Type: notes.EventCreated}))
+	assert.False(t, all.Subscribed(notes.Event{Type: notes.EventCreated, UID: "bob"}))
+	assert.False(t, deletes.Subscribed(notes.Event{Type: notes.EventCreated, UID: "alice"}))
+	assert.True(t, deletes.Subscribed(notes.Event{Type: notes.EventDeleted, UID: "alice"}))
+}
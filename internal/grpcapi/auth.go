@@ -0,0 +1,116 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authorizationKey = "authorization"
+
+// access — требования метода к вызывающему. Правила те же, что у маршрутов /api/v1.
+type access struct {
+	scope users.Scope
+	// interactive закрывает метод для персональных токенов.
+	interactive bool
+	// verified требует подтверждённой почты после льготного периода.
+	verified bool
+}
+
+//nolint:gochecknoglobals // its ok
+var methodAccess = map[string]access{
+	todolistv1.NotesService_ListNotes_FullMethodName:  {scope: users.ScopeNotesRead, verified: true},
+	todolistv1.NotesService_GetNote_FullMethodName:    {scope: users.ScopeNotesRead, verified: true},
+	todolistv1.NotesService_CreateNote_FullMethodName: {scope: users.ScopeNotesWrite, verified: true},
+	todolistv1.NotesService_UpdateNote_FullMethodName: {scope: users.ScopeNotesWrite, verified: true},
+	todolistv1.NotesService_DeleteNote_FullMethodName: {scope: users.ScopeNotesWrite, verified: true},
+	todolistv1.UsersService_GetMe_FullMethodName:      {interactive: true},
+	todolistv1.UsersService_UpdateMe_FullMethodName:   {interactive: true},
+}
+
+type principalKey struct{}
+
+func principalFrom(ctx context.Context) users.Principal {
+	principal, _ := ctx.Value(principalKey{}).(users.Principal)
+	return principal
+}
+
+func (s *Server) unaryAuth(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorizedStream подменяет контекст потока, чтобы обработчик видел пользователя.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize проверяет токен из метаданных и права на метод. Метод без правил закрыт.
+func (s *Server) authorize(ctx context.Context, method string) (context.Context, error) {
+	rule, ok := methodAccess[method]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "method is not allowed")
+	}
+
+	token := ``
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationKey); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+	if token == `` {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	principal, err := s.auth.Authenticate(token)
+	if err != nil {
+		s.log.Error().Err(err).Str("method", method).Msg("failed to authenticate")
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	switch {
+	case rule.interactive && principal.AccessToken:
+		return nil, status.Error(codes.PermissionDenied, users.ErrInsufficientScope.Error())
+	case rule.scope != `` && !principal.Can(rule.scope):
+		return nil, status.Errorf(codes.PermissionDenied, "%s: %s", users.ErrInsufficientScope, rule.scope)
+	}
+	if rule.verified {
+		err = s.users.CheckVerified(principal.UID)
+		if errors.Is(err, users.ErrEmailNotVerified) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if err != nil {
+			s.log.Error().Err(err).Msg("failed to check email verification")
+			return nil, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+	}
+	s.log.Debug().Str("uid", principal.UID).Str("method", method).Msg("user was authorized")
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
@@ -0,0 +1,67 @@
+package grpcapi
+
+import (
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// В protobuf нулевое значение перечисления означает «не задано», поэтому коды
+// статусов и приоритетов сдвинуты на единицу относительно домена.
+
+func statusFromProto(status todolistv1.Status) *notes.Status {
+	if status == todolistv1.Status_STATUS_UNSPECIFIED {
+		return nil
+	}
+	domain := notes.Status(status - 1)
+	return &domain
+}
+
+func priorityFromProto(priority todolistv1.Priority) *notes.Priority {
+	if priority == todolistv1.Priority_PRIORITY_UNSPECIFIED {
+		return nil
+	}
+	domain := notes.Priority(priority - 1)
+	return &domain
+}
+
+func sortFromProto(sortBy todolistv1.SortBy, fallback notes.SortBy) notes.SortBy {
+	switch sortBy {
+	case todolistv1.SortBy_SORT_BY_POSITION:
+		return notes.SortPosition
+	case todolistv1.SortBy_SORT_BY_PRIORITY:
+		return notes.SortPriority
+	default:
+		return fallback
+	}
+}
+
+func noteToProto(note notes.Note) *todolistv1.Note {
+	return &todolistv1.Note{
+		Nid:         note.NID,
+		Title:       note.Title,
+		Description: note.Description,
+		Status:      todolistv1.Status(note.Status + 1),
+		Priority:    todolistv1.Priority(note.Priority + 1),
+		Position:    note.Position,
+		CreatedAt:   timestamppb.New(note.CreatedAt),
+		Uid:         note.UID,
+	}
+}
+
+func userToProto(user users.User) *todolistv1.User {
+	profile := users.NewProfile(user)
+	message := &todolistv1.User{
+		Uid:              profile.UID,
+		Name:             profile.Name,
+		Email:            profile.Email,
+		Verified:         profile.Verified,
+		TwoFactorEnabled: profile.TwoFactorEnabled,
+		CreatedAt:        timestamppb.New(profile.CreatedAt),
+	}
+	if profile.DeleteAt != nil {
+		message.DeleteAt = timestamppb.New(*profile.DeleteAt)
+	}
+	return message
+}
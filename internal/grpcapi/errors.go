@@ -0,0 +1,45 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus переводит ошибку сервиса в статус gRPC; коды соответствуют статусам HTTP API.
+func toStatus(err error) error {
+	var fieldErrs validation.Errors
+	switch {
+	case errors.As(err, &fieldErrs):
+		return validationStatus(fieldErrs)
+	case errors.Is(err, notes.ErrNoteNotFound), errors.Is(err, users.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, notes.ErrNoteAlreadyExists), errors.Is(err, users.ErrUserAlredyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// validationStatus передаёт нарушенные правила в деталях BadRequest, как поле fields в HTTP API.
+func validationStatus(fieldErrs validation.Errors) error {
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Message,
+			Reason:      fieldErr.Code,
+		})
+	}
+	st, err := status.New(codes.InvalidArgument, "validation failed").
+		WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fieldErrs.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type notesServer struct {
+	todolistv1.UnimplementedNotesServiceServer
+	*Server
+}
+
+// preferences возвращает настройки пользователя; при ошибке чтения — настройки по умолчанию.
+func (s *Server) preferences(uid string) users.Preferences {
+	prefs, err := s.users.GetPreferences(uid)
+	if err != nil {
+		s.log.Error().Err(err).Str("uid", uid).Msg("failed to get preferences")
+		return users.DefaultPreferences()
+	}
+	return prefs
+}
+
+func (s *notesServer) ListNotes(req *todolistv1.ListNotesRequest, stream grpc.ServerStreamingServer[todolistv1.Note]) error {
+	uid := principalFrom(stream.Context()).UID
+	send := func(note notes.Note) error {
+		return stream.Send(noteToProto(note))
+	}
+
+	sortBy := sortFromProto(req.GetSort(), s.preferences(uid).DefaultSort)
+	if sortBy != notes.SortPriority {
+		// Хранилище само отдаёт заметки в порядке позиций.
+		if err := s.notes.StreamUserNotes(uid, send); err != nil {
+			return toStatus(err)
+		}
+		return nil
+	}
+
+	notesSlice, err := s.notes.ListUserNotes(uid, sortBy)
+	if err != nil {
+		return toStatus(err)
+	}
+	for _, note := range notesSlice {
+		if err = send(note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *notesServer) GetNote(ctx context.Context, req *todolistv1.GetNoteRequest) (*todolistv1.Note, error) {
+	note, err := s.notes.GetUserNote(principalFrom(ctx).UID, req.GetNid())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return noteToProto(note), nil
+}
+
+func (s *notesServer) CreateNote(ctx context.Context, req *todolistv1.CreateNoteRequest) (*todolistv1.Note, error) {
+	uid := principalFrom(ctx).UID
+	patch := notes.NotePatch{
+		Title:       &req.Title,
+		Description: &req.Description,
+		Status:      statusFromProto(req.GetStatus()),
+		Priority:    priorityFromProto(req.GetPriority()),
+	}
+	note, err := s.notes.CreateUserNote(uid, patch.Apply(notes.Note{Status: s.preferences(uid).DefaultStatus}))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return noteToProto(note), nil
+}
+
+func (s *notesServer) UpdateNote(ctx context.Context, req *todolistv1.UpdateNoteRequest) (*todolistv1.Note, error) {
+	patch := notes.NotePatch{
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      statusFromProto(req.GetStatus()),
+		Priority:    priorityFromProto(req.GetPriority()),
+	}
+	note, err := s.notes.PatchUserNote(principalFrom(ctx).UID, req.GetNid(), patch)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return noteToProto(note), nil
+}
+
+func (s *notesServer) DeleteNote(ctx context.Context, req *todolistv1.DeleteNoteRequest) (*emptypb.Empty, error) {
+	if err := s.notes.DeleteUserNote(principalFrom(ctx).UID, req.GetNid()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
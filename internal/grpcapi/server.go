@@ -0,0 +1,86 @@
+// Package grpcapi — gRPC API поверх тех же сервисов заметок и пользователей, что и HTTP API.
+// Описание сервисов лежит в api/proto, сгенерированный код — в пакете todolistv1.
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note"
+	"github.com/Snoop-Duck/ToDoList/internal/services/user"
+	logger "github.com/Snoop-Duck/ToDoList/pkg"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+// Authenticator проверяет токен из метаданных; его реализует server.NotesAPI.
+type Authenticator interface {
+	Authenticate(token string) (users.Principal, error)
+}
+
+type Server struct {
+	addr       string
+	grpcServer *grpc.Server
+	notes      *note.Service
+	users      *user.Service
+	auth       Authenticator
+	log        zerolog.Logger
+}
+
+func New(cfg *internal.Config, notes *note.Service, users *user.Service, auth Authenticator) *Server {
+	var log zerolog.Logger
+	if cfg != nil {
+		log = logger.Get(cfg.Debug)
+	} else {
+		log = zerolog.Nop() // для тестов
+	}
+
+	s := &Server{
+		notes: notes,
+		users: users,
+		auth:  auth,
+		log:   log,
+	}
+	if cfg != nil {
+		s.addr = fmt.Sprintf("%s:%d", cfg.Host, cfg.GRPCPort)
+	}
+	s.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamAuth),
+	)
+	todolistv1.RegisterNotesServiceServer(s.grpcServer, &notesServer{Server: s})
+	todolistv1.RegisterUsersServiceServer(s.grpcServer, &usersServer{Server: s})
+	return s
+}
+
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.log.Info().Msgf("gRPC API started on %s", s.addr)
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpcServer.Serve(lis)
+}
+
+// Stop дожидается завершения текущих вызовов, а по истечении ctx обрывает их,
+// иначе долгий ListNotes задержал бы остановку сервиса.
+func (s *Server) Stop(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+}
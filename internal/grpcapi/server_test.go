@@ -0,0 +1,218 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note"
+	"github.com/Snoop-Duck/ToDoList/internal/services/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// tokens — аутентификатор для тестов: токен сразу указывает на пользователя.
+type tokens map[string]users.Principal
+
+func (t tokens) Authenticate(token string) (users.Principal, error) {
+	principal, ok := t[token]
+	if !ok {
+		return users.Principal{}, users.ErrInvalidToken
+	}
+	return principal, nil
+}
+
+func newTestClient(t *testing.T) (*grpc.ClientConn, *inmemory.Notes) {
+	t.Helper()
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "bob", Name: "Bob", Email: "bob@example.com", Verified: true}))
+	repoNote := inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json"))
+
+	srv := New(nil, note.New(repoNote), user.New(repo), tokens{
+		"jwt":       {UID: "bob", SessionID: "s1"},
+		"read-only": {UID: "bob", Scopes: []users.Scope{users.ScopeNotesRead}, AccessToken: true},
+	})
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, repoNote
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), authorizationKey, "Bearer "+token)
+}
+
+func TestNotesService(t *testing.T) {
+	conn, repoNote := newTestClient(t)
+	require.NoError(t, repoNote.AddNote(notes.Note{NID: "foreign", Title: "Alice's", UID: "alice"}))
+	client := todolistv1.NewNotesServiceClient(conn)
+	ctx := withToken("jwt")
+
+	created, err := client.CreateNote(ctx, &todolistv1.CreateNoteRequest{
+		Title:    "Buy milk",
+		Priority: todolistv1.Priority_PRIORITY_HIGH,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, todolistv1.Status_STATUS_NEW, created.GetStatus())
+	assert.Equal(t, todolistv1.Priority_PRIORITY_HIGH, created.GetPriority())
+	assert.Equal(t, "bob", created.GetUid())
+
+	_, err = client.CreateNote(ctx, &todolistv1.CreateNoteRequest{Title: "Walk", Priority: todolistv1.Priority_PRIORITY_URGENT})
+	require.NoError(t, err)
+
+	updated, err := client.UpdateNote(ctx, &todolistv1.UpdateNoteRequest{
+		Nid:    created.GetNid(),
+		Status: todolistv1.Status_STATUS_ACTIVE,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, todolistv1.Status_STATUS_ACTIVE, updated.GetStatus())
+	assert.Equal(t, "Buy milk", updated.GetTitle())
+
+	stream, err := client.ListNotes(ctx, &todolistv1.ListNotesRequest{Sort: todolistv1.SortBy_SORT_BY_PRIORITY})
+	require.NoError(t, err)
+	var titles []string
+	for {
+		n, recvErr := stream.Recv()
+		if errors.Is(recvErr, io.EOF) {
+			break
+		}
+		require.NoError(t, recvErr)
+		titles = append(titles, n.GetTitle())
+	}
+	assert.Equal(t, []string{"Walk", "Buy milk"}, titles)
+
+	_, err = client.GetNote(ctx, &todolistv1.GetNoteRequest{Nid: "foreign"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteNote(ctx, &todolistv1.DeleteNoteRequest{Nid: created.GetNid()})
+	require.NoError(t, err)
+	_, err = client.GetNote(ctx, &todolistv1.GetNoteRequest{Nid: created.GetNid()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestNotesServiceValidation(t *testing.T) {
+	conn, _ := newTestClient(t)
+	client := todolistv1.NewNotesServiceClient(conn)
+
+	_, err := client.CreateNote(withToken("jwt"), &todolistv1.CreateNoteRequest{Title: " "})
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	assert.True(t, proto.Equal(&errdetails.BadRequest_FieldViolation{
+		Field: "title", Reason: "required", Description: "must not be empty",
+	}, badRequest.GetFieldViolations()[0]))
+}
+
+func TestAuthInterceptors(t *testing.T) {
+	conn, _ := newTestClient(t)
+	notesClient := todolistv1.NewNotesServiceClient(conn)
+	usersClient := todolistv1.NewUsersServiceClient(conn)
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "no token",
+			call: func() error {
+				_, err := notesClient.GetNote(context.Background(), &todolistv1.GetNoteRequest{Nid: "n1"})
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "unknown token on a stream",
+			call: func() error {
+				stream, err := notesClient.ListNotes(withToken("forged"), &todolistv1.ListNotesRequest{})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "access token without write scope",
+			call: func() error {
+				_, err := notesClient.CreateNote(withToken("read-only"), &todolistv1.CreateNoteRequest{Title: "x"})
+				return err
+			},
+			want: codes.PermissionDenied,
+		},
+		{
+			name: "access token on an account method",
+			call: func() error {
+				_, err := usersClient.GetMe(withToken("read-only"), &emptypb.Empty{})
+				return err
+			},
+			want: codes.PermissionDenied,
+		},
+		{
+			name: "access token with read scope",
+			call: func() error {
+				stream, err := notesClient.ListNotes(withToken("read-only"), &todolistv1.ListNotesRequest{})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			want: codes.OK,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.call()
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			assert.Equal(t, tc.want, status.Code(err))
+		})
+	}
+}
+
+func TestUsersService(t *testing.T) {
+	conn, _ := newTestClient(t)
+	client := todolistv1.NewUsersServiceClient(conn)
+	ctx := withToken("jwt")
+
+	me, err := client.GetMe(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", me.GetEmail())
+	assert.Nil(t, me.GetDeleteAt())
+
+	name := "Robert"
+	me, err = client.UpdateMe(ctx, &todolistv1.UpdateMeRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, "Robert", me.GetName())
+	assert.Equal(t, "bob@example.com", me.GetEmail())
+}
@@ -0,0 +1,4 @@
+// Package todolistv1 — код, сгенерированный из описаний в api/proto/todolist/v1.
+package todolistv1
+
+//go:generate protoc -I ../../../api/proto --go_out=../../.. --go_opt=module=github.com/Snoop-Duck/ToDoList --go-grpc_out=../../.. --go-grpc_opt=module=github.com/Snoop-Duck/ToDoList todolist/v1/notes.proto todolist/v1/users.proto
@@ -0,0 +1,703 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: todolist/v1/notes.proto
+
+package todolistv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED Status = 0
+	Status_STATUS_NEW         Status = 1
+	Status_STATUS_ACTIVE      Status = 2
+	Status_STATUS_INACTIVE    Status = 3
+	Status_STATUS_DELETED     Status = 4
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_NEW",
+		2: "STATUS_ACTIVE",
+		3: "STATUS_INACTIVE",
+		4: "STATUS_DELETED",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_NEW":         1,
+		"STATUS_ACTIVE":      2,
+		"STATUS_INACTIVE":    3,
+		"STATUS_DELETED":     4,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_todolist_v1_notes_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_todolist_v1_notes_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{0}
+}
+
+type Priority int32
+
+const (
+	Priority_PRIORITY_UNSPECIFIED Priority = 0
+	Priority_PRIORITY_LOW         Priority = 1
+	Priority_PRIORITY_MEDIUM      Priority = 2
+	Priority_PRIORITY_HIGH        Priority = 3
+	Priority_PRIORITY_URGENT      Priority = 4
+)
+
+// Enum value maps for Priority.
+var (
+	Priority_name = map[int32]string{
+		0: "PRIORITY_UNSPECIFIED",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_MEDIUM",
+		3: "PRIORITY_HIGH",
+		4: "PRIORITY_URGENT",
+	}
+	Priority_value = map[string]int32{
+		"PRIORITY_UNSPECIFIED": 0,
+		"PRIORITY_LOW":         1,
+		"PRIORITY_MEDIUM":      2,
+		"PRIORITY_HIGH":        3,
+		"PRIORITY_URGENT":      4,
+	}
+)
+
+func (x Priority) Enum() *Priority {
+	p := new(Priority)
+	*p = x
+	return p
+}
+
+func (x Priority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Priority) Descriptor() protoreflect.EnumDescriptor {
+	return file_todolist_v1_notes_proto_enumTypes[1].Descriptor()
+}
+
+func (Priority) Type() protoreflect.EnumType {
+	return &file_todolist_v1_notes_proto_enumTypes[1]
+}
+
+func (x Priority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Priority.Descriptor instead.
+func (Priority) EnumDescriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{1}
+}
+
+type SortBy int32
+
+const (
+	SortBy_SORT_BY_UNSPECIFIED SortBy = 0
+	SortBy_SORT_BY_POSITION    SortBy = 1
+	SortBy_SORT_BY_PRIORITY    SortBy = 2
+)
+
+// Enum value maps for SortBy.
+var (
+	SortBy_name = map[int32]string{
+		0: "SORT_BY_UNSPECIFIED",
+		1: "SORT_BY_POSITION",
+		2: "SORT_BY_PRIORITY",
+	}
+	SortBy_value = map[string]int32{
+		"SORT_BY_UNSPECIFIED": 0,
+		"SORT_BY_POSITION":    1,
+		"SORT_BY_PRIORITY":    2,
+	}
+)
+
+func (x SortBy) Enum() *SortBy {
+	p := new(SortBy)
+	*p = x
+	return p
+}
+
+func (x SortBy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortBy) Descriptor() protoreflect.EnumDescriptor {
+	return file_todolist_v1_notes_proto_enumTypes[2].Descriptor()
+}
+
+func (SortBy) Type() protoreflect.EnumType {
+	return &file_todolist_v1_notes_proto_enumTypes[2]
+}
+
+func (x SortBy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortBy.Descriptor instead.
+func (SortBy) EnumDescriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{2}
+}
+
+type Note struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nid           string                 `protobuf:"bytes,1,opt,name=nid,proto3" json:"nid,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todolist.v1.Status" json:"status,omitempty"`
+	Priority      Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=todolist.v1.Priority" json:"priority,omitempty"`
+	Position      string                 `protobuf:"bytes,6,opt,name=position,proto3" json:"position,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Uid           string                 `protobuf:"bytes,8,opt,name=uid,proto3" json:"uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	mi := &file_todolist_v1_notes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_notes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Note) GetNid() string {
+	if x != nil {
+		return x.Nid
+	}
+	return ""
+}
+
+func (x *Note) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Note) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Note) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *Note) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Note) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Note) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Note) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ListNotesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// SORT_BY_UNSPECIFIED — порядок из настройки default_sort.
+	Sort          SortBy `protobuf:"varint,1,opt,name=sort,proto3,enum=todolist.v1.SortBy" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNotesRequest) Reset() {
+	*x = ListNotesRequest{}
+	mi := &file_todolist_v1_notes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNotesRequest) ProtoMessage() {}
+
+func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_notes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNotesRequest.ProtoReflect.Descriptor instead.
+func (*ListNotesRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListNotesRequest) GetSort() SortBy {
+	if x != nil {
+		return x.Sort
+	}
+	return SortBy_SORT_BY_UNSPECIFIED
+}
+
+type GetNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nid           string                 `protobuf:"bytes,1,opt,name=nid,proto3" json:"nid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNoteRequest) Reset() {
+	*x = GetNoteRequest{}
+	mi := &file_todolist_v1_notes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNoteRequest) ProtoMessage() {}
+
+func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_notes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNoteRequest.ProtoReflect.Descriptor instead.
+func (*GetNoteRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetNoteRequest) GetNid() string {
+	if x != nil {
+		return x.Nid
+	}
+	return ""
+}
+
+type CreateNoteRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// STATUS_UNSPECIFIED — статус из настройки default_status.
+	Status Status `protobuf:"varint,3,opt,name=status,proto3,enum=todolist.v1.Status" json:"status,omitempty"`
+	// PRIORITY_UNSPECIFIED — низкий приоритет.
+	Priority      Priority `protobuf:"varint,4,opt,name=priority,proto3,enum=todolist.v1.Priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteRequest) Reset() {
+	*x = CreateNoteRequest{}
+	mi := &file_todolist_v1_notes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteRequest) ProtoMessage() {}
+
+func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_notes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateNoteRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *CreateNoteRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+// UpdateNoteRequest меняет только заданные поля, как PATCH /api/v1/notes/{id}.
+type UpdateNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nid           string                 `protobuf:"bytes,1,opt,name=nid,proto3" json:"nid,omitempty"`
+	Title         *string                `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Description   *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Status        Status                 `protobuf:"varint,4,opt,name=status,proto3,enum=todolist.v1.Status" json:"status,omitempty"`
+	Priority      Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=todolist.v1.Priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateNoteRequest) Reset() {
+	*x = UpdateNoteRequest{}
+	mi := &file_todolist_v1_notes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNoteRequest) ProtoMessage() {}
+
+func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_notes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNoteRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateNoteRequest) GetNid() string {
+	if x != nil {
+		return x.Nid
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateNoteRequest) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateNoteRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+type DeleteNoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nid           string                 `protobuf:"bytes,1,opt,name=nid,proto3" json:"nid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteNoteRequest) Reset() {
+	*x = DeleteNoteRequest{}
+	mi := &file_todolist_v1_notes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNoteRequest) ProtoMessage() {}
+
+func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_notes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNoteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNoteRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_notes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteNoteRequest) GetNid() string {
+	if x != nil {
+		return x.Nid
+	}
+	return ""
+}
+
+var File_todolist_v1_notes_proto protoreflect.FileDescriptor
+
+const file_todolist_v1_notes_proto_rawDesc = "" +
+	"\n" +
+	"\x17todolist/v1/notes.proto\x12\vtodolist.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x99\x02\n" +
+	"\x04Note\x12\x10\n" +
+	"\x03nid\x18\x01 \x01(\tR\x03nid\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12+\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x13.todolist.v1.StatusR\x06status\x121\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x15.todolist.v1.PriorityR\bpriority\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\tR\bposition\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x10\n" +
+	"\x03uid\x18\b \x01(\tR\x03uid\";\n" +
+	"\x10ListNotesRequest\x12'\n" +
+	"\x04sort\x18\x01 \x01(\x0e2\x13.todolist.v1.SortByR\x04sort\"\"\n" +
+	"\x0eGetNoteRequest\x12\x10\n" +
+	"\x03nid\x18\x01 \x01(\tR\x03nid\"\xab\x01\n" +
+	"\x11CreateNoteRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12+\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x13.todolist.v1.StatusR\x06status\x121\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x15.todolist.v1.PriorityR\bpriority\"\xe1\x01\n" +
+	"\x11UpdateNoteRequest\x12\x10\n" +
+	"\x03nid\x18\x01 \x01(\tR\x03nid\x12\x19\n" +
+	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x01R\vdescription\x88\x01\x01\x12+\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x13.todolist.v1.StatusR\x06status\x121\n" +
+	"\bpriority\x18\x05 \x01(\x0e2\x15.todolist.v1.PriorityR\bpriorityB\b\n" +
+	"\x06_titleB\x0e\n" +
+	"\f_description\"%\n" +
+	"\x11DeleteNoteRequest\x12\x10\n" +
+	"\x03nid\x18\x01 \x01(\tR\x03nid*l\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"STATUS_NEW\x10\x01\x12\x11\n" +
+	"\rSTATUS_ACTIVE\x10\x02\x12\x13\n" +
+	"\x0fSTATUS_INACTIVE\x10\x03\x12\x12\n" +
+	"\x0eSTATUS_DELETED\x10\x04*s\n" +
+	"\bPriority\x12\x18\n" +
+	"\x14PRIORITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x13\n" +
+	"\x0fPRIORITY_MEDIUM\x10\x02\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x03\x12\x13\n" +
+	"\x0fPRIORITY_URGENT\x10\x04*M\n" +
+	"\x06SortBy\x12\x17\n" +
+	"\x13SORT_BY_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10SORT_BY_POSITION\x10\x01\x12\x14\n" +
+	"\x10SORT_BY_PRIORITY\x10\x022\xd2\x02\n" +
+	"\fNotesService\x12?\n" +
+	"\tListNotes\x12\x1d.todolist.v1.ListNotesRequest\x1a\x11.todolist.v1.Note0\x01\x129\n" +
+	"\aGetNote\x12\x1b.todolist.v1.GetNoteRequest\x1a\x11.todolist.v1.Note\x12?\n" +
+	"\n" +
+	"CreateNote\x12\x1e.todolist.v1.CreateNoteRequest\x1a\x11.todolist.v1.Note\x12?\n" +
+	"\n" +
+	"UpdateNote\x12\x1e.todolist.v1.UpdateNoteRequest\x1a\x11.todolist.v1.Note\x12D\n" +
+	"\n" +
+	"DeleteNote\x12\x1e.todolist.v1.DeleteNoteRequest\x1a\x16.google.protobuf.EmptyBGZEgithub.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1;todolistv1b\x06proto3"
+
+var (
+	file_todolist_v1_notes_proto_rawDescOnce sync.Once
+	file_todolist_v1_notes_proto_rawDescData []byte
+)
+
+func file_todolist_v1_notes_proto_rawDescGZIP() []byte {
+	file_todolist_v1_notes_proto_rawDescOnce.Do(func() {
+		file_todolist_v1_notes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todolist_v1_notes_proto_rawDesc), len(file_todolist_v1_notes_proto_rawDesc)))
+	})
+	return file_todolist_v1_notes_proto_rawDescData
+}
+
+var file_todolist_v1_notes_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_todolist_v1_notes_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_todolist_v1_notes_proto_goTypes = []any{
+	(Status)(0),                   // 0: todolist.v1.Status
+	(Priority)(0),                 // 1: todolist.v1.Priority
+	(SortBy)(0),                   // 2: todolist.v1.SortBy
+	(*Note)(nil),                  // 3: todolist.v1.Note
+	(*ListNotesRequest)(nil),      // 4: todolist.v1.ListNotesRequest
+	(*GetNoteRequest)(nil),        // 5: todolist.v1.GetNoteRequest
+	(*CreateNoteRequest)(nil),     // 6: todolist.v1.CreateNoteRequest
+	(*UpdateNoteRequest)(nil),     // 7: todolist.v1.UpdateNoteRequest
+	(*DeleteNoteRequest)(nil),     // 8: todolist.v1.DeleteNoteRequest
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 10: google.protobuf.Empty
+}
+var file_todolist_v1_notes_proto_depIdxs = []int32{
+	0,  // 0: todolist.v1.Note.status:type_name -> todolist.v1.Status
+	1,  // 1: todolist.v1.Note.priority:type_name -> todolist.v1.Priority
+	9,  // 2: todolist.v1.Note.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 3: todolist.v1.ListNotesRequest.sort:type_name -> todolist.v1.SortBy
+	0,  // 4: todolist.v1.CreateNoteRequest.status:type_name -> todolist.v1.Status
+	1,  // 5: todolist.v1.CreateNoteRequest.priority:type_name -> todolist.v1.Priority
+	0,  // 6: todolist.v1.UpdateNoteRequest.status:type_name -> todolist.v1.Status
+	1,  // 7: todolist.v1.UpdateNoteRequest.priority:type_name -> todolist.v1.Priority
+	4,  // 8: todolist.v1.NotesService.ListNotes:input_type -> todolist.v1.ListNotesRequest
+	5,  // 9: todolist.v1.NotesService.GetNote:input_type -> todolist.v1.GetNoteRequest
+	6,  // 10: todolist.v1.NotesService.CreateNote:input_type -> todolist.v1.CreateNoteRequest
+	7,  // 11: todolist.v1.NotesService.UpdateNote:input_type -> todolist.v1.UpdateNoteRequest
+	8,  // 12: todolist.v1.NotesService.DeleteNote:input_type -> todolist.v1.DeleteNoteRequest
+	3,  // 13: todolist.v1.NotesService.ListNotes:output_type -> todolist.v1.Note
+	3,  // 14: todolist.v1.NotesService.GetNote:output_type -> todolist.v1.Note
+	3,  // 15: todolist.v1.NotesService.CreateNote:output_type -> todolist.v1.Note
+	3,  // 16: todolist.v1.NotesService.UpdateNote:output_type -> todolist.v1.Note
+	10, // 17: todolist.v1.NotesService.DeleteNote:output_type -> google.protobuf.Empty
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_todolist_v1_notes_proto_init() }
+func file_todolist_v1_notes_proto_init() {
+	if File_todolist_v1_notes_proto != nil {
+		return
+	}
+	file_todolist_v1_notes_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todolist_v1_notes_proto_rawDesc), len(file_todolist_v1_notes_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todolist_v1_notes_proto_goTypes,
+		DependencyIndexes: file_todolist_v1_notes_proto_depIdxs,
+		EnumInfos:         file_todolist_v1_notes_proto_enumTypes,
+		MessageInfos:      file_todolist_v1_notes_proto_msgTypes,
+	}.Build()
+	File_todolist_v1_notes_proto = out.File
+	file_todolist_v1_notes_proto_goTypes = nil
+	file_todolist_v1_notes_proto_depIdxs = nil
+}
@@ -0,0 +1,288 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: todolist/v1/notes.proto
+
+package todolistv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NotesService_ListNotes_FullMethodName  = "/todolist.v1.NotesService/ListNotes"
+	NotesService_GetNote_FullMethodName    = "/todolist.v1.NotesService/GetNote"
+	NotesService_CreateNote_FullMethodName = "/todolist.v1.NotesService/CreateNote"
+	NotesService_UpdateNote_FullMethodName = "/todolist.v1.NotesService/UpdateNote"
+	NotesService_DeleteNote_FullMethodName = "/todolist.v1.NotesService/DeleteNote"
+)
+
+// NotesServiceClient is the client API for NotesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NotesService работает только с заметками вызывающего пользователя, как /api/v1/notes.
+// Токен передаётся в метаданных: authorization: Bearer <JWT или персональный токен>.
+type NotesServiceClient interface {
+	// ListNotes отдаёт заметки по одной, читая их из хранилища потоком в порядке позиций.
+	// Только сортировка по приоритету собирает заметки в памяти.
+	ListNotes(ctx context.Context, in *ListNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Note], error)
+	GetNote(ctx context.Context, in *GetNoteRequest, opts ...grpc.CallOption) (*Note, error)
+	CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*Note, error)
+	UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*Note, error)
+	DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type notesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNotesServiceClient(cc grpc.ClientConnInterface) NotesServiceClient {
+	return &notesServiceClient{cc}
+}
+
+func (c *notesServiceClient) ListNotes(ctx context.Context, in *ListNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Note], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[0], NotesService_ListNotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListNotesRequest, Note]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ListNotesClient = grpc.ServerStreamingClient[Note]
+
+func (c *notesServiceClient) GetNote(ctx context.Context, in *GetNoteRequest, opts ...grpc.CallOption) (*Note, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Note)
+	err := c.cc.Invoke(ctx, NotesService_GetNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*Note, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Note)
+	err := c.cc.Invoke(ctx, NotesService_CreateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UpdateNote(ctx context.Context, in *UpdateNoteRequest, opts ...grpc.CallOption) (*Note, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Note)
+	err := c.cc.Invoke(ctx, NotesService_UpdateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, NotesService_DeleteNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotesServiceServer is the server API for NotesService service.
+// All implementations must embed UnimplementedNotesServiceServer
+// for forward compatibility.
+//
+// NotesService работает только с заметками вызывающего пользователя, как /api/v1/notes.
+// Токен передаётся в метаданных: authorization: Bearer <JWT или персональный токен>.
+type NotesServiceServer interface {
+	// ListNotes отдаёт заметки по одной, читая их из хранилища потоком в порядке позиций.
+	// Только сортировка по приоритету собирает заметки в памяти.
+	ListNotes(*ListNotesRequest, grpc.ServerStreamingServer[Note]) error
+	GetNote(context.Context, *GetNoteRequest) (*Note, error)
+	CreateNote(context.Context, *CreateNoteRequest) (*Note, error)
+	UpdateNote(context.Context, *UpdateNoteRequest) (*Note, error)
+	DeleteNote(context.Context, *DeleteNoteRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedNotesServiceServer()
+}
+
+// UnimplementedNotesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNotesServiceServer struct{}
+
+func (UnimplementedNotesServiceServer) ListNotes(*ListNotesRequest, grpc.ServerStreamingServer[Note]) error {
+	return status.Errorf(codes.Unimplemented, "method ListNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) GetNote(context.Context, *GetNoteRequest) (*Note, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNote not implemented")
+}
+func (UnimplementedNotesServiceServer) CreateNote(context.Context, *CreateNoteRequest) (*Note, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNote not implemented")
+}
+func (UnimplementedNotesServiceServer) UpdateNote(context.Context, *UpdateNoteRequest) (*Note, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNote not implemented")
+}
+func (UnimplementedNotesServiceServer) DeleteNote(context.Context, *DeleteNoteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNote not implemented")
+}
+func (UnimplementedNotesServiceServer) mustEmbedUnimplementedNotesServiceServer() {}
+func (UnimplementedNotesServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeNotesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NotesServiceServer will
+// result in compilation errors.
+type UnsafeNotesServiceServer interface {
+	mustEmbedUnimplementedNotesServiceServer()
+}
+
+func RegisterNotesServiceServer(s grpc.ServiceRegistrar, srv NotesServiceServer) {
+	// If the following call pancis, it indicates UnimplementedNotesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NotesService_ServiceDesc, srv)
+}
+
+func _NotesService_ListNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListNotesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotesServiceServer).ListNotes(m, &grpc.GenericServerStream[ListNotesRequest, Note]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_ListNotesServer = grpc.ServerStreamingServer[Note]
+
+func _NotesService_GetNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).GetNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_GetNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).GetNote(ctx, req.(*GetNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_CreateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).CreateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_CreateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).CreateNote(ctx, req.(*CreateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UpdateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UpdateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UpdateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UpdateNote(ctx, req.(*UpdateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_DeleteNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).DeleteNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_DeleteNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).DeleteNote(ctx, req.(*DeleteNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NotesService_ServiceDesc is the grpc.ServiceDesc for NotesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NotesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todolist.v1.NotesService",
+	HandlerType: (*NotesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetNote",
+			Handler:    _NotesService_GetNote_Handler,
+		},
+		{
+			MethodName: "CreateNote",
+			Handler:    _NotesService_CreateNote_Handler,
+		},
+		{
+			MethodName: "UpdateNote",
+			Handler:    _NotesService_UpdateNote_Handler,
+		},
+		{
+			MethodName: "DeleteNote",
+			Handler:    _NotesService_DeleteNote_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListNotes",
+			Handler:       _NotesService_ListNotes_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "todolist/v1/notes.proto",
+}
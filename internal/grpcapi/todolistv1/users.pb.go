@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: todolist/v1/users.proto
+
+package todolistv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type User struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Uid              string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email            string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Verified         bool                   `protobuf:"varint,4,opt,name=verified,proto3" json:"verified,omitempty"`
+	TwoFactorEnabled bool                   `protobuf:"varint,5,opt,name=two_factor_enabled,json=twoFactorEnabled,proto3" json:"two_factor_enabled,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// delete_at задан, если пользователь запросил удаление аккаунта.
+	DeleteAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=delete_at,json=deleteAt,proto3" json:"delete_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_todolist_v1_users_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_users_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_users_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *User) GetTwoFactorEnabled() bool {
+	if x != nil {
+		return x.TwoFactorEnabled
+	}
+	return false
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetDeleteAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeleteAt
+	}
+	return nil
+}
+
+type UpdateMeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Email         *string                `protobuf:"bytes,2,opt,name=email,proto3,oneof" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMeRequest) Reset() {
+	*x = UpdateMeRequest{}
+	mi := &file_todolist_v1_users_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMeRequest) ProtoMessage() {}
+
+func (x *UpdateMeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todolist_v1_users_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMeRequest) Descriptor() ([]byte, []int) {
+	return file_todolist_v1_users_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UpdateMeRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateMeRequest) GetEmail() string {
+	if x != nil && x.Email != nil {
+		return *x.Email
+	}
+	return ""
+}
+
+var File_todolist_v1_users_proto protoreflect.FileDescriptor
+
+const file_todolist_v1_users_proto_rawDesc = "" +
+	"\n" +
+	"\x17todolist/v1/users.proto\x12\vtodolist.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x80\x02\n" +
+	"\x04User\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x1a\n" +
+	"\bverified\x18\x04 \x01(\bR\bverified\x12,\n" +
+	"\x12two_factor_enabled\x18\x05 \x01(\bR\x10twoFactorEnabled\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\tdelete_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bdeleteAt\"X\n" +
+	"\x0fUpdateMeRequest\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x19\n" +
+	"\x05email\x18\x02 \x01(\tH\x01R\x05email\x88\x01\x01B\a\n" +
+	"\x05_nameB\b\n" +
+	"\x06_email2\x7f\n" +
+	"\fUsersService\x122\n" +
+	"\x05GetMe\x12\x16.google.protobuf.Empty\x1a\x11.todolist.v1.User\x12;\n" +
+	"\bUpdateMe\x12\x1c.todolist.v1.UpdateMeRequest\x1a\x11.todolist.v1.UserBGZEgithub.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1;todolistv1b\x06proto3"
+
+var (
+	file_todolist_v1_users_proto_rawDescOnce sync.Once
+	file_todolist_v1_users_proto_rawDescData []byte
+)
+
+func file_todolist_v1_users_proto_rawDescGZIP() []byte {
+	file_todolist_v1_users_proto_rawDescOnce.Do(func() {
+		file_todolist_v1_users_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todolist_v1_users_proto_rawDesc), len(file_todolist_v1_users_proto_rawDesc)))
+	})
+	return file_todolist_v1_users_proto_rawDescData
+}
+
+var file_todolist_v1_users_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_todolist_v1_users_proto_goTypes = []any{
+	(*User)(nil),                  // 0: todolist.v1.User
+	(*UpdateMeRequest)(nil),       // 1: todolist.v1.UpdateMeRequest
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 3: google.protobuf.Empty
+}
+var file_todolist_v1_users_proto_depIdxs = []int32{
+	2, // 0: todolist.v1.User.created_at:type_name -> google.protobuf.Timestamp
+	2, // 1: todolist.v1.User.delete_at:type_name -> google.protobuf.Timestamp
+	3, // 2: todolist.v1.UsersService.GetMe:input_type -> google.protobuf.Empty
+	1, // 3: todolist.v1.UsersService.UpdateMe:input_type -> todolist.v1.UpdateMeRequest
+	0, // 4: todolist.v1.UsersService.GetMe:output_type -> todolist.v1.User
+	0, // 5: todolist.v1.UsersService.UpdateMe:output_type -> todolist.v1.User
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_todolist_v1_users_proto_init() }
+func file_todolist_v1_users_proto_init() {
+	if File_todolist_v1_users_proto != nil {
+		return
+	}
+	file_todolist_v1_users_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todolist_v1_users_proto_rawDesc), len(file_todolist_v1_users_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todolist_v1_users_proto_goTypes,
+		DependencyIndexes: file_todolist_v1_users_proto_depIdxs,
+		MessageInfos:      file_todolist_v1_users_proto_msgTypes,
+	}.Build()
+	File_todolist_v1_users_proto = out.File
+	file_todolist_v1_users_proto_goTypes = nil
+	file_todolist_v1_users_proto_depIdxs = nil
+}
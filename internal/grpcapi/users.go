@@ -0,0 +1,30 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/grpcapi/todolistv1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type usersServer struct {
+	todolistv1.UnimplementedUsersServiceServer
+	*Server
+}
+
+func (s *usersServer) GetMe(ctx context.Context, _ *emptypb.Empty) (*todolistv1.User, error) {
+	user, err := s.users.GetUser(principalFrom(ctx).UID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return userToProto(user), nil
+}
+
+func (s *usersServer) UpdateMe(ctx context.Context, req *todolistv1.UpdateMeRequest) (*todolistv1.User, error) {
+	user, err := s.users.UpdateProfile(principalFrom(ctx).UID, users.ProfilePatch{Name: req.Name, Email: req.Email})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return userToProto(user), nil
+}
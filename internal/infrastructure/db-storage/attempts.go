@@ -0,0 +1,68 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *DBStorage) GetAttempts(key string) (users.LoginAttempts, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	attempts := users.LoginAttempts{Key: key}
+	row := db.db.QueryRow(ctx, "SELECT failures, last_failure, locked_until FROM login_attempts WHERE key = $1", key)
+	err := row.Scan(&attempts.Failures, &attempts.LastFailure, &attempts.LockedUntil)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return users.LoginAttempts{}, err
+	}
+	return attempts, nil
+}
+
+// RecordFailure считает неудачу одним upsert, чтобы параллельные попытки с разных реплик не терялись.
+func (db *DBStorage) RecordFailure(key string, now time.Time, window time.Duration) (users.LoginAttempts, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	attempts := users.LoginAttempts{Key: key}
+	row := db.db.QueryRow(
+		ctx,
+		`INSERT INTO login_attempts(key, failures, last_failure) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure = EXCLUDED.last_failure
+		RETURNING failures, last_failure, locked_until`,
+		key,
+		now.UTC(),
+		now.Add(-window).UTC(),
+	)
+	if err := row.Scan(&attempts.Failures, &attempts.LastFailure, &attempts.LockedUntil); err != nil {
+		return users.LoginAttempts{}, err
+	}
+	return attempts, nil
+}
+
+func (db *DBStorage) LockUntil(key string, until time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(
+		ctx,
+		`INSERT INTO login_attempts(key, locked_until) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET locked_until = EXCLUDED.locked_until`,
+		key,
+		until.UTC(),
+	)
+	return err
+}
+
+func (db *DBStorage) ResetAttempts(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(ctx, "DELETE FROM login_attempts WHERE key = $1", key)
+	return err
+}
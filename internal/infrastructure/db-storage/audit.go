@@ -0,0 +1,167 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/jackc/pgx/v5"
+)
+
+// auditLockKey — ключ advisory-блокировки, под которой добавляются записи: цепочка
+// не должна ветвиться при параллельной записи.
+const auditLockKey = 420420
+
+const selectAuditQuery = "SELECT seq, type, actor_uid, target, ip, outcome, detail, created_at, prev_hash, hash" +
+	" FROM audit_log"
+
+func scanAuditEvent(row pgx.Row) (audit.Event, error) {
+	var event audit.Event
+	err := row.Scan(
+		&event.Seq,
+		&event.Type,
+		&event.ActorUID,
+		&event.Target,
+		&event.IP,
+		&event.Outcome,
+		&event.Detail,
+		&event.CreatedAt,
+		&event.PrevHash,
+		&event.Hash,
+	)
+	return event, err
+}
+
+// AppendAudit добавляет запись и логирует сбой: вызывающие не прерывают из-за него действие.
+func (db *DBStorage) AppendAudit(event audit.Event) (audit.Event, error) {
+	appended, err := db.appendAudit(event)
+	if err != nil {
+		db.log.Error().Err(err).Str("type", string(event.Type)).Msg("failed to append audit event")
+	}
+	return appended, err
+}
+
+func (db *DBStorage) appendAudit(event audit.Event) (audit.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return audit.Event{}, err
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			db.log.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", auditLockKey); err != nil {
+		return audit.Event{}, err
+	}
+	prev, err := scanAuditEvent(tx.QueryRow(ctx, selectAuditQuery+" ORDER BY seq DESC LIMIT 1"))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return audit.Event{}, err
+	}
+
+	event = audit.Chain(prev, event)
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO audit_log(seq, type, actor_uid, target, ip, outcome, detail, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		event.Seq,
+		event.Type,
+		event.ActorUID,
+		event.Target,
+		event.IP,
+		event.Outcome,
+		event.Detail,
+		event.CreatedAt,
+		event.PrevHash,
+		event.Hash,
+	)
+	if err != nil {
+		return audit.Event{}, err
+	}
+	return event, tx.Commit(ctx)
+}
+
+func (db *DBStorage) ListAudit(filter audit.Filter) ([]audit.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	var conds []string
+	var args []any
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, cond+" $"+strconv.Itoa(len(args)))
+	}
+	if filter.Type != `` {
+		where("type =", filter.Type)
+	}
+	if filter.ActorUID != `` {
+		where("actor_uid =", filter.ActorUID)
+	}
+	if filter.Target != `` {
+		where("target =", filter.Target)
+	}
+	if filter.IP != `` {
+		where("ip =", filter.IP)
+	}
+	if filter.Outcome != `` {
+		where("outcome =", filter.Outcome)
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >=", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		where("created_at <", filter.Until.UTC())
+	}
+
+	query := selectAuditQuery
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.PageSize())
+	query += " ORDER BY seq DESC LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := db.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]audit.Event, 0)
+	for rows.Next() {
+		event, scanErr := scanAuditEvent(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// WalkAudit читает журнал целиком от старых записей к новым, не держа его в памяти.
+func (db *DBStorage) WalkAudit(fn func(event audit.Event) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), streamTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, selectAuditQuery+" ORDER BY seq")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, scanErr := scanAuditEvent(rows)
+		if scanErr != nil {
+			return scanErr
+		}
+		if err = fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
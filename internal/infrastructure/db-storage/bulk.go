@@ -0,0 +1,106 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/jackc/pgx/v5"
+)
+
+// ApplyBulk выполняет пакет в одной транзакции.
+// В режиме best_effort каждая операция изолирована своей точкой сохранения.
+func (db *DBStorage) ApplyBulk(ops []notes.BulkOperation, mode notes.BulkMode) ([]notes.BulkResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			db.log.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+		}
+	}()
+
+	results := make([]notes.BulkResult, len(ops))
+	deleted := false
+	for i, op := range ops {
+		results[i] = notes.BulkResult{Index: i, Action: op.Action, NID: op.NID}
+
+		if mode == notes.BulkAtomic {
+			if err = applyBulkOperation(ctx, tx, op); err != nil {
+				results[i].Error = err.Error()
+				return results[:i+1], notes.ErrBulkFailed
+			}
+		} else if err = applyBulkSavepoint(ctx, tx, op); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		deleted = deleted || op.Action == notes.BulkDelete
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if deleted {
+		select {
+		case db.deleteChan <- struct{}{}:
+		default:
+		}
+	}
+	return results, nil
+}
+
+func applyBulkSavepoint(ctx context.Context, tx pgx.Tx, op notes.BulkOperation) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err = applyBulkOperation(ctx, savepoint, op); err != nil {
+		if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return savepoint.Commit(ctx)
+}
+
+func applyBulkOperation(ctx context.Context, tx pgx.Tx, op notes.BulkOperation) error {
+	var (
+		query string
+		args  []any
+	)
+	switch op.Action {
+	case notes.BulkCreate:
+		query = `INSERT INTO notes(nid, title, description, status, priority, position, created_at, user_id, deleted)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, false)`
+		args = []any{
+			op.Note.NID, op.Note.Title, op.Note.Description, op.Note.Status,
+			op.Note.Priority, op.Note.Position, op.Note.CreatedAt, op.Note.UID,
+		}
+	case notes.BulkUpdate:
+		query = `UPDATE notes SET title = $1, description = $2, status = $3, priority = $4
+		WHERE nid = $5 AND deleted = false`
+		args = []any{op.Note.Title, op.Note.Description, op.Note.Status, op.Note.Priority, op.NID}
+	case notes.BulkDelete:
+		query = "UPDATE notes SET deleted = true WHERE nid = $1 AND deleted = false"
+		args = []any{op.NID}
+	case notes.BulkStatus:
+		query = "UPDATE notes SET status = $1 WHERE nid = $2 AND deleted = false"
+		args = []any{op.Note.Status, op.NID}
+	default:
+		return notes.ErrInvalidBulkAction
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notes.ErrNoteNotFound
+	}
+	return nil
+}
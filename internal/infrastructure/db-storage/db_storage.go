@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Dorrrke/notes-g2/pkg/logger"
+	"github.com/Snoop-Duck/ToDoList/internal/metrics"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/rs/zerolog"
 
@@ -91,13 +92,24 @@ func (db *DBStorage) startBatchDeleter() {
 	}
 }
 
+// PoolStat возвращает статистику пула соединений для метрик.
+func (db *DBStorage) PoolStat() *pgxpool.Stat {
+	return db.db.Stat()
+}
+
 func (db *DBStorage) processBatchDeletion() error {
+	deleted, err := db.deleteBatch()
+	metrics.ObserveBatchDeletion(deleted, err)
+	return err
+}
+
+func (db *DBStorage) deleteBatch() (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
 	tx, err := db.db.Begin(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
@@ -105,7 +117,7 @@ func (db *DBStorage) processBatchDeletion() error {
 		}
 	}()
 
-	_, err = tx.Exec(ctx, `
+	tag, err := tx.Exec(ctx, `
 		DELETE FROM notes 
 		WHERE nid IN (
 			SELECT nid FROM notes 
@@ -115,8 +127,11 @@ func (db *DBStorage) processBatchDeletion() error {
 		)`, deleteBatchSize)
 
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return tx.Commit(ctx)
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
 }
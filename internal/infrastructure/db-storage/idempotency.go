@@ -0,0 +1,97 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/idempotency"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReserveIdempotencyKey занимает ключ одним upsert: истёкшая запись перезаписывается,
+// а живая остаётся и возвращается вызывающему.
+func (db *DBStorage) ReserveIdempotencyKey(record idempotency.Record, now time.Time) (idempotency.Record, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	var key string
+	err := db.db.QueryRow(
+		ctx,
+		`INSERT INTO idempotency_keys(key, fingerprint, created_at, expires_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			status = 0,
+			headers = '{}',
+			body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= $5
+		RETURNING key`,
+		record.Key,
+		record.Fingerprint,
+		record.CreatedAt.UTC(),
+		record.ExpiresAt.UTC(),
+		now.UTC(),
+	).Scan(&key)
+	if err == nil {
+		return record, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return idempotency.Record{}, false, err
+	}
+
+	existing := idempotency.Record{Key: record.Key}
+	err = db.db.QueryRow(
+		ctx,
+		`SELECT fingerprint, status, headers, body, created_at, expires_at
+		FROM idempotency_keys WHERE key = $1`,
+		record.Key,
+	).Scan(
+		&existing.Fingerprint,
+		&existing.Status,
+		&existing.Headers,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return idempotency.Record{}, false, err
+	}
+	return existing, false, nil
+}
+
+func (db *DBStorage) SaveIdempotentResponse(record idempotency.Record) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(
+		ctx,
+		"UPDATE idempotency_keys SET status = $2, headers = $3, body = $4, expires_at = $5 WHERE key = $1",
+		record.Key,
+		record.Status,
+		record.Headers,
+		record.Body,
+		record.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+func (db *DBStorage) ReleaseIdempotencyKey(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status = 0", key)
+	return err
+}
+
+func (db *DBStorage) PurgeIdempotencyKeys(now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
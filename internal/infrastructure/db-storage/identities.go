@@ -0,0 +1,63 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *DBStorage) SaveIdentity(identity users.Identity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(
+		ctx,
+		"INSERT INTO user_identities(issuer, subject, user_id) VALUES ($1, $2, $3)",
+		identity.Issuer,
+		identity.Subject,
+		identity.UID,
+	)
+	return err
+}
+
+func (db *DBStorage) GetUserByIdentity(issuer, subject string) (users.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	user, err := scanUser(db.db.QueryRow(
+		ctx,
+		selectUsersQuery+" WHERE uid = (SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2)",
+		issuer,
+		subject,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.User{}, users.ErrIdentityNotFound
+		}
+		return users.User{}, err
+	}
+	return user, nil
+}
+
+func (db *DBStorage) GetIdentities(userID string) ([]users.Identity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, "SELECT issuer, subject, user_id FROM user_identities WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := make([]users.Identity, 0)
+	for rows.Next() {
+		var identity users.Identity
+		if err = rows.Scan(&identity.Issuer, &identity.Subject, &identity.UID); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
@@ -2,14 +2,20 @@ package dbstorage
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/Dorrrke/notes-g2/pkg/logger"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/jackc/pgx/v5"
 )
 
 const (
 	contextTimeout = 5 * time.Second
+	streamTimeout  = 5 * time.Minute
+
+	selectNotesQuery = `SELECT nid, title, description, status, priority, position, created_at, user_id
+		FROM notes WHERE deleted = false`
 )
 
 func (db *DBStorage) AddNote(notes notes.Note) error {
@@ -18,11 +24,14 @@ func (db *DBStorage) AddNote(notes notes.Note) error {
 
 	_, err := db.db.Exec(
 		ctx,
-		"INSERT INTO note(nid, title, description, status, created_at, uid, deleted) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		`INSERT INTO notes(nid, title, description, status, priority, position, created_at, user_id, deleted)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 		notes.NID,
 		notes.Title,
 		notes.Description,
 		notes.Status,
+		notes.Priority,
+		notes.Position,
 		notes.CreatedAt,
 		notes.UID,
 		false,
@@ -34,14 +43,23 @@ func (db *DBStorage) AddNote(notes notes.Note) error {
 }
 
 func (db *DBStorage) GetNotes() ([]notes.Note, error) {
+	return db.queryNotes(selectNotesQuery)
+}
+
+func (db *DBStorage) GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error) {
+	orderBy := " ORDER BY position, created_at"
+	if sortBy == notes.SortPriority {
+		orderBy = " ORDER BY priority DESC, position, created_at"
+	}
+	return db.queryNotes(selectNotesQuery + orderBy)
+}
+
+func (db *DBStorage) queryNotes(query string) ([]notes.Note, error) {
 	log := logger.Get()
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	rows, err := db.db.Query(
-		ctx,
-		"SELECT nid, title, description, status, created_at, uid FROM notes WHERE deleted = false",
-	)
+	rows, err := db.db.Query(ctx, query)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get notes")
 		return nil, err
@@ -51,7 +69,10 @@ func (db *DBStorage) GetNotes() ([]notes.Note, error) {
 	var notesSlice []notes.Note
 	for rows.Next() {
 		var note notes.Note
-		if err = rows.Scan(&note.NID, &note.Title, &note.Description, &note.Status, &note.CreatedAt, &note.UID); err != nil {
+		if err = rows.Scan(
+			&note.NID, &note.Title, &note.Description, &note.Status,
+			&note.Priority, &note.Position, &note.CreatedAt, &note.UID,
+		); err != nil {
 			log.Error().Err(err).Msg("failed to scan note")
 			return nil, err
 		}
@@ -60,6 +81,32 @@ func (db *DBStorage) GetNotes() ([]notes.Note, error) {
 	return notesSlice, nil
 }
 
+// StreamNotes передаёт заметки в fn по одной строке, не собирая весь результат в памяти.
+func (db *DBStorage) StreamNotes(uid string, fn func(note notes.Note) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), streamTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, selectNotesQuery+" AND user_id = $1 ORDER BY position, created_at", uid)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note notes.Note
+		if err = rows.Scan(
+			&note.NID, &note.Title, &note.Description, &note.Status,
+			&note.Priority, &note.Position, &note.CreatedAt, &note.UID,
+		); err != nil {
+			return err
+		}
+		if err = fn(note); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (db *DBStorage) GetNoteID(noteID string) (notes.Note, error) {
 	log := logger.Get()
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
@@ -68,8 +115,12 @@ func (db *DBStorage) GetNoteID(noteID string) (notes.Note, error) {
 	var note notes.Note
 
 	row := db.db.QueryRow(ctx,
-		"SELECT nid, title, description, status, created_at, uid FROM notes WHERE nid = $1 AND deleted = false", noteID)
-	err := row.Scan(&note.NID, &note.Title, &note.Description, &note.Status, &note.CreatedAt, &note.UID)
+		`SELECT nid, title, description, status, priority, position, created_at, user_id
+		FROM notes WHERE nid = $1 AND deleted = false`, noteID)
+	err := row.Scan(
+		&note.NID, &note.Title, &note.Description, &note.Status,
+		&note.Priority, &note.Position, &note.CreatedAt, &note.UID,
+	)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get note")
 		return notes.Note{}, err
@@ -95,12 +146,111 @@ func (db *DBStorage) DeleteNote(noteID string) error {
 	return nil
 }
 
-func (db *DBStorage) UpdateNote(_ string, note notes.Note) error {
+// DeleteUserNotes удаляет заметки пользователя сразу, минуя пакетное удаление.
+func (db *DBStorage) DeleteUserNotes(uid string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "DELETE FROM notes WHERE user_id = $1", uid)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (db *DBStorage) UpdateNote(noteID string, note notes.Note) error {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
 	_, err := db.db.Exec(ctx,
-		"UPDATE notes SET title = $1, description = $2, status = $3 WHERE nid = $4 AND deleted = false",
-		note.Title, note.Description, note.Status, note.NID)
+		"UPDATE notes SET title = $1, description = $2, status = $3, priority = $4 WHERE nid = $5 AND deleted = false",
+		note.Title, note.Description, note.Status, note.Priority, noteID)
 	return err
 }
+
+func (db *DBStorage) MoveNote(noteID string, position string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx,
+		"UPDATE notes SET position = $1 WHERE nid = $2 AND deleted = false", position, noteID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notes.ErrNoteNotFound
+	}
+	return nil
+}
+
+func (db *DBStorage) MoveNoteToStatus(noteID string, status notes.Status, move notes.MoveRequest) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return ``, err
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			db.log.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+		}
+	}()
+
+	var nid string
+	err = tx.QueryRow(ctx,
+		"SELECT nid FROM notes WHERE nid = $1 AND deleted = false FOR UPDATE", noteID).Scan(&nid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ``, notes.ErrNoteNotFound
+	}
+	if err != nil {
+		return ``, err
+	}
+
+	lower, err := neighbourPosition(ctx, tx, move.After, status)
+	if err != nil {
+		return ``, err
+	}
+	upper, err := neighbourPosition(ctx, tx, move.Before, status)
+	if err != nil {
+		return ``, err
+	}
+	position, err := notes.RankBetween(lower, upper)
+	if err != nil {
+		return ``, err
+	}
+
+	if _, err = tx.Exec(ctx,
+		"UPDATE notes SET status = $1, position = $2 WHERE nid = $3", status, position, noteID); err != nil {
+		return ``, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return ``, err
+	}
+	return position, nil
+}
+
+func neighbourPosition(ctx context.Context, tx pgx.Tx, noteID string, status notes.Status) (string, error) {
+	if noteID == `` {
+		return ``, nil
+	}
+
+	var (
+		position       string
+		neighbourState notes.Status
+	)
+	err := tx.QueryRow(ctx,
+		"SELECT position, status FROM notes WHERE nid = $1 AND deleted = false FOR SHARE", noteID,
+	).Scan(&position, &neighbourState)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ``, notes.ErrNoteNotFound
+	}
+	if err != nil {
+		return ``, err
+	}
+	if neighbourState != status {
+		return ``, notes.ErrInvalidPosition
+	}
+	return position, nil
+}
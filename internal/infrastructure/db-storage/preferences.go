@@ -0,0 +1,54 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *DBStorage) GetPreferences(userID string) (users.Preferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	var prefs users.Preferences
+	err := db.db.QueryRow(
+		ctx,
+		`SELECT timezone, locale, default_status, default_sort, week_start
+		FROM user_preferences WHERE user_id = $1`,
+		userID,
+	).Scan(&prefs.Timezone, &prefs.Locale, &prefs.DefaultStatus, &prefs.DefaultSort, &prefs.WeekStart)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.Preferences{}, users.ErrPreferencesNotFound
+		}
+		return users.Preferences{}, err
+	}
+	return prefs, nil
+}
+
+func (db *DBStorage) SavePreferences(userID string, prefs users.Preferences) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(
+		ctx,
+		`INSERT INTO user_preferences(user_id, timezone, locale, default_status, default_sort, week_start)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			timezone = EXCLUDED.timezone,
+			locale = EXCLUDED.locale,
+			default_status = EXCLUDED.default_status,
+			default_sort = EXCLUDED.default_sort,
+			week_start = EXCLUDED.week_start,
+			updated_at = NOW()`,
+		userID,
+		prefs.Timezone,
+		prefs.Locale,
+		prefs.DefaultStatus,
+		prefs.DefaultSort,
+		prefs.WeekStart,
+	)
+	return err
+}
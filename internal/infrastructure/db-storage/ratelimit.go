@@ -0,0 +1,66 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/ratelimit"
+	"github.com/jackc/pgx/v5"
+)
+
+// TakeToken берёт токен из общей для всех реплик корзины. Строка корзины блокируется
+// на время транзакции, поэтому параллельные запросы не расходуют один токен дважды.
+func (db *DBStorage) TakeToken(key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return ratelimit.Decision{}, err
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			db.log.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+		}
+	}()
+
+	_, err = tx.Exec(ctx, "INSERT INTO rate_limits(key) VALUES ($1) ON CONFLICT (key) DO NOTHING", key)
+	if err != nil {
+		return ratelimit.Decision{}, err
+	}
+	var bucket ratelimit.Bucket
+	var updatedAt *time.Time
+	err = tx.QueryRow(ctx, "SELECT tokens, updated_at FROM rate_limits WHERE key = $1 FOR UPDATE", key).
+		Scan(&bucket.Tokens, &updatedAt)
+	if err != nil {
+		return ratelimit.Decision{}, err
+	}
+	if updatedAt != nil {
+		bucket.UpdatedAt = *updatedAt
+	}
+
+	bucket, decision := limit.Take(bucket, now.UTC())
+	_, err = tx.Exec(
+		ctx,
+		"UPDATE rate_limits SET tokens = $2, updated_at = $3 WHERE key = $1",
+		key,
+		bucket.Tokens,
+		bucket.UpdatedAt,
+	)
+	if err != nil {
+		return ratelimit.Decision{}, err
+	}
+	return decision, tx.Commit(ctx)
+}
+
+func (db *DBStorage) PurgeRateLimits(idleSince time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "DELETE FROM rate_limits WHERE updated_at < $1", idleSince.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
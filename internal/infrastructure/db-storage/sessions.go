@@ -0,0 +1,102 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/jackc/pgx/v5"
+)
+
+const selectSessionsQuery = "SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at FROM sessions"
+
+func scanSession(row pgx.Row) (users.Session, error) {
+	var session users.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+	)
+	return session, err
+}
+
+func (db *DBStorage) SaveSession(session users.Session) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(
+		ctx,
+		`INSERT INTO sessions(id, user_id, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		session.ID,
+		session.UID,
+		session.UserAgent,
+		session.IP,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+	return err
+}
+
+func (db *DBStorage) GetSessions(userID string) ([]users.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, selectSessionsQuery+" WHERE user_id = $1 ORDER BY last_seen_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]users.Session, 0)
+	for rows.Next() {
+		session, scanErr := scanSession(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (db *DBStorage) GetSession(sessionID string) (users.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	session, err := scanSession(db.db.QueryRow(ctx, selectSessionsQuery+" WHERE id = $1", sessionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.Session{}, users.ErrSessionNotFound
+		}
+		return users.Session{}, err
+	}
+	return session, nil
+}
+
+func (db *DBStorage) TouchSession(sessionID string, lastSeen time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(ctx, "UPDATE sessions SET last_seen_at = $1 WHERE id = $2", lastSeen, sessionID)
+	return err
+}
+
+func (db *DBStorage) DeleteSession(userID, sessionID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "DELETE FROM sessions WHERE id = $1 AND user_id = $2", sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrSessionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/jackc/pgx/v5"
+)
+
+const selectAccessTokensQuery = "SELECT id, user_id, name, token_hash, scopes, created_at, expires_at FROM access_tokens"
+
+func scanAccessToken(row pgx.Row) (users.AccessToken, error) {
+	var token users.AccessToken
+	var scopes []string
+	err := row.Scan(&token.ID, &token.UID, &token.Name, &token.Hash, &scopes, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		return users.AccessToken{}, err
+	}
+	token.Scopes = make([]users.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		token.Scopes = append(token.Scopes, users.Scope(scope))
+	}
+	return token, nil
+}
+
+func (db *DBStorage) SaveAccessToken(token users.AccessToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	scopes := make([]string, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	_, err := db.db.Exec(
+		ctx,
+		`INSERT INTO access_tokens(id, user_id, name, token_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		token.ID,
+		token.UID,
+		token.Name,
+		token.Hash,
+		scopes,
+		token.CreatedAt,
+		token.ExpiresAt,
+	)
+	return err
+}
+
+func (db *DBStorage) GetAccessTokens(userID string) ([]users.AccessToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, selectAccessTokensQuery+" WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]users.AccessToken, 0)
+	for rows.Next() {
+		token, scanErr := scanAccessToken(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (db *DBStorage) GetAccessTokenByHash(hash string) (users.AccessToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	token, err := scanAccessToken(db.db.QueryRow(ctx, selectAccessTokensQuery+" WHERE token_hash = $1", hash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.AccessToken{}, users.ErrTokenNotFound
+		}
+		return users.AccessToken{}, err
+	}
+	return token, nil
+}
+
+func (db *DBStorage) DeleteAccessToken(userID, tokenID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "DELETE FROM access_tokens WHERE id = $1 AND user_id = $2", tokenID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrTokenNotFound
+	}
+	return nil
+}
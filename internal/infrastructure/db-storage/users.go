@@ -2,38 +2,107 @@ package dbstorage
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// uniqueViolation — код ошибки PostgreSQL unique_violation.
+const uniqueViolation = "23505"
+
+const selectUsersQuery = "SELECT uid, name, email, password, verified, created_at, password_changed_at," +
+	" totp_secret, totp_enabled, recovery_codes, totp_last_step, delete_at FROM users"
+
+func scanUser(row pgx.Row) (users.User, error) {
+	var user users.User
+	err := row.Scan(
+		&user.UID,
+		&user.Name,
+		&user.Email,
+		&user.Password,
+		&user.Verified,
+		&user.CreatedAt,
+		&user.PasswordChangedAt,
+		&user.TOTP.Secret,
+		&user.TOTP.Enabled,
+		&user.TOTP.RecoveryCodes,
+		&user.TOTP.LastStep,
+		&user.DeleteAt,
+	)
+	return user, err
+}
+
 func (db *DBStorage) SaveUser(user users.User) error {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
 	_, err := db.db.Exec(
 		ctx,
-		"INSERT INTO users(uid, name, email, password) VALUES ($1, $2, $3, $4)",
+		"INSERT INTO users(uid, name, email, password, verified, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
 		user.UID,
 		user.Name,
 		user.Email,
 		user.Password,
+		user.Verified,
+		user.CreatedAt,
 	)
 
 	if err != nil {
-		return err
+		return uniqueEmailError(err)
 	}
 
 	return nil
 }
 
-func (db *DBStorage) GetUser(login string) (users.User, error) {
+// uniqueEmailError переводит нарушение уникального индекса по lower(email) в доменную ошибку.
+func uniqueEmailError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return users.ErrUserAlredyExists
+	}
+	return err
+}
+
+// FindEmailDuplicates находит аккаунты, адреса которых совпадают без учёта регистра.
+// Такие группы не дают применить миграцию с уникальным индексом по lower(email).
+func (db *DBStorage) FindEmailDuplicates() ([]users.EmailDuplicate, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	var user users.User
+	rows, err := db.db.Query(ctx, `SELECT lower(email), uid, email, created_at FROM users
+		WHERE lower(email) IN (SELECT lower(email) FROM users GROUP BY lower(email) HAVING count(*) > 1)
+		ORDER BY lower(email), created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	row := db.db.QueryRow(ctx, "SELECT * FROM users WHERE email = $1", login)
-	err := row.Scan(&user.UID, &user.Name, &user.Email, &user.Password)
+	duplicates := make([]users.EmailDuplicate, 0)
+	for rows.Next() {
+		var (
+			normalized string
+			account    users.DuplicateAccount
+		)
+		if err = rows.Scan(&normalized, &account.UID, &account.Email, &account.CreatedAt); err != nil {
+			return nil, err
+		}
+		if len(duplicates) == 0 || duplicates[len(duplicates)-1].Email != normalized {
+			duplicates = append(duplicates, users.EmailDuplicate{Email: normalized})
+		}
+		last := &duplicates[len(duplicates)-1]
+		last.Accounts = append(last.Accounts, account)
+	}
+	return duplicates, rows.Err()
+}
+
+func (db *DBStorage) GetUser(login string) (users.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	user, err := scanUser(db.db.QueryRow(ctx, selectUsersQuery+" WHERE lower(email) = lower($1)", login))
 	if err != nil {
 		return users.User{}, err
 	}
@@ -41,31 +110,84 @@ func (db *DBStorage) GetUser(login string) (users.User, error) {
 	return user, nil
 }
 
+// DeleteUser удаляет пользователя вместе с заметками в одной транзакции; остальные
+// связанные таблицы очищаются через ON DELETE CASCADE.
 func (db *DBStorage) DeleteUser(userID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	_, err := db.db.Exec(ctx, "DELETE FROM users WHERE id = $1", userID)
+	tx, err := db.db.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			db.log.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+		}
+	}()
+
+	if _, err = tx.Exec(ctx, "DELETE FROM notes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, "DELETE FROM users WHERE uid = $1", userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrUserNotFound
+	}
+	return tx.Commit(ctx)
+}
+
+func (db *DBStorage) ScheduleDeletion(userID string, deleteAt *time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "UPDATE users SET delete_at = $1 WHERE uid = $2", deleteAt, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrUserNotFound
+	}
 	return nil
 }
 
+func (db *DBStorage) GetUsersDueForDeletion(now time.Time) ([]users.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, selectUsersQuery+" WHERE delete_at IS NOT NULL AND delete_at <= $1", now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := make([]users.User, 0)
+	for rows.Next() {
+		user, scanErr := scanUser(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		due = append(due, user)
+	}
+	return due, rows.Err()
+}
+
 func (db *DBStorage) GetAllUsers() ([]users.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	rows, err := db.db.Query(ctx, "SELECT * FROM users")
+	rows, err := db.db.Query(ctx, selectUsersQuery)
 	if err != nil {
 		return nil, err
 	}
 
 	var usersSlice []users.User
 	for rows.Next() {
-		var user users.User
-		if err = rows.Scan(&user.UID, &user.Name, &user.Email, &user.Password); err != nil {
-			return nil, err
+		user, scanErr := scanUser(rows)
+		if scanErr != nil {
+			return nil, scanErr
 		}
 		usersSlice = append(usersSlice, user)
 	}
@@ -76,9 +198,7 @@ func (db *DBStorage) GetUserID(userID string) (users.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	var user users.User
-	row := db.db.QueryRow(ctx, "SELECT * FROM users WHERE uid = $1", userID)
-	err := row.Scan(&user.UID, &user.Name, &user.Email, &user.Password)
+	user, err := scanUser(db.db.QueryRow(ctx, selectUsersQuery+" WHERE uid = $1", userID))
 	if err != nil {
 		return users.User{}, err
 	}
@@ -89,9 +209,156 @@ func (db *DBStorage) UpdateUserID(userID string, user users.User) error {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
 
-	_, err := db.db.Exec(ctx, "UPDATE users SET name = $1, email = $2 WHERE uid = $3", user.Name, user.Email, userID)
+	// Смена адреса снимает подтверждение: новый адрес ещё никто не подтвердил.
+	_, err := db.db.Exec(ctx,
+		"UPDATE users SET name = $1, email = $2, verified = verified AND lower(email) = lower($2) WHERE uid = $3",
+		user.Name, user.Email, userID)
+	if err != nil {
+		return uniqueEmailError(err)
+	}
+	return nil
+}
+
+func (db *DBStorage) VerifyUser(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "UPDATE users SET verified = true WHERE uid = $1", userID)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrUserNotFound
+	}
+	return nil
+}
+
+func (db *DBStorage) SaveResetToken(token users.ResetToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	_, err := db.db.Exec(
+		ctx,
+		"INSERT INTO password_resets(token_hash, user_id, expires_at) VALUES ($1, $2, $3)",
+		token.Hash,
+		token.UID,
+		token.ExpiresAt,
+	)
+	return err
+}
+
+// ConsumeResetToken удаляет токен в том же запросе, что и читает, поэтому его нельзя использовать дважды.
+func (db *DBStorage) ConsumeResetToken(hash string) (users.ResetToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	token := users.ResetToken{Hash: hash}
+	row := db.db.QueryRow(ctx, "DELETE FROM password_resets WHERE token_hash = $1 RETURNING user_id, expires_at", hash)
+	if err := row.Scan(&token.UID, &token.ExpiresAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return users.ResetToken{}, users.ErrInvalidToken
+		}
+		return users.ResetToken{}, err
+	}
+	return token, nil
+}
+
+func (db *DBStorage) ResetPassword(userID, password string, changedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tx, err := db.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			db.log.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+		}
+	}()
+
+	tag, err := tx.Exec(
+		ctx,
+		"UPDATE users SET password = $1, password_changed_at = $2 WHERE uid = $3",
+		password,
+		changedAt,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrUserNotFound
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM password_resets WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, "DELETE FROM sessions WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (db *DBStorage) UpdateTOTP(userID string, totp users.TOTP) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	codes := totp.RecoveryCodes
+	if codes == nil {
+		codes = []string{}
+	}
+	tag, err := db.db.Exec(
+		ctx,
+		"UPDATE users SET totp_secret = $1, totp_enabled = $2, recovery_codes = $3 WHERE uid = $4",
+		totp.Secret,
+		totp.Enabled,
+		codes,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrUserNotFound
+	}
+	return nil
+}
+
+// UseTOTPStep сдвигает последний принятый интервал одним UPDATE, поэтому код одного
+// интервала принимается один раз даже при параллельных запросах.
+func (db *DBStorage) UseTOTPStep(userID string, step int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx,
+		"UPDATE users SET totp_last_step = $2 WHERE uid = $1 AND totp_last_step < $2", userID, step)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrInvalidCode
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode удаляет код одним UPDATE, поэтому один код нельзя использовать дважды.
+func (db *DBStorage) ConsumeRecoveryCode(userID, codeHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(
+		ctx,
+		`UPDATE users SET recovery_codes = array_remove(recovery_codes, $2)
+		WHERE uid = $1 AND $2 = ANY(recovery_codes)`,
+		userID,
+		codeHash,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return users.ErrInvalidCode
+	}
 	return nil
 }
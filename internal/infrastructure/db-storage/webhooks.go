@@ -0,0 +1,282 @@
+package dbstorage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	selectWebhooksQuery = "SELECT id, user_id, url, events, secret, created_at FROM webhooks"
+
+	deliveryColumns = "id, webhook_id, user_id, event, nid, payload, status, attempts," +
+		" next_attempt_at, last_attempt_at, response_code, last_error, created_at"
+	selectDeliveriesQuery = "SELECT " + deliveryColumns + " FROM webhook_deliveries"
+)
+
+func scanWebhook(row pgx.Row) (webhooks.Webhook, error) {
+	var hook webhooks.Webhook
+	var events []string
+	err := row.Scan(&hook.ID, &hook.UID, &hook.URL, &events, &hook.Secret, &hook.CreatedAt)
+	if err != nil {
+		return webhooks.Webhook{}, err
+	}
+	hook.Events = make([]notes.EventType, 0, len(events))
+	for _, event := range events {
+		hook.Events = append(hook.Events, notes.EventType(event))
+	}
+	return hook, nil
+}
+
+func scanDelivery(row pgx.Row) (webhooks.Delivery, error) {
+	var delivery webhooks.Delivery
+	var payload string
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.UID,
+		&delivery.Event,
+		&delivery.NID,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastAttemptAt,
+		&delivery.ResponseCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+	)
+	delivery.Payload = []byte(payload)
+	return delivery, err
+}
+
+func (db *DBStorage) SaveWebhook(hook webhooks.Webhook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	events := make([]string, 0, len(hook.Events))
+	for _, event := range hook.Events {
+		events = append(events, string(event))
+	}
+	_, err := db.db.Exec(
+		ctx,
+		`INSERT INTO webhooks(id, user_id, url, events, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		hook.ID,
+		hook.UID,
+		hook.URL,
+		events,
+		hook.Secret,
+		hook.CreatedAt,
+	)
+	return err
+}
+
+func (db *DBStorage) GetWebhooks(userID string) ([]webhooks.Webhook, error) {
+	return db.queryWebhooks(selectWebhooksQuery+" WHERE user_id = $1 ORDER BY created_at", userID)
+}
+
+func (db *DBStorage) queryWebhooks(query string, args ...any) ([]webhooks.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := make([]webhooks.Webhook, 0)
+	for rows.Next() {
+		hook, scanErr := scanWebhook(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+func (db *DBStorage) GetWebhook(webhookID string) (webhooks.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	hook, err := scanWebhook(db.db.QueryRow(ctx, selectWebhooksQuery+" WHERE id = $1", webhookID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhooks.Webhook{}, webhooks.ErrWebhookNotFound
+		}
+		return webhooks.Webhook{}, err
+	}
+	return hook, nil
+}
+
+// DeleteWebhook удаляет подписку; её доставки удаляются каскадно.
+func (db *DBStorage) DeleteWebhook(userID, webhookID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(ctx, "DELETE FROM webhooks WHERE id = $1 AND user_id = $2", webhookID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return webhooks.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (db *DBStorage) EnqueueDeliveries(deliveries []webhooks.Delivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, delivery := range deliveries {
+		batch.Queue(
+			`INSERT INTO webhook_deliveries(`+deliveryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+			delivery.ID,
+			delivery.WebhookID,
+			delivery.UID,
+			delivery.Event,
+			delivery.NID,
+			string(delivery.Payload),
+			delivery.Status,
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.LastAttemptAt,
+			delivery.ResponseCode,
+			delivery.LastError,
+			delivery.CreatedAt,
+		)
+	}
+	return db.db.SendBatch(ctx, batch).Close()
+}
+
+// ClaimDeliveries откладывает выбранные доставки на lease одним запросом; SKIP LOCKED
+// не даёт двум экземплярам сервиса забрать одну доставку.
+func (db *DBStorage) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]webhooks.Delivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	rows, err := db.db.Query(
+		ctx,
+		`UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		now,
+		now.Add(lease),
+		webhooks.StatusPending,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhooks.Delivery, 0)
+	for rows.Next() {
+		delivery, scanErr := scanDelivery(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (db *DBStorage) UpdateDelivery(delivery webhooks.Delivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	tag, err := db.db.Exec(
+		ctx,
+		`UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_attempt_at = $5, response_code = $6, last_error = $7
+		WHERE id = $1`,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastAttemptAt,
+		delivery.ResponseCode,
+		delivery.LastError,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return webhooks.ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func (db *DBStorage) GetDeliveries(filter webhooks.DeliveryFilter) ([]webhooks.Delivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	var conds []string
+	var args []any
+	where := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, cond+" $"+strconv.Itoa(len(args)))
+	}
+	if filter.UID != `` {
+		where("user_id =", filter.UID)
+	}
+	if filter.WebhookID != `` {
+		where("webhook_id =", filter.WebhookID)
+	}
+	if filter.Status != `` {
+		where("status =", filter.Status)
+	}
+
+	query := selectDeliveriesQuery
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.PageSize())
+	query += " ORDER BY created_at DESC, id LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := db.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhooks.Delivery, 0)
+	for rows.Next() {
+		delivery, scanErr := scanDelivery(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (db *DBStorage) GetDelivery(deliveryID string) (webhooks.Delivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	delivery, err := scanDelivery(db.db.QueryRow(ctx, selectDeliveriesQuery+" WHERE id = $1", deliveryID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return webhooks.Delivery{}, webhooks.ErrDeliveryNotFound
+		}
+		return webhooks.Delivery{}, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,63 @@
+package inmemory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+type Attempts struct {
+	mu       sync.Mutex
+	attempts map[string]users.LoginAttempts
+}
+
+func NewAttempts() *Attempts {
+	return &Attempts{attempts: make(map[string]users.LoginAttempts)}
+}
+
+func (im *Attempts) GetAttempts(key string) (users.LoginAttempts, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	attempts, ok := im.attempts[key]
+	if !ok {
+		return users.LoginAttempts{Key: key}, nil
+	}
+	return attempts, nil
+}
+
+func (im *Attempts) RecordFailure(key string, now time.Time, window time.Duration) (users.LoginAttempts, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	attempts, ok := im.attempts[key]
+	if !ok || attempts.LastFailure.Before(now.Add(-window)) {
+		attempts = users.LoginAttempts{Key: key, LockedUntil: attempts.LockedUntil}
+	}
+	attempts.Failures++
+	attempts.LastFailure = now
+	im.attempts[key] = attempts
+	return attempts, nil
+}
+
+func (im *Attempts) LockUntil(key string, until time.Time) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	attempts, ok := im.attempts[key]
+	if !ok {
+		attempts = users.LoginAttempts{Key: key}
+	}
+	attempts.LockedUntil = until
+	im.attempts[key] = attempts
+	return nil
+}
+
+func (im *Attempts) ResetAttempts(key string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	delete(im.attempts, key)
+	return nil
+}
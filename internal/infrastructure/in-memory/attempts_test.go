@@ -0,0 +1,40 @@
+package inmemory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttempts(t *testing.T) {
+	store := NewAttempts()
+	now := time.Now()
+
+	attempts, err := store.GetAttempts("account:bob")
+	require.NoError(t, err)
+	assert.Zero(t, attempts.Failures)
+
+	for i := 1; i <= 3; i++ {
+		attempts, err = store.RecordFailure("account:bob", now, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, i, attempts.Failures)
+	}
+
+	require.NoError(t, store.LockUntil("account:bob", now.Add(time.Minute)))
+	attempts, err = store.GetAttempts("account:bob")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), attempts.LockedUntil)
+
+	// Неудача после окна начинает счёт заново.
+	attempts, err = store.RecordFailure("account:bob", now.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts.Failures)
+
+	require.NoError(t, store.ResetAttempts("account:bob"))
+	attempts, err = store.GetAttempts("account:bob")
+	require.NoError(t, err)
+	assert.Zero(t, attempts.Failures)
+	assert.True(t, attempts.LockedUntil.IsZero())
+}
@@ -0,0 +1,71 @@
+package inmemory
+
+import (
+	"sync"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+)
+
+// AuditLog — журнал аудита в кольцевом буфере: хранятся последние capacity событий,
+// цепочка хэшей продолжается и после вытеснения старых записей.
+type AuditLog struct {
+	mu     sync.RWMutex
+	events []audit.Event
+	next   int
+	size   int
+	last   audit.Event
+}
+
+func NewAuditLog(capacity int) *AuditLog {
+	return &AuditLog{events: make([]audit.Event, max(capacity, 1))}
+}
+
+func (im *AuditLog) AppendAudit(event audit.Event) (audit.Event, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	event = audit.Chain(im.last, event)
+	im.events[im.next] = event
+	im.next = (im.next + 1) % len(im.events)
+	im.size = min(im.size+1, len(im.events))
+	im.last = event
+	return event, nil
+}
+
+// Truncated сообщает, что старые записи уже вытеснены из буфера.
+func (im *AuditLog) Truncated() bool {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return im.last.Seq > int64(im.size)
+}
+
+// ListAudit возвращает подходящие события, начиная с самых новых.
+func (im *AuditLog) ListAudit(filter audit.Filter) ([]audit.Event, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	limit := filter.PageSize()
+	events := make([]audit.Event, 0)
+	for i := 1; i <= im.size && len(events) < limit; i++ {
+		event := im.events[(im.next-i+len(im.events))%len(im.events)]
+		if filter.Match(event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// WalkAudit передаёт fn все хранящиеся события от старых к новым.
+func (im *AuditLog) WalkAudit(fn func(event audit.Event) error) error {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	start := (im.next - im.size + len(im.events)) % len(im.events)
+	for i := range im.size {
+		if err := fn(im.events[(start+i)%len(im.events)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRingBuffer(t *testing.T) {
+	store := NewAuditLog(3)
+	for _, uid := range []string{"uid-1", "uid-2", "uid-1", "uid-2", "uid-1"} {
+		_, err := store.AppendAudit(audit.Event{Type: audit.EventLogin, ActorUID: uid, Outcome: audit.OutcomeSuccess})
+		require.NoError(t, err)
+	}
+
+	events, err := store.ListAudit(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, []int64{5, 4, 3}, []int64{events[0].Seq, events[1].Seq, events[2].Seq})
+
+	events, err = store.ListAudit(audit.Filter{ActorUID: "uid-2"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(4), events[0].Seq)
+
+	require.True(t, store.Truncated())
+	verifier := audit.Verifier{Tail: true}
+	require.NoError(t, store.WalkAudit(verifier.Check))
+	require.Error(t, store.WalkAudit(new(audit.Verifier).Check), "without Tail the evicted head is a gap")
+	assert.Equal(t, 3, verifier.Report(nil).Checked)
+}
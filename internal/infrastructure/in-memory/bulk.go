@@ -0,0 +1,52 @@
+package inmemory
+
+import (
+	"maps"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+func (im *Notes) ApplyBulk(ops []notes.BulkOperation, mode notes.BulkMode) ([]notes.BulkResult, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	snapshot := maps.Clone(im.noteStorage)
+	results := make([]notes.BulkResult, len(ops))
+	for i, op := range ops {
+		results[i] = notes.BulkResult{Index: i, Action: op.Action, NID: op.NID}
+		if err := im.applyBulkOperation(op); err != nil {
+			results[i].Error = err.Error()
+			if mode == notes.BulkAtomic {
+				im.noteStorage = snapshot
+				return results[:i+1], notes.ErrBulkFailed
+			}
+		}
+	}
+
+	if err := im.SaveToFile(); err != nil {
+		im.noteStorage = snapshot
+		return nil, err
+	}
+	return results, nil
+}
+
+func (im *Notes) applyBulkOperation(op notes.BulkOperation) error {
+	switch op.Action {
+	case notes.BulkCreate:
+		return im.addNote(op.Note)
+	case notes.BulkUpdate:
+		return im.updateNote(op.NID, op.Note)
+	case notes.BulkDelete:
+		return im.deleteNote(op.NID)
+	case notes.BulkStatus:
+		note, ok := im.noteStorage[op.NID]
+		if !ok {
+			return notes.ErrNoteNotFound
+		}
+		note.Status = op.Note.Status
+		im.noteStorage[op.NID] = note
+		return nil
+	default:
+		return notes.ErrInvalidBulkAction
+	}
+}
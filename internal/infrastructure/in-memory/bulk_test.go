@@ -0,0 +1,52 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBulk(t *testing.T) {
+	newStorage := func(t *testing.T) *Notes {
+		im := NewNotes(false, t.TempDir()+"/notes_test.json")
+		require.NoError(t, im.AddNote(notes.Note{NID: "1", Title: "Note 1", Status: notes.New}))
+		require.NoError(t, im.AddNote(notes.Note{NID: "2", Title: "Note 2", Status: notes.New}))
+		return im
+	}
+
+	ops := []notes.BulkOperation{
+		{Action: notes.BulkCreate, NID: "3", Note: notes.Note{NID: "3", Title: "Note 3"}},
+		{Action: notes.BulkStatus, NID: "1", Note: notes.Note{Status: notes.Active}},
+		{Action: notes.BulkDelete, NID: "999"},
+		{Action: notes.BulkDelete, NID: "2"},
+	}
+
+	t.Run("atomic rolls back on failure", func(t *testing.T) {
+		im := newStorage(t)
+
+		results, err := im.ApplyBulk(ops, notes.BulkAtomic)
+
+		assert.ErrorIs(t, err, notes.ErrBulkFailed)
+		require.Len(t, results, 3)
+		assert.Equal(t, notes.ErrNoteNotFound.Error(), results[2].Error)
+		assert.NotContains(t, im.noteStorage, "3")
+		assert.Contains(t, im.noteStorage, "2")
+		assert.Equal(t, notes.Status(notes.New), im.noteStorage["1"].Status)
+	})
+
+	t.Run("best effort applies the rest", func(t *testing.T) {
+		im := newStorage(t)
+
+		results, err := im.ApplyBulk(ops, notes.BulkBestEffort)
+
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Empty(t, results[0].Error)
+		assert.Equal(t, notes.ErrNoteNotFound.Error(), results[2].Error)
+		assert.Contains(t, im.noteStorage, "3")
+		assert.NotContains(t, im.noteStorage, "2")
+		assert.Equal(t, notes.Status(notes.Active), im.noteStorage["1"].Status)
+	})
+}
@@ -0,0 +1,116 @@
+package inmemory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+// EventBus — шина событий заметок внутри процесса. Последние события хранятся в кольцевом
+// буфере, чтобы переподключившийся клиент дочитал пропущенное по Last-Event-ID.
+// Publish никогда не ждёт подписчиков: у каждого свой буфер, и переполнивший его
+// подписчик отключается — он продолжит с последнего полученного события.
+type EventBus struct {
+	mu          sync.Mutex
+	history     []notes.Event
+	next        int
+	size        int
+	lastID      uint64
+	buffer      int
+	subscribers map[*subscription]struct{}
+}
+
+// NewEventBus создаёт шину, хранящую history последних событий; buffer — сколько
+// непрочитанных событий может накопить подписчик.
+func NewEventBus(history, buffer int) *EventBus {
+	return &EventBus{
+		history: make([]notes.Event, max(history, 1)),
+		buffer:  max(buffer, 1),
+		// Нумерация начинается с текущего времени, поэтому Last-Event-ID, полученный
+		// до перезапуска, не совпадёт с новыми событиями и потребует перечитать заметки.
+		lastID:      uint64(time.Now().UnixMicro()),
+		subscribers: make(map[*subscription]struct{}),
+	}
+}
+
+func (im *EventBus) Publish(event notes.Event) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.lastID++
+	event.ID = im.lastID
+	im.history[im.next] = event
+	im.next = (im.next + 1) % len(im.history)
+	im.size = min(im.size+1, len(im.history))
+
+	for sub := range im.subscribers {
+		if !event.VisibleTo(sub.uid) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			im.drop(sub, notes.ErrSubscriberLagging)
+		}
+	}
+}
+
+// Subscribe подписывает пользователя uid на видимые ему события. При lastID != 0 возвращаются
+// события после него; если часть из них уже вытеснена из истории, возвращается notes.ErrEventsExpired.
+func (im *EventBus) Subscribe(uid string, lastID uint64) ([]notes.Event, notes.Subscription, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	var backlog []notes.Event
+	if lastID != 0 && lastID != im.lastID {
+		oldest := im.lastID - uint64(im.size) + 1
+		if lastID > im.lastID || lastID+1 < oldest {
+			return nil, nil, notes.ErrEventsExpired
+		}
+		start := (im.next - im.size + len(im.history)) % len(im.history)
+		for i := range im.size {
+			event := im.history[(start+i)%len(im.history)]
+			if event.ID > lastID && event.VisibleTo(uid) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	sub := &subscription{bus: im, uid: uid, events: make(chan notes.Event, im.buffer)}
+	im.subscribers[sub] = struct{}{}
+	return backlog, sub, nil
+}
+
+// drop отключает подписчика; вызывается под im.mu.
+func (im *EventBus) drop(sub *subscription, err error) {
+	if _, ok := im.subscribers[sub]; !ok {
+		return
+	}
+	delete(im.subscribers, sub)
+	sub.err = err
+	close(sub.events)
+}
+
+type subscription struct {
+	bus    *EventBus
+	uid    string
+	events chan notes.Event
+	err    error
+}
+
+func (s *subscription) Events() <-chan notes.Event {
+	return s.events
+}
+
+func (s *subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.err
+}
+
+func (s *subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.drop(s, nil)
+}
@@ -0,0 +1,84 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBusVisibility(t *testing.T) {
+	bus := NewEventBus(10, 10)
+	_, sub, err := bus.Subscribe("alice", 0)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	bus.Publish(notes.Event{Type: notes.EventCreated, NID: "bob-note", UID: "bob"})
+	bus.Publish(notes.Event{Type: notes.EventCreated, NID: "alice-note", UID: "alice"})
+	bus.Publish(notes.Event{Type: notes.EventDeleted, NID: "board-note"})
+
+	first, second := <-sub.Events(), <-sub.Events()
+	assert.Equal(t, "alice-note", first.NID)
+	assert.Equal(t, "board-note", second.NID)
+	assert.Less(t, first.ID, second.ID)
+	assert.Empty(t, sub.Events())
+}
+
+func TestEventBusResume(t *testing.T) {
+	bus := NewEventBus(3, 10)
+	_, sub, err := bus.Subscribe("alice", 0)
+	require.NoError(t, err)
+	sub.Close()
+
+	for _, nid := range []string{"n1", "n2", "n3", "n4"} {
+		bus.Publish(notes.Event{Type: notes.EventCreated, NID: nid, UID: "alice"})
+	}
+	// В истории остались n2..n4.
+	history, sub, err := bus.Subscribe("alice", 0)
+	require.NoError(t, err)
+	sub.Close()
+	assert.Empty(t, history)
+
+	last := bus.lastID
+	backlog, sub, err := bus.Subscribe("alice", last-2)
+	require.NoError(t, err)
+	sub.Close()
+	require.Len(t, backlog, 2)
+	assert.Equal(t, "n3", backlog[0].NID)
+	assert.Equal(t, "n4", backlog[1].NID)
+
+	backlog, sub, err = bus.Subscribe("alice", last-3)
+	require.NoError(t, err)
+	sub.Close()
+	assert.Len(t, backlog, 3)
+
+	_, _, err = bus.Subscribe("alice", last-4)
+	require.ErrorIs(t, err, notes.ErrEventsExpired)
+	_, _, err = bus.Subscribe("alice", last+1)
+	require.ErrorIs(t, err, notes.ErrEventsExpired)
+}
+
+func TestEventBusDropsLaggingSubscriber(t *testing.T) {
+	bus := NewEventBus(10, 2)
+	_, slow, err := bus.Subscribe("alice", 0)
+	require.NoError(t, err)
+	_, fast, err := bus.Subscribe("alice", 0)
+	require.NoError(t, err)
+	defer fast.Close()
+
+	for range 2 {
+		bus.Publish(notes.Event{Type: notes.EventUpdated, NID: "n1", UID: "alice"})
+		<-fast.Events()
+	}
+	bus.Publish(notes.Event{Type: notes.EventUpdated, NID: "n1", UID: "alice"})
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, 2, received)
+	require.ErrorIs(t, slow.Err(), notes.ErrSubscriberLagging)
+	assert.Len(t, fast.Events(), 1)
+	slow.Close()
+}
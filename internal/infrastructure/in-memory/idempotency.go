@@ -0,0 +1,71 @@
+package inmemory
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/idempotency"
+)
+
+type Idempotency struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+}
+
+func NewIdempotency() *Idempotency {
+	return &Idempotency{records: make(map[string]idempotency.Record)}
+}
+
+func (im *Idempotency) ReserveIdempotencyKey(record idempotency.Record, now time.Time) (idempotency.Record, bool, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if existing, ok := im.records[record.Key]; ok && !existing.Expired(now) {
+		return cloneRecord(existing), false, nil
+	}
+	im.records[record.Key] = record
+	return record, true, nil
+}
+
+func (im *Idempotency) SaveIdempotentResponse(record idempotency.Record) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if _, ok := im.records[record.Key]; ok {
+		im.records[record.Key] = cloneRecord(record)
+	}
+	return nil
+}
+
+func (im *Idempotency) ReleaseIdempotencyKey(key string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if record, ok := im.records[key]; ok && !record.Completed() {
+		delete(im.records, key)
+	}
+	return nil
+}
+
+func (im *Idempotency) PurgeIdempotencyKeys(now time.Time) (int, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	purged := 0
+	for key, record := range im.records {
+		if record.Expired(now) {
+			delete(im.records, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// cloneRecord не даёт вызывающему менять сохранённый ответ через общие срезы и карты.
+func cloneRecord(record idempotency.Record) idempotency.Record {
+	record.Headers = maps.Clone(record.Headers)
+	record.Body = slices.Clone(record.Body)
+	return record
+}
@@ -0,0 +1,45 @@
+package inmemory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency_ReserveIdempotencyKey(t *testing.T) {
+	store := NewIdempotency()
+	now := time.Now().UTC()
+	record := idempotency.Record{Key: "k", Fingerprint: "a", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+
+	_, reserved, err := store.ReserveIdempotencyKey(record, now)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	record.Status = 201
+	record.Body = []byte("created")
+	require.NoError(t, store.SaveIdempotentResponse(record))
+
+	existing, reserved, err := store.ReserveIdempotencyKey(idempotency.Record{Key: "k", Fingerprint: "b"}, now)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, []byte("created"), existing.Body)
+	assert.NoError(t, existing.Replayable("a"))
+	assert.ErrorIs(t, existing.Replayable("b"), idempotency.ErrKeyReused)
+
+	require.NoError(t, store.ReleaseIdempotencyKey("k"), "completed key is kept")
+	_, reserved, err = store.ReserveIdempotencyKey(record, now)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+
+	later := now.Add(2 * time.Minute)
+	_, reserved, err = store.ReserveIdempotencyKey(record, later)
+	require.NoError(t, err)
+	assert.True(t, reserved, "expired key is reserved again")
+
+	purged, err := store.PurgeIdempotencyKeys(later)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+}
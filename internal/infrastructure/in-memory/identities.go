@@ -0,0 +1,47 @@
+package inmemory
+
+import (
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+func identityKey(issuer, subject string) string {
+	return issuer + "\x00" + subject
+}
+
+func (im *Users) SaveIdentity(identity users.Identity) error {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	if _, ok := im.userStorage[identity.UID]; !ok {
+		return users.ErrUserNotFound
+	}
+
+	im.identMu.Lock()
+	defer im.identMu.Unlock()
+
+	im.identities[identityKey(identity.Issuer, identity.Subject)] = identity
+	return nil
+}
+
+func (im *Users) GetUserByIdentity(issuer, subject string) (users.User, error) {
+	im.identMu.RLock()
+	identity, ok := im.identities[identityKey(issuer, subject)]
+	im.identMu.RUnlock()
+	if !ok {
+		return users.User{}, users.ErrIdentityNotFound
+	}
+	return im.GetUserID(identity.UID)
+}
+
+func (im *Users) GetIdentities(userID string) ([]users.Identity, error) {
+	im.identMu.RLock()
+	defer im.identMu.RUnlock()
+
+	identities := make([]users.Identity, 0)
+	for _, identity := range im.identities {
+		if identity.UID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/Dorrrke/notes-g2/pkg/logger"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
@@ -12,6 +13,7 @@ import (
 )
 
 type Notes struct {
+	mu          sync.RWMutex
 	noteStorage map[string]notes.Note
 	filePath    string
 	log         zerolog.Logger
@@ -20,7 +22,21 @@ type Notes struct {
 var emtyUser = users.User{} //nolint:gochecknoglobals // its ok
 
 type Users struct {
+	// usersMu защищает userStorage и emails; берётся раньше остальных мьютексов.
+	usersMu     sync.RWMutex
 	userStorage map[string]users.User
+	// emails — индекс нормализованный адрес -> uid.
+	emails      map[string]string
+	resetMu     sync.Mutex
+	resetTokens map[string]users.ResetToken
+	tokensMu    sync.RWMutex
+	tokens      map[string]users.AccessToken
+	identMu     sync.RWMutex
+	identities  map[string]users.Identity
+	sessionsMu  sync.RWMutex
+	sessions    map[string]users.Session
+	prefsMu     sync.RWMutex
+	preferences map[string]users.Preferences
 	log         zerolog.Logger
 }
 
@@ -40,7 +56,13 @@ func NewNotes(debug bool, filePath string) *Notes {
 func NewUsers() *Users {
 	return &Users{
 		userStorage: make(map[string]users.User),
-		log:         logger.Get(),
+		emails:      make(map[string]string),
+		resetTokens: make(map[string]users.ResetToken),
+		tokens:      make(map[string]users.AccessToken),
+		identities:  make(map[string]users.Identity),
+		sessions:    make(map[string]users.Session),
+		preferences: make(map[string]users.Preferences),
+		log:         logger.Get(false),
 	}
 }
 
@@ -1,16 +1,19 @@
 package inmemory
 
 import (
+	"slices"
+	"sort"
+
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
 )
 
 func (im *Notes) AddNote(note notes.Note) error {
-	for _, id := range im.noteStorage {
-		if id.Title == note.Title {
-			return notes.ErrNoteAlreadyExists
-		}
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := im.addNote(note); err != nil {
+		return err
 	}
-	im.noteStorage[note.NID] = note
 
 	if err := im.SaveToFile(); err != nil {
 		return err
@@ -19,6 +22,9 @@ func (im *Notes) AddNote(note notes.Note) error {
 }
 
 func (im *Notes) GetNotes() ([]notes.Note, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
 	if len(im.noteStorage) == 0 {
 		return nil, notes.ErrNoNotesAvailable
 	}
@@ -31,7 +37,22 @@ func (im *Notes) GetNotes() ([]notes.Note, error) {
 	return notesSlice, nil
 }
 
+func (im *Notes) GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error) {
+	notesSlice, err := im.GetNotes()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(notesSlice, func(i, j int) bool {
+		return sortBy.Less(notesSlice[i], notesSlice[j])
+	})
+	return notesSlice, nil
+}
+
 func (im *Notes) GetNoteID(noteID string) (notes.Note, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
 	note, ok := im.noteStorage[noteID]
 	if !ok {
 		return notes.Note{}, notes.ErrNoteNotFound
@@ -40,10 +61,12 @@ func (im *Notes) GetNoteID(noteID string) (notes.Note, error) {
 }
 
 func (im *Notes) DeleteNote(noteID string) error {
-	if _, ok := im.noteStorage[noteID]; !ok {
-		return notes.ErrNoteNotFound
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := im.deleteNote(noteID); err != nil {
+		return err
 	}
-	delete(im.noteStorage, noteID)
 
 	if err := im.SaveToFile(); err != nil {
 		return err
@@ -52,9 +75,28 @@ func (im *Notes) DeleteNote(noteID string) error {
 }
 
 func (im *Notes) UpdateNote(noteID string, note notes.Note) error {
-	if _, ok := im.noteStorage[noteID]; !ok {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if err := im.updateNote(noteID, note); err != nil {
+		return err
+	}
+
+	if err := im.SaveToFile(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (im *Notes) MoveNote(noteID string, position string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	note, ok := im.noteStorage[noteID]
+	if !ok {
 		return notes.ErrNoteNotFound
 	}
+	note.Position = position
 	im.noteStorage[noteID] = note
 
 	if err := im.SaveToFile(); err != nil {
@@ -62,3 +104,155 @@ func (im *Notes) UpdateNote(noteID string, note notes.Note) error {
 	}
 	return nil
 }
+
+func (im *Notes) MoveNoteToStatus(noteID string, status notes.Status, move notes.MoveRequest) (string, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	note, ok := im.noteStorage[noteID]
+	if !ok {
+		return ``, notes.ErrNoteNotFound
+	}
+
+	lower, err := im.neighbourPosition(move.After, status)
+	if err != nil {
+		return ``, err
+	}
+	upper, err := im.neighbourPosition(move.Before, status)
+	if err != nil {
+		return ``, err
+	}
+	position, err := notes.RankBetween(lower, upper)
+	if err != nil {
+		return ``, err
+	}
+
+	note.Status = status
+	note.Position = position
+	im.noteStorage[noteID] = note
+
+	if err = im.SaveToFile(); err != nil {
+		return ``, err
+	}
+	return position, nil
+}
+
+func (im *Notes) addNote(note notes.Note) error {
+	for _, id := range im.noteStorage {
+		if id.Title == note.Title {
+			return notes.ErrNoteAlreadyExists
+		}
+	}
+	im.noteStorage[note.NID] = note
+	return nil
+}
+
+func (im *Notes) deleteNote(noteID string) error {
+	if _, ok := im.noteStorage[noteID]; !ok {
+		return notes.ErrNoteNotFound
+	}
+	delete(im.noteStorage, noteID)
+	return nil
+}
+
+// updateNote меняет только редактируемые поля, как UPDATE в db-storage.
+func (im *Notes) updateNote(noteID string, note notes.Note) error {
+	stored, ok := im.noteStorage[noteID]
+	if !ok {
+		return notes.ErrNoteNotFound
+	}
+	stored.Title = note.Title
+	stored.Description = note.Description
+	stored.Status = note.Status
+	stored.Priority = note.Priority
+	im.noteStorage[noteID] = stored
+	return nil
+}
+
+func (im *Notes) neighbourPosition(noteID string, status notes.Status) (string, error) {
+	if noteID == `` {
+		return ``, nil
+	}
+	note, ok := im.noteStorage[noteID]
+	if !ok {
+		return ``, notes.ErrNoteNotFound
+	}
+	if note.Status != status {
+		return ``, notes.ErrInvalidPosition
+	}
+	return note.Position, nil
+}
+
+// streamPage — сколько заметок StreamNotes выбирает за один захват блокировки.
+const streamPage = 100
+
+// StreamNotes передаёт заметки владельца в порядке позиций страницами по streamPage:
+// в памяти не больше одной страницы, а fn вызывается без блокировки, так что
+// медленный получатель не задерживает запись.
+func (im *Notes) StreamNotes(uid string, fn func(note notes.Note) error) error {
+	var cursor *notes.Note
+	for {
+		page := im.notesPage(uid, cursor)
+		for _, note := range page {
+			if err := fn(note); err != nil {
+				return err
+			}
+		}
+		if len(page) < streamPage {
+			return nil
+		}
+		cursor = &page[len(page)-1]
+	}
+}
+
+// notesPage возвращает до streamPage заметок владельца, идущих после cursor.
+func (im *Notes) notesPage(uid string, cursor *notes.Note) []notes.Note {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	page := make([]notes.Note, 0, streamPage)
+	for _, note := range im.noteStorage {
+		if note.UID != uid || (cursor != nil && !streamLess(*cursor, note)) {
+			continue
+		}
+		i := sort.Search(len(page), func(i int) bool { return streamLess(note, page[i]) })
+		if i == streamPage {
+			continue
+		}
+		if len(page) == streamPage {
+			page = page[:streamPage-1]
+		}
+		page = slices.Insert(page, i, note)
+	}
+	return page
+}
+
+// streamLess — порядок SortPosition, доведённый идентификатором до строгого, чтобы курсор не терял заметки.
+func streamLess(a, b notes.Note) bool {
+	if a.Position != b.Position || !a.CreatedAt.Equal(b.CreatedAt) {
+		return notes.SortPosition.Less(a, b)
+	}
+	return a.NID < b.NID
+}
+
+// DeleteUserNotes удаляет все заметки пользователя при удалении аккаунта.
+func (im *Notes) DeleteUserNotes(uid string) (int, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	deleted := 0
+	for nid, note := range im.noteStorage {
+		if note.UID == uid {
+			delete(im.noteStorage, nid)
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	if err := im.SaveToFile(); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -2,6 +2,7 @@ package inmemory
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -212,6 +213,17 @@ func TestUpdateNote(t *testing.T) {
 		assert.Equal(t, toComparer(updatedNote), toComparer(fileData["1"]))
 	})
 
+	t.Run("only editable fields change", func(t *testing.T) {
+		err = im.UpdateNote("1", notes.Note{Title: "Partial"})
+		require.NoError(t, err)
+
+		stored := im.noteStorage["1"]
+		assert.Equal(t, "Partial", stored.Title)
+		assert.Equal(t, "1", stored.NID)
+		assert.Equal(t, "user1", stored.UID)
+		assert.True(t, stored.CreatedAt.Equal(initialNote.CreatedAt))
+	})
+
 	t.Run("note not found", func(t *testing.T) {
 		err = im.UpdateNote("999", updatedNote)
 		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
@@ -270,3 +282,140 @@ func TestStatusOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNotesSorted(t *testing.T) {
+	tmpFile := t.TempDir() + "/notes_test.json"
+	im := NewNotes(false, tmpFile)
+
+	testNotes := []notes.Note{
+		{NID: "1", Title: "Note 1", Priority: notes.Low, Position: "a"},
+		{NID: "2", Title: "Note 2", Priority: notes.Urgent, Position: "c"},
+		{NID: "3", Title: "Note 3", Priority: notes.Urgent, Position: "b"},
+	}
+	for _, note := range testNotes {
+		require.NoError(t, im.AddNote(note))
+	}
+
+	t.Run("by position", func(t *testing.T) {
+		result, err := im.GetNotesSorted(notes.SortPosition)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "3", "2"}, nids(result))
+	})
+
+	t.Run("by priority", func(t *testing.T) {
+		result, err := im.GetNotesSorted(notes.SortPriority)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3", "2", "1"}, nids(result))
+	})
+}
+
+func TestMoveNote(t *testing.T) {
+	tmpFile := t.TempDir() + "/notes_test.json"
+	im := NewNotes(false, tmpFile)
+
+	require.NoError(t, im.AddNote(notes.Note{NID: "1", Title: "Note 1", Position: "a"}))
+
+	t.Run("successful move", func(t *testing.T) {
+		err := im.MoveNote("1", "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "b", im.noteStorage["1"].Position)
+	})
+
+	t.Run("update keeps position", func(t *testing.T) {
+		err := im.UpdateNote("1", notes.Note{NID: "1", Title: "Renamed"})
+		assert.NoError(t, err)
+		assert.Equal(t, "b", im.noteStorage["1"].Position)
+	})
+
+	t.Run("note not found", func(t *testing.T) {
+		err := im.MoveNote("999", "b")
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+}
+
+func nids(notesSlice []notes.Note) []string {
+	ids := make([]string, 0, len(notesSlice))
+	for _, note := range notesSlice {
+		ids = append(ids, note.NID)
+	}
+	return ids
+}
+
+func TestMoveNoteToStatus(t *testing.T) {
+	tmpFile := t.TempDir() + "/notes_test.json"
+	im := NewNotes(false, tmpFile)
+
+	for _, note := range []notes.Note{
+		{NID: "1", Title: "Note 1", Status: notes.New, Position: "a"},
+		{NID: "2", Title: "Note 2", Status: notes.Active, Position: "b"},
+		{NID: "3", Title: "Note 3", Status: notes.Active, Position: "d"},
+	} {
+		require.NoError(t, im.AddNote(note))
+	}
+
+	t.Run("successful move", func(t *testing.T) {
+		position, err := im.MoveNoteToStatus("1", notes.Active, notes.MoveRequest{After: "2", Before: "3"})
+		require.NoError(t, err)
+		assert.Equal(t, "c", position)
+		assert.Equal(t, notes.Status(notes.Active), im.noteStorage["1"].Status)
+		assert.Equal(t, "c", im.noteStorage["1"].Position)
+	})
+
+	t.Run("neighbour in another column", func(t *testing.T) {
+		_, err := im.MoveNoteToStatus("2", notes.Inactive, notes.MoveRequest{After: "3"})
+		assert.ErrorIs(t, err, notes.ErrInvalidPosition)
+		assert.Equal(t, notes.Status(notes.Active), im.noteStorage["2"].Status)
+	})
+
+	t.Run("note not found", func(t *testing.T) {
+		_, err := im.MoveNoteToStatus("999", notes.Active, notes.MoveRequest{})
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+}
+
+func TestStreamNotes(t *testing.T) {
+	im := NewNotes(false, t.TempDir()+"/notes_test.json")
+
+	for _, note := range []notes.Note{
+		{NID: "1", Title: "Note 1", Position: "b", UID: "user1"},
+		{NID: "2", Title: "Note 2", Position: "a", UID: "user1"},
+		{NID: "3", Title: "Note 3", Position: "c", UID: "user2"},
+	} {
+		require.NoError(t, im.AddNote(note))
+	}
+
+	var streamed []notes.Note
+	err := im.StreamNotes("user1", func(note notes.Note) error {
+		streamed = append(streamed, note)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "1"}, nids(streamed))
+}
+
+func TestStreamNotesPages(t *testing.T) {
+	im := NewNotes(false, t.TempDir()+"/notes_test.json")
+
+	want := make([]string, 0, 2*streamPage+1)
+	for i := range 2*streamPage + 1 {
+		nid := fmt.Sprintf("%03d", i)
+		// Одинаковые позиции у соседних заметок проверяют, что курсор не теряет равные.
+		note := notes.Note{NID: nid, Title: nid, Position: fmt.Sprintf("p%03d", i/2), UID: "user1"}
+		require.NoError(t, im.AddNote(note))
+		want = append(want, nid)
+	}
+
+	var streamed []notes.Note
+	err := im.StreamNotes("user1", func(note notes.Note) error {
+		if len(streamed) == 0 {
+			// fn вызывается без блокировки: запись из него не должна зависнуть.
+			require.NoError(t, im.AddNote(notes.Note{NID: "other", Title: "Other", UID: "user2"}))
+		}
+		streamed = append(streamed, note)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, want, nids(streamed))
+}
@@ -0,0 +1,29 @@
+package inmemory
+
+import "github.com/Snoop-Duck/ToDoList/internal/domain/users"
+
+func (im *Users) GetPreferences(userID string) (users.Preferences, error) {
+	im.prefsMu.RLock()
+	defer im.prefsMu.RUnlock()
+
+	prefs, ok := im.preferences[userID]
+	if !ok {
+		return users.Preferences{}, users.ErrPreferencesNotFound
+	}
+	return prefs, nil
+}
+
+func (im *Users) SavePreferences(userID string, prefs users.Preferences) error {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	if _, ok := im.userStorage[userID]; !ok {
+		return users.ErrUserNotFound
+	}
+
+	im.prefsMu.Lock()
+	defer im.prefsMu.Unlock()
+
+	im.preferences[userID] = prefs
+	return nil
+}
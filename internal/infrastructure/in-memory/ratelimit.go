@@ -0,0 +1,41 @@
+package inmemory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/ratelimit"
+)
+
+// RateLimits хранит корзины в памяти процесса: у каждой реплики свой бюджет.
+type RateLimits struct {
+	mu      sync.Mutex
+	buckets map[string]ratelimit.Bucket
+}
+
+func NewRateLimits() *RateLimits {
+	return &RateLimits{buckets: make(map[string]ratelimit.Bucket)}
+}
+
+func (im *RateLimits) TakeToken(key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	bucket, decision := limit.Take(im.buckets[key], now)
+	im.buckets[key] = bucket
+	return decision, nil
+}
+
+func (im *RateLimits) PurgeRateLimits(idleSince time.Time) (int, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	purged := 0
+	for key, bucket := range im.buckets {
+		if bucket.UpdatedAt.Before(idleSince) {
+			delete(im.buckets, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
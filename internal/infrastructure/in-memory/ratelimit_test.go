@@ -0,0 +1,33 @@
+package inmemory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimits_TakeToken(t *testing.T) {
+	store := NewRateLimits()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Minute}
+	now := time.Now().UTC()
+
+	decision, err := store.TakeToken("a", limit, now)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	decision, err = store.TakeToken("a", limit, now)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	decision, err = store.TakeToken("b", limit, now)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed, "keys have separate buckets")
+
+	purged, err := store.PurgeRateLimits(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	decision, err = store.TakeToken("a", limit, now)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+}
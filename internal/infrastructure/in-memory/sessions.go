@@ -0,0 +1,75 @@
+package inmemory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+func (im *Users) SaveSession(session users.Session) error {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	if _, ok := im.userStorage[session.UID]; !ok {
+		return users.ErrUserNotFound
+	}
+
+	im.sessionsMu.Lock()
+	defer im.sessionsMu.Unlock()
+
+	im.sessions[session.ID] = session
+	return nil
+}
+
+func (im *Users) GetSessions(userID string) ([]users.Session, error) {
+	im.sessionsMu.RLock()
+	defer im.sessionsMu.RUnlock()
+
+	sessions := make([]users.Session, 0)
+	for _, session := range im.sessions {
+		if session.UID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (im *Users) GetSession(sessionID string) (users.Session, error) {
+	im.sessionsMu.RLock()
+	defer im.sessionsMu.RUnlock()
+
+	session, ok := im.sessions[sessionID]
+	if !ok {
+		return users.Session{}, users.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (im *Users) TouchSession(sessionID string, lastSeen time.Time) error {
+	im.sessionsMu.Lock()
+	defer im.sessionsMu.Unlock()
+
+	session, ok := im.sessions[sessionID]
+	if !ok {
+		return users.ErrSessionNotFound
+	}
+	session.LastSeenAt = lastSeen
+	im.sessions[sessionID] = session
+	return nil
+}
+
+func (im *Users) DeleteSession(userID, sessionID string) error {
+	im.sessionsMu.Lock()
+	defer im.sessionsMu.Unlock()
+
+	session, ok := im.sessions[sessionID]
+	if !ok || session.UID != userID {
+		return users.ErrSessionNotFound
+	}
+	delete(im.sessions, sessionID)
+	return nil
+}
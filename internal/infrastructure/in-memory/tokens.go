@@ -0,0 +1,62 @@
+package inmemory
+
+import (
+	"sort"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+func (im *Users) SaveAccessToken(token users.AccessToken) error {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	if _, ok := im.userStorage[token.UID]; !ok {
+		return users.ErrUserNotFound
+	}
+
+	im.tokensMu.Lock()
+	defer im.tokensMu.Unlock()
+
+	im.tokens[token.ID] = token
+	return nil
+}
+
+func (im *Users) GetAccessTokens(userID string) ([]users.AccessToken, error) {
+	im.tokensMu.RLock()
+	defer im.tokensMu.RUnlock()
+
+	tokens := make([]users.AccessToken, 0)
+	for _, token := range im.tokens {
+		if token.UID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (im *Users) GetAccessTokenByHash(hash string) (users.AccessToken, error) {
+	im.tokensMu.RLock()
+	defer im.tokensMu.RUnlock()
+
+	for _, token := range im.tokens {
+		if token.Hash == hash {
+			return token, nil
+		}
+	}
+	return users.AccessToken{}, users.ErrTokenNotFound
+}
+
+func (im *Users) DeleteAccessToken(userID, tokenID string) error {
+	im.tokensMu.Lock()
+	defer im.tokensMu.Unlock()
+
+	token, ok := im.tokens[tokenID]
+	if !ok || token.UID != userID {
+		return users.ErrTokenNotFound
+	}
+	delete(im.tokens, tokenID)
+	return nil
+}
@@ -1,38 +1,115 @@
 package inmemory
 
 import (
+	"maps"
+	"slices"
+	"time"
+
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
 )
 
 func (im *Users) SaveUser(user users.User) error {
-	for _, us := range im.userStorage {
-		if us.Email == user.Email {
-			return users.ErrUserAlredyExists
-		}
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	email := users.NormalizeEmail(user.Email)
+	if _, ok := im.userByEmail(email); ok {
+		return users.ErrUserAlredyExists
 	}
 
 	im.userStorage[user.UID] = user
+	im.emails[email] = user.UID
 	return nil
 }
 
 func (im *Users) GetUser(login string) (users.User, error) {
-	for _, us := range im.userStorage {
-		if us.Email == login {
-			return us, nil
-		}
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	user, ok := im.userByEmail(users.NormalizeEmail(login))
+	if !ok {
+		return emtyUser, users.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// userByEmail ищет пользователя по индексу; запись индекса без пользователя считается пустой.
+// Вызывается под usersMu.
+func (im *Users) userByEmail(email string) (users.User, bool) {
+	uid, ok := im.emails[email]
+	if !ok {
+		return users.User{}, false
 	}
-	return emtyUser, users.ErrUserNotFound
+	user, ok := im.userStorage[uid]
+	return user, ok
 }
 
 func (im *Users) DeleteUser(userID string) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
 	if _, ok := im.userStorage[userID]; !ok {
 		return users.ErrUserNotFound
 	}
+	delete(im.emails, users.NormalizeEmail(im.userStorage[userID].Email))
 	delete(im.userStorage, userID)
+	im.deleteUserData(userID)
+	return nil
+}
+
+// deleteUserData убирает связанные с пользователем записи, как ON DELETE CASCADE в БД.
+func (im *Users) deleteUserData(userID string) {
+	im.resetMu.Lock()
+	maps.DeleteFunc(im.resetTokens, func(_ string, token users.ResetToken) bool { return token.UID == userID })
+	im.resetMu.Unlock()
+
+	im.tokensMu.Lock()
+	maps.DeleteFunc(im.tokens, func(_ string, token users.AccessToken) bool { return token.UID == userID })
+	im.tokensMu.Unlock()
+
+	im.identMu.Lock()
+	maps.DeleteFunc(im.identities, func(_ string, identity users.Identity) bool { return identity.UID == userID })
+	im.identMu.Unlock()
+
+	im.sessionsMu.Lock()
+	maps.DeleteFunc(im.sessions, func(_ string, session users.Session) bool { return session.UID == userID })
+	im.sessionsMu.Unlock()
+
+	im.prefsMu.Lock()
+	delete(im.preferences, userID)
+	im.prefsMu.Unlock()
+}
+
+func (im *Users) ScheduleDeletion(userID string, deleteAt *time.Time) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	user, ok := im.userStorage[userID]
+	if !ok {
+		return users.ErrUserNotFound
+	}
+	user.DeleteAt = deleteAt
+	im.userStorage[userID] = user
 	return nil
 }
 
+func (im *Users) GetUsersDueForDeletion(now time.Time) ([]users.User, error) {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	due := make([]users.User, 0)
+	for _, user := range im.userStorage {
+		if user.DeleteAt != nil && !user.DeleteAt.After(now) {
+			due = append(due, user)
+		}
+	}
+	return due, nil
+}
+
 func (im *Users) GetAllUsers() ([]users.User, error) {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
 	if len(im.userStorage) == 0 {
 		return nil, users.ErrNoUsersAvailable
 	}
@@ -46,6 +123,9 @@ func (im *Users) GetAllUsers() ([]users.User, error) {
 }
 
 func (im *Users) GetUserID(userID string) (users.User, error) {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
 	user, ok := im.userStorage[userID]
 	if !ok {
 		return users.User{}, users.ErrUserNotFound
@@ -54,9 +134,146 @@ func (im *Users) GetUserID(userID string) (users.User, error) {
 }
 
 func (im *Users) UpdateUserID(userID string, user users.User) error {
-	if _, ok := im.userStorage[userID]; !ok {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	stored, ok := im.userStorage[userID]
+	if !ok {
 		return users.ErrUserNotFound
 	}
+	oldEmail, newEmail := users.NormalizeEmail(stored.Email), users.NormalizeEmail(user.Email)
+	if owner, taken := im.userByEmail(newEmail); taken && owner.UID != userID {
+		return users.ErrUserAlredyExists
+	}
+	delete(im.emails, oldEmail)
+	im.emails[newEmail] = userID
+
+	user.Password = stored.Password
+	user.Verified = stored.Verified && oldEmail == newEmail
+	user.CreatedAt = stored.CreatedAt
+	user.PasswordChangedAt = stored.PasswordChangedAt
+	user.TOTP = stored.TOTP
+	user.DeleteAt = stored.DeleteAt
+	im.userStorage[userID] = user
+	return nil
+}
+
+func (im *Users) VerifyUser(userID string) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	user, ok := im.userStorage[userID]
+	if !ok {
+		return users.ErrUserNotFound
+	}
+	user.Verified = true
+	im.userStorage[userID] = user
+	return nil
+}
+
+func (im *Users) SaveResetToken(token users.ResetToken) error {
+	im.usersMu.RLock()
+	defer im.usersMu.RUnlock()
+
+	if _, ok := im.userStorage[token.UID]; !ok {
+		return users.ErrUserNotFound
+	}
+
+	im.resetMu.Lock()
+	defer im.resetMu.Unlock()
+
+	im.resetTokens[token.Hash] = token
+	return nil
+}
+
+func (im *Users) ConsumeResetToken(hash string) (users.ResetToken, error) {
+	im.resetMu.Lock()
+	defer im.resetMu.Unlock()
+
+	token, ok := im.resetTokens[hash]
+	if !ok {
+		return users.ResetToken{}, users.ErrInvalidToken
+	}
+	delete(im.resetTokens, hash)
+	return token, nil
+}
+
+func (im *Users) ResetPassword(userID, password string, changedAt time.Time) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	user, ok := im.userStorage[userID]
+	if !ok {
+		return users.ErrUserNotFound
+	}
+	user.Password = password
+	user.PasswordChangedAt = changedAt
+	im.userStorage[userID] = user
+
+	im.resetMu.Lock()
+	defer im.resetMu.Unlock()
+
+	for hash, token := range im.resetTokens {
+		if token.UID == userID {
+			delete(im.resetTokens, hash)
+		}
+	}
+
+	im.sessionsMu.Lock()
+	defer im.sessionsMu.Unlock()
+
+	for id, session := range im.sessions {
+		if session.UID == userID {
+			delete(im.sessions, id)
+		}
+	}
+	return nil
+}
+
+func (im *Users) UpdateTOTP(userID string, totp users.TOTP) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	user, ok := im.userStorage[userID]
+	if !ok {
+		return users.ErrUserNotFound
+	}
+	// LastStep меняет только UseTOTPStep, как и в Postgres.
+	totp.LastStep = user.TOTP.LastStep
+	user.TOTP = totp
+	im.userStorage[userID] = user
+	return nil
+}
+
+func (im *Users) UseTOTPStep(userID string, step int64) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	user, ok := im.userStorage[userID]
+	if !ok {
+		return users.ErrUserNotFound
+	}
+	if step <= user.TOTP.LastStep {
+		return users.ErrInvalidCode
+	}
+	user.TOTP.LastStep = step
+	im.userStorage[userID] = user
+	return nil
+}
+
+func (im *Users) ConsumeRecoveryCode(userID, codeHash string) error {
+	im.usersMu.Lock()
+	defer im.usersMu.Unlock()
+
+	user, ok := im.userStorage[userID]
+	if !ok {
+		return users.ErrUserNotFound
+	}
+	idx := slices.Index(user.TOTP.RecoveryCodes, codeHash)
+	if idx == -1 {
+		return users.ErrInvalidCode
+	}
+	user.TOTP.RecoveryCodes = slices.Delete(slices.Clone(user.TOTP.RecoveryCodes), idx, idx+1)
 	im.userStorage[userID] = user
 	return nil
 }
@@ -1,7 +1,10 @@
 package inmemory
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
 	"github.com/google/uuid"
@@ -120,3 +123,86 @@ func TestInMemoryUsers(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestInMemoryPasswordReset(t *testing.T) {
+	im := NewUsers()
+	user := users.User{UID: "uid-1", Email: "user@example.com", Password: "old"}
+	assert.NoError(t, im.SaveUser(user))
+
+	expiresAt := time.Now().Add(time.Hour)
+	assert.NoError(t, im.SaveResetToken(users.ResetToken{Hash: "h1", UID: user.UID, ExpiresAt: expiresAt}))
+	assert.NoError(t, im.SaveResetToken(users.ResetToken{Hash: "h2", UID: user.UID, ExpiresAt: expiresAt}))
+	assert.ErrorIs(t, im.SaveResetToken(users.ResetToken{Hash: "h3", UID: "unknown"}), users.ErrUserNotFound)
+
+	token, err := im.ConsumeResetToken("h1")
+	assert.NoError(t, err)
+	assert.Equal(t, user.UID, token.UID)
+
+	_, err = im.ConsumeResetToken("h1")
+	assert.ErrorIs(t, err, users.ErrInvalidToken)
+
+	assert.NoError(t, im.SaveSession(users.Session{ID: "s1", UID: user.UID, ExpiresAt: expiresAt}))
+
+	changedAt := time.Now()
+	assert.NoError(t, im.ResetPassword(user.UID, "new", changedAt))
+
+	_, err = im.GetSession("s1")
+	assert.ErrorIs(t, err, users.ErrSessionNotFound, "reset must end the user's sessions")
+
+	stored, err := im.GetUserID(user.UID)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", stored.Password)
+	assert.Equal(t, changedAt, stored.PasswordChangedAt)
+
+	_, err = im.ConsumeResetToken("h2")
+	assert.ErrorIs(t, err, users.ErrInvalidToken, "reset must drop the user's other tokens")
+}
+
+func TestInMemoryEmailCaseInsensitive(t *testing.T) {
+	im := NewUsers()
+	assert.NoError(t, im.SaveUser(users.User{UID: "uid-1", Email: "Bob@Example.com"}))
+	assert.NoError(t, im.SaveUser(users.User{UID: "uid-2", Email: "alice@example.com"}))
+
+	assert.ErrorIs(t, im.SaveUser(users.User{UID: "uid-3", Email: "bob@example.com"}), users.ErrUserAlredyExists)
+
+	found, err := im.GetUser("BOB@example.COM")
+	assert.NoError(t, err)
+	assert.Equal(t, "uid-1", found.UID)
+
+	assert.ErrorIs(t, im.UpdateUserID("uid-2", users.User{Email: "bob@EXAMPLE.com"}), users.ErrUserAlredyExists)
+	assert.NoError(t, im.UpdateUserID("uid-1", users.User{Email: "robert@example.com"}))
+
+	_, err = im.GetUser("bob@example.com")
+	assert.ErrorIs(t, err, users.ErrUserNotFound)
+	assert.NoError(t, im.SaveUser(users.User{UID: "uid-3", Email: "bob@example.com"}))
+
+	assert.NoError(t, im.DeleteUser("uid-3"))
+	_, err = im.GetUser("bob@example.com")
+	assert.ErrorIs(t, err, users.ErrUserNotFound)
+}
+
+func TestInMemoryTOTPConcurrent(t *testing.T) {
+	im := NewUsers()
+	assert.NoError(t, im.SaveUser(users.User{UID: "uid-1", Email: "user@example.com"}))
+	assert.NoError(t, im.UpdateTOTP("uid-1", users.TOTP{Enabled: true, RecoveryCodes: []string{"r1"}}))
+
+	const workers = 20
+	var steps, codes atomic.Int32
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if im.UseTOTPStep("uid-1", 42) == nil {
+				steps.Add(1)
+			}
+			if im.ConsumeRecoveryCode("uid-1", "r1") == nil {
+				codes.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), steps.Load(), "step must be accepted once")
+	assert.Equal(t, int32(1), codes.Load(), "recovery code must be used once")
+}
@@ -0,0 +1,145 @@
+package inmemory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+)
+
+// Webhooks хранит подписки и очередь доставок в памяти — для запуска без базы данных
+// и тестов. После перезапуска очередь теряется.
+type Webhooks struct {
+	mu         sync.RWMutex
+	hooks      map[string]webhooks.Webhook
+	deliveries []webhooks.Delivery
+}
+
+func NewWebhooks() *Webhooks {
+	return &Webhooks{hooks: make(map[string]webhooks.Webhook)}
+}
+
+func (im *Webhooks) SaveWebhook(hook webhooks.Webhook) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.hooks[hook.ID] = hook
+	return nil
+}
+
+func (im *Webhooks) GetWebhooks(userID string) ([]webhooks.Webhook, error) {
+	return im.webhooks(func(hook webhooks.Webhook) bool { return hook.UID == userID }), nil
+}
+
+func (im *Webhooks) webhooks(match func(hook webhooks.Webhook) bool) []webhooks.Webhook {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	hooks := make([]webhooks.Webhook, 0)
+	for _, hook := range im.hooks {
+		if match(hook) {
+			hooks = append(hooks, hook)
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+	})
+	return hooks
+}
+
+func (im *Webhooks) GetWebhook(webhookID string) (webhooks.Webhook, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	hook, ok := im.hooks[webhookID]
+	if !ok {
+		return webhooks.Webhook{}, webhooks.ErrWebhookNotFound
+	}
+	return hook, nil
+}
+
+func (im *Webhooks) DeleteWebhook(userID, webhookID string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	hook, ok := im.hooks[webhookID]
+	if !ok || hook.UID != userID {
+		return webhooks.ErrWebhookNotFound
+	}
+	delete(im.hooks, webhookID)
+	kept := im.deliveries[:0]
+	for _, delivery := range im.deliveries {
+		if delivery.WebhookID != webhookID {
+			kept = append(kept, delivery)
+		}
+	}
+	im.deliveries = kept
+	return nil
+}
+
+func (im *Webhooks) EnqueueDeliveries(deliveries []webhooks.Delivery) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.deliveries = append(im.deliveries, deliveries...)
+	return nil
+}
+
+func (im *Webhooks) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]webhooks.Delivery, error) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	claimed := make([]webhooks.Delivery, 0)
+	for i := range im.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		delivery := &im.deliveries[i]
+		if delivery.Status != webhooks.StatusPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		claimed = append(claimed, *delivery)
+		delivery.NextAttemptAt = now.Add(lease)
+	}
+	return claimed, nil
+}
+
+func (im *Webhooks) UpdateDelivery(delivery webhooks.Delivery) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for i := range im.deliveries {
+		if im.deliveries[i].ID == delivery.ID {
+			im.deliveries[i] = delivery
+			return nil
+		}
+	}
+	return webhooks.ErrDeliveryNotFound
+}
+
+func (im *Webhooks) GetDeliveries(filter webhooks.DeliveryFilter) ([]webhooks.Delivery, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	limit := filter.PageSize()
+	deliveries := make([]webhooks.Delivery, 0)
+	for i := len(im.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if filter.Match(im.deliveries[i]) {
+			deliveries = append(deliveries, im.deliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (im *Webhooks) GetDelivery(deliveryID string) (webhooks.Delivery, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	for _, delivery := range im.deliveries {
+		if delivery.ID == deliveryID {
+			return delivery, nil
+		}
+	}
+	return webhooks.Delivery{}, webhooks.ErrDeliveryNotFound
+}
@@ -0,0 +1,48 @@
+package inmemory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks_ClaimDeliveries(t *testing.T) {
+	store := NewWebhooks()
+	now := time.Now().UTC()
+	require.NoError(t, store.EnqueueDeliveries([]webhooks.Delivery{
+		{ID: "due", Status: webhooks.StatusPending, NextAttemptAt: now},
+		{ID: "later", Status: webhooks.StatusPending, NextAttemptAt: now.Add(time.Hour)},
+		{ID: "dead", Status: webhooks.StatusDead, NextAttemptAt: now},
+	}))
+
+	claimed, err := store.ClaimDeliveries(now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, "due", claimed[0].ID)
+
+	claimed, err = store.ClaimDeliveries(now, time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "claimed delivery is leased")
+
+	claimed, err = store.ClaimDeliveries(now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "expired lease makes delivery due again")
+	assert.Equal(t, "due", claimed[0].ID)
+}
+
+func TestWebhooks_DeleteWebhook(t *testing.T) {
+	store := NewWebhooks()
+	require.NoError(t, store.SaveWebhook(webhooks.Webhook{ID: "h1", UID: "alice"}))
+	require.NoError(t, store.EnqueueDeliveries([]webhooks.Delivery{
+		{ID: "d1", WebhookID: "h1", UID: "alice"},
+	}))
+
+	require.ErrorIs(t, store.DeleteWebhook("bob", "h1"), webhooks.ErrWebhookNotFound)
+	require.NoError(t, store.DeleteWebhook("alice", "h1"))
+
+	_, err := store.GetDelivery("d1")
+	assert.ErrorIs(t, err, webhooks.ErrDeliveryNotFound)
+}
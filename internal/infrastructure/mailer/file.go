@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+	"github.com/google/uuid"
+)
+
+// File складывает письма в каталог в виде .eml файлов вместо отправки.
+type File struct {
+	dir  string
+	from string
+}
+
+func NewFile(dir, from string) *File {
+	return &File{dir: dir, from: from}
+}
+
+func (f *File) Send(msg mail.Message) error {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405"), uuid.New().String())
+	return os.WriteFile(filepath.Join(f.dir, name), format(f.from, msg, now), 0600)
+}
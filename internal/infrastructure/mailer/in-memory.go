@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"sync"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+)
+
+type Memory struct {
+	mu       sync.Mutex
+	messages []mail.Message
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Send(msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *Memory) Messages() []mail.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]mail.Message(nil), m.messages...)
+}
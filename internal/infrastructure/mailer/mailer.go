@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+)
+
+// format собирает письмо в формате RFC 5322.
+func format(from string, msg mail.Message, now time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSend(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	f := NewFile(dir, "no-reply@example.com")
+
+	require.NoError(t, f.Send(mail.Message{To: "bob@example.com", Subject: "Hello", Body: "line 1\nline 2"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "From: no-reply@example.com\r\n")
+	assert.Contains(t, content, "To: bob@example.com\r\n")
+	assert.Contains(t, content, "Subject: Hello\r\n")
+	assert.True(t, strings.HasSuffix(content, "\r\n\r\nline 1\r\nline 2"), content)
+}
+
+func TestMemorySend(t *testing.T) {
+	m := NewMemory()
+	msg := mail.Message{To: "bob@example.com", Subject: "Hello", Body: "body"}
+
+	require.NoError(t, m.Send(msg))
+
+	assert.Equal(t, []mail.Message{msg}, m.Messages())
+}
@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+)
+
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTP(addr, from, user, password string) *SMTP {
+	sender := &SMTP{addr: addr, from: from}
+	if user != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		sender.auth = smtp.PlainAuth("", user, password, host)
+	}
+	return sender
+}
+
+func (s *SMTP) Send(msg mail.Message) error {
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, format(s.from, msg, time.Now()))
+}
@@ -0,0 +1,84 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// publicKeys разбирает ключи подписи; ключи неизвестных типов пропускаются.
+func (s jwks) publicKeys() map[string]any {
+	keys := make(map[string]any, len(s.Keys))
+	for _, k := range s.Keys {
+		if k.Use != `` && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			if key, ok := k.rsaKey(); ok {
+				keys[k.Kid] = key
+			}
+		case "EC":
+			if key, ok := k.ecKey(); ok {
+				keys[k.Kid] = key
+			}
+		}
+	}
+	return keys
+}
+
+func (k jwk) rsaKey() (*rsa.PublicKey, bool) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, false
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, false
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() {
+		return nil, false
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, true
+}
+
+func (k jwk) ecKey() (*ecdsa.PublicKey, bool) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, false
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, false
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, false
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, true
+}
@@ -0,0 +1,238 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	httpTimeout    = 10 * time.Second
+	maxBodySize    = 1 << 20
+	discoveryPath  = "/.well-known/openid-configuration"
+	defaultScopes  = "openid email profile"
+	challengeS256  = "S256"
+	grantTypeCode  = "authorization_code"
+	contentTypeKey = "Content-Type"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrUnknownKey     = errors.New("unknown signing key")
+)
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider реализует authorization code flow с PKCE. Метаданные провайдера
+// загружаются при первом обращении, ключи JWKS перечитываются при встрече незнакомого kid.
+type Provider struct {
+	cfg    internal.OIDCConfig
+	client *http.Client
+
+	mu   sync.Mutex
+	meta *discovery
+	keys map[string]any
+}
+
+func New(cfg internal.OIDCConfig) *Provider {
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: httpTimeout},
+		keys:   make(map[string]any),
+	}
+}
+
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, challenge string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return ``, err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", defaultScopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", challengeS256)
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange меняет код авторизации на ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return ``, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", grantTypeCode)
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return ``, err
+	}
+	req.Header.Set(contentTypeKey, "application/x-www-form-urlencoded")
+	if p.cfg.ClientSecret != `` {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err = p.doJSON(req, &tokens); err != nil {
+		return ``, fmt.Errorf("token exchange: %w", err)
+	}
+	if tokens.IDToken == `` {
+		return ``, ErrInvalidIDToken
+	}
+	return tokens.IDToken, nil
+}
+
+type idClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Verify проверяет подпись ID token по JWKS, издателя, аудиторию и nonce;
+// срок действия проверяет сам парсер.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (users.Identity, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return users.Identity{}, err
+	}
+
+	claims := idClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	_, err = parser.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, meta.JWKSURI, kid)
+	})
+	if err != nil {
+		return users.Identity{}, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	switch {
+	case claims.Issuer != meta.Issuer:
+		return users.Identity{}, fmt.Errorf("%w: unexpected issuer", ErrInvalidIDToken)
+	case !claims.VerifyAudience(p.cfg.ClientID, true):
+		return users.Identity{}, fmt.Errorf("%w: unexpected audience", ErrInvalidIDToken)
+	case claims.ExpiresAt == nil:
+		return users.Identity{}, fmt.Errorf("%w: missing exp", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return users.Identity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	case claims.Subject == ``:
+		return users.Identity{}, fmt.Errorf("%w: empty subject", ErrInvalidIDToken)
+	}
+
+	return users.Identity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.cfg.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta discovery
+	if err = p.doJSON(req, &meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if meta.Issuer != strings.TrimRight(p.cfg.Issuer, "/") && meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", meta.Issuer, p.cfg.Issuer)
+	}
+	p.meta = &meta
+	return p.meta, nil
+}
+
+func (p *Provider) key(ctx context.Context, jwksURI, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	// Провайдер мог провернуть ключи — перечитываем набор.
+	keys, err := p.fetchKeys(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownKey
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set jwks
+	if err = p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	return set.publicKeys(), nil
+}
+
+func (p *Provider) doJSON(req *http.Request, dst any) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, dst)
+}
@@ -0,0 +1,91 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+	idp := oidctest.NewServer(t)
+	return New(internal.OIDCConfig{
+		Issuer:      idp.URL,
+		ClientID:    oidctest.ClientID,
+		RedirectURL: "http://notes.test/users/oidc/callback",
+	}), idp
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider, idp := newTestProvider(t)
+
+	raw, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "challenge-1")
+	require.NoError(t, err)
+
+	authURL, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	query := authURL.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, oidctest.ClientID, query.Get("client_id"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "challenge-1", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestVerify(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+
+	identity, err := provider.Verify(ctx, idp.IDToken("nonce-1", nil), "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, idp.URL, identity.Issuer)
+	assert.Equal(t, idp.Subject, identity.Subject)
+	assert.Equal(t, idp.Email, identity.Email)
+	assert.True(t, identity.EmailVerified)
+
+	tests := []struct {
+		name     string
+		override func(claims jwt.MapClaims)
+		nonce    string
+	}{
+		{name: "wrong nonce", nonce: "other"},
+		{name: "wrong audience", nonce: "nonce-1", override: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		{name: "wrong issuer", nonce: "nonce-1", override: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", nonce: "nonce-1", override: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := provider.Verify(ctx, idp.IDToken("nonce-1", tc.override), tc.nonce)
+			assert.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+
+	t.Run("forged signature", func(t *testing.T) {
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"iss": idp.URL, "sub": "x", "aud": oidctest.ClientID, "nonce": "nonce-1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		forged.Header["kid"] = oidctest.KeyID
+		raw, err := forged.SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = provider.Verify(ctx, raw, "nonce-1")
+		assert.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+}
+
+func TestExchangeRequiresPKCE(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	_, err := provider.Exchange(context.Background(), "unknown-code", "verifier")
+	assert.Error(t, err)
+}
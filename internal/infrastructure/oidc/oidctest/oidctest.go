@@ -0,0 +1,185 @@
+// Package oidctest поднимает локальный провайдер OpenID Connect для тестов.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	KeyID    = "test-key"
+	ClientID = "notes-test"
+	keyBits  = 2048
+)
+
+type authRequest struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+// Server — заглушка IdP: discovery, JWKS, authorize (сразу редиректит обратно с кодом) и token.
+type Server struct {
+	*httptest.Server
+
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]authRequest
+}
+
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		Subject:       "subject-1",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+		Name:          "Alice",
+		key:           key,
+		codes:         make(map[string]authRequest),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// IDToken подписывает ID token с заданными claims поверх стандартных.
+func (s *Server) IDToken(nonce string, override func(claims jwt.MapClaims)) string {
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            s.Subject,
+		"aud":            ClientID,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          s.Email,
+		"email_verified": s.EmailVerified,
+		"name":           s.Name,
+	}
+	if override != nil {
+		override(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = KeyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]string{
+		"issuer":                 s.URL,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, map[string]any{"keys": []map[string]string{{
+		"kid": KeyID,
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != ClientID || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = authRequest{
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		redirectURI: query.Get("redirect_uri"),
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "bad redirect", http.StatusBadRequest)
+		return
+	}
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad form", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	req, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !ok, r.PostForm.Get("redirect_uri") != req.redirectURI:
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != req.challenge:
+		http.Error(w, `{"error":"invalid_grant","error_description":"pkce"}`, http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600, //nolint:mnd // секунды
+		"id_token":     s.IDToken(req.nonce, nil),
+	})
+}
+
+func randomString() string {
+	raw := make([]byte, 16) //nolint:mnd // длина случайного кода
+	_, _ = rand.Read(raw)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
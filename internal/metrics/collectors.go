@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector снимает статистику пула соединений pgx при каждом опросе.
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+
+	acquiredConns   *prometheus.Desc
+	idleConns       *prometheus.Desc
+	totalConns      *prometheus.Desc
+	maxConns        *prometheus.Desc
+	acquires        *prometheus.Desc
+	emptyAcquires   *prometheus.Desc
+	canceled        *prometheus.Desc
+	acquireDuration *prometheus.Desc
+}
+
+// NewPoolCollector описывает пул соединений; stat — например, (*pgxpool.Pool).Stat.
+func NewPoolCollector(stat func() *pgxpool.Stat) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		stat:            stat,
+		acquiredConns:   desc("acquired_connections", "Connections currently in use."),
+		idleConns:       desc("idle_connections", "Idle connections in the pool."),
+		totalConns:      desc("total_connections", "All connections in the pool."),
+		maxConns:        desc("max_connections", "Maximum size of the pool."),
+		acquires:        desc("acquires_total", "Successful connection acquires."),
+		emptyAcquires:   desc("empty_acquires_total", "Acquires that had to wait for a connection."),
+		canceled:        desc("canceled_acquires_total", "Acquires canceled by their context."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+// countCollector отдаёт gauge с числом объектов по значению метки, например заметок по статусам.
+// count вызывается при каждом опросе; его ошибка попадает в ответ, но не мешает остальным метрикам.
+type countCollector struct {
+	desc  *prometheus.Desc
+	count func() (map[string]int, error)
+}
+
+func NewCountCollector(name, help, label string, count func() (map[string]int, error)) prometheus.Collector {
+	return &countCollector{
+		desc:  prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, []string{label}, nil),
+		count: count,
+	}
+}
+
+func (c *countCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *countCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.count()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for value, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), value)
+	}
+}
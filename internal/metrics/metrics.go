@@ -0,0 +1,127 @@
+// Package metrics собирает метрики сервиса в формате Prometheus. Метрики регистрируются
+// в собственном реестре пакета, а отдаёт их Handler на /metrics.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "todolist"
+
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+//nolint:gochecknoglobals // реестр и метрики общие для всего процесса
+var (
+	Registry = newRegistry()
+
+	httpRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	repositoryDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "repository_operation_duration_seconds",
+		Help:      "Repository operation latency by storage, operation and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"storage", "operation", "outcome"})
+
+	syncRuns = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_runs_total",
+		Help:      "Runs of the notes file to database sync by outcome.",
+	}, []string{"outcome"})
+
+	syncNotes = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_notes_total",
+		Help:      "Notes copied to the database by the sync, by outcome.",
+	}, []string{"outcome"})
+
+	syncDuration = promauto.With(Registry).NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_duration_seconds",
+		Help:      "Duration of the notes file to database sync.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	batchDeletions = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "batch_deletion_runs_total",
+		Help:      "Runs of the deleted notes batch deleter by outcome.",
+	}, []string{"outcome"})
+
+	batchDeletedNotes = promauto.With(Registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "batch_deleted_notes_total",
+		Help:      "Notes removed by the batch deleter.",
+	})
+)
+
+func newRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return registry
+}
+
+// Handler отдаёт метрики реестра. Сжатие отключено: ответы сжимает gzip-middleware сервера.
+// Ошибка одного сборщика не скрывает остальные метрики.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{
+		DisableCompression: true,
+		ErrorHandling:      promhttp.ContinueOnError,
+	})
+}
+
+// ObserveHTTP учитывает обработанный HTTP-запрос; route — шаблон маршрута, а не путь,
+// чтобы число рядов не зависело от идентификаторов в URL.
+func ObserveHTTP(method, route, status string, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, status).Inc()
+	httpDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// ObserveRepository учитывает операцию хранилища, начатую в start.
+func ObserveRepository(storage, operation string, start time.Time, err error) {
+	repositoryDuration.WithLabelValues(storage, operation, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveSync учитывает запуск синхронизации: сколько заметок скопировано и сколько не удалось.
+func ObserveSync(start time.Time, synced, failed int, err error) {
+	syncRuns.WithLabelValues(outcome(err)).Inc()
+	syncNotes.WithLabelValues(OutcomeSuccess).Add(float64(synced))
+	syncNotes.WithLabelValues(OutcomeError).Add(float64(failed))
+	syncDuration.Observe(time.Since(start).Seconds())
+}
+
+// ObserveBatchDeletion учитывает запуск пакетного удаления заметок.
+func ObserveBatchDeletion(deleted int64, err error) {
+	batchDeletions.WithLabelValues(outcome(err)).Inc()
+	batchDeletedNotes.Add(float64(deleted))
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountCollector(t *testing.T) {
+	collector := NewCountCollector("notes", "Notes by status.", "status", func() (map[string]int, error) {
+		return map[string]int{"New": 2, "Done": 1}, nil
+	})
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP todolist_notes Notes by status.
+# TYPE todolist_notes gauge
+todolist_notes{status="Done"} 1
+todolist_notes{status="New"} 2
+`))
+	require.NoError(t, err)
+
+	failing := NewCountCollector("notes", "Notes by status.", "status", func() (map[string]int, error) {
+		return nil, errors.New("storage is down")
+	})
+	_, err = testutil.CollectAndLint(failing)
+	assert.Error(t, err)
+}
+
+func TestObserveBatchDeletion(t *testing.T) {
+	before := testutil.ToFloat64(batchDeletedNotes)
+	failedBefore := testutil.ToFloat64(batchDeletions.WithLabelValues(OutcomeError))
+
+	ObserveBatchDeletion(3, nil)
+	ObserveBatchDeletion(0, errors.New("tx failed"))
+
+	assert.InDelta(t, before+3, testutil.ToFloat64(batchDeletedNotes), 0)
+	assert.InDelta(t, failedBefore+1, testutil.ToFloat64(batchDeletions.WithLabelValues(OutcomeError)), 0)
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *NotesAPI) unlockUser(ctx *gin.Context) {
+	var req users.UnlockRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	err := userService.UnlockAccount(req)
+	s.recordAudit(ctx, audit.Event{Type: audit.EventUserUnlocked, Target: unlockTarget(req)}, err)
+	if err != nil {
+		if errors.Is(err, users.ErrEmptyUnlock) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.String(http.StatusOK, "unlocked")
+}
+
+func unlockTarget(req users.UnlockRequest) string {
+	if req.Email == `` {
+		return req.IP
+	}
+	if req.IP == `` {
+		return req.Email
+	}
+	return req.Email + " " + req.IP
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLog interface {
+	AppendAudit(event audit.Event) (audit.Event, error)
+	ListAudit(filter audit.Filter) ([]audit.Event, error)
+	WalkAudit(fn func(event audit.Event) error) error
+}
+
+// WithAuditLog включает журнал событий безопасности и GET /admin/audit.
+func WithAuditLog(log AuditLog) Option {
+	return func(nApi *NotesAPI) {
+		nApi.auditLog = log
+	}
+}
+
+// recordAudit пишет событие от имени текущего пользователя с IP запроса.
+func (s *NotesAPI) recordAudit(ctx *gin.Context, event audit.Event, err error) {
+	event.ActorUID = ctx.GetString("uid")
+	event.IP = ctx.ClientIP()
+	s.userService().Record(event, err)
+}
+
+func (s *NotesAPI) listAudit(ctx *gin.Context) {
+	var filter audit.Filter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	events, err := userService.ListAudit(filter)
+	if err != nil {
+		if errors.Is(err, audit.ErrNotConfigured) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, events)
+}
+
+// verifyAudit сверяет цепочку хэшей; при нарушении отвечает 409 с описанием места разрыва.
+func (s *NotesAPI) verifyAudit(ctx *gin.Context) {
+	userService := s.userService()
+	report, err := userService.VerifyAudit()
+	if err != nil {
+		if errors.Is(err, audit.ErrNotConfigured) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !report.Valid {
+		ctx.JSON(http.StatusConflict, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAudit(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com"}))
+	api := &NotesAPI{
+		log:      zerolog.Nop(),
+		repo:     repo,
+		auditLog: inmemory.NewAuditLog(100),
+		testMode: true,
+	}
+
+	r := gin.New()
+	admin := r.Group("/admin", api.JWTMiddleware(), api.AdminMiddleware())
+	admin.DELETE("/users/:id", api.deleteUser)
+	admin.GET("/audit", api.listAudit)
+	admin.GET("/audit/verify", api.verifyAudit)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	_, err := client.R().Delete(ts.URL + "/admin/users/uid-1")
+	require.NoError(t, err)
+	_, err = client.R().Delete(ts.URL + "/admin/users/missing")
+	require.NoError(t, err)
+
+	var events []audit.Event
+	resp, err := client.R().
+		SetQueryParams(map[string]string{
+			"type":    string(audit.EventUserDeleted),
+			"outcome": string(audit.OutcomeSuccess),
+			"since":   time.Now().Add(-time.Minute).Format(time.RFC3339),
+		}).
+		SetResult(&events).
+		Get(ts.URL + "/admin/audit")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Len(t, events, 1)
+	assert.Equal(t, "uid-1", events[0].Target)
+	assert.Equal(t, "test-user", events[0].ActorUID)
+	assert.NotEmpty(t, events[0].IP)
+
+	var report audit.Report
+	resp, err = client.R().SetResult(&report).Get(ts.URL + "/admin/audit/verify")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.True(t, report.Valid)
+	assert.Equal(t, 2, report.Checked)
+
+	resp, err = client.R().SetQueryParam("since", "yesterday").Get(ts.URL + "/admin/audit")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+}
+
+func TestLegacyDeleteUserAudit(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	session, err := api.userService().CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	token, err := jwtToken("uid-1", session.ID)
+	require.NoError(t, err)
+
+	resp, err := resty.New().R().SetHeader("Authorization", token).Delete(ts.URL + "/users/del/uid-1")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	events, err := api.auditLog.ListAudit(audit.Filter{Type: audit.EventUserDeleted})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "uid-1", events[0].ActorUID)
+	assert.Equal(t, "uid-1", events[0].Target)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (s *NotesAPI) getBoard(ctx *gin.Context) {
+	noteService := s.noteService()
+	board, err := noteService.GetBoard(ctx.GetString("uid"), ctx.Param("board"), s.preferences(ctx).Location())
+	if err != nil {
+		if errors.Is(err, notes.ErrBoardNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, board)
+}
+
+func (s *NotesAPI) moveOnBoard(ctx *gin.Context) {
+	var mReq notes.BoardMoveRequest
+	if err := ctx.BindJSON(&mReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+	if ctx.Param("board") != notes.DefaultBoard {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": notes.ErrBoardNotFound.Error()})
+		return
+	}
+
+	noteID := ctx.Param("id")
+	noteService := s.noteService()
+	position, err := noteService.MoveOnBoard(ctx.GetString("uid"), noteID, mReq)
+	if err != nil {
+		if errors.Is(err, notes.ErrNoteNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "No task"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"nid": noteID, "status": mReq.Status, "position": position})
+}
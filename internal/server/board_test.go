@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/server/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBoard(t *testing.T) {
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("StreamNotes", "test-user", mock.Anything).
+		Return(func(_ string, fn func(notes.Note) error) error {
+			for _, note := range []notes.Note{
+				{NID: "1", UID: "test-user", Title: "Todo", Status: notes.New},
+				{NID: "2", UID: "test-user", Title: "Doing", Status: notes.Active},
+			} {
+				if err := fn(note); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.GET("/boards/:board", api.JWTMiddleware(), api.getBoard)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	resp, err := client.R().Get(ts.URL + "/boards/default")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	var board notes.Board
+	require.NoError(t, json.Unmarshal(resp.Body(), &board))
+	assert.Equal(t, 2, board.Total)
+	assert.Equal(t, "Doing", board.Columns[notes.Active].Notes[0].Title)
+	mockRepo.AssertExpectations(t)
+
+	resp, err = client.R().Get(ts.URL + "/boards/unknown")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+}
+
+func TestMoveOnBoard(t *testing.T) {
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("GetNoteID", "123").Return(notes.Note{NID: "123", UID: "test-user"}, nil)
+	mockRepo.On("GetNoteID", "2").Return(notes.Note{NID: "2", UID: "test-user"}, nil)
+	mockRepo.On("MoveNoteToStatus", "123", notes.Status(notes.Inactive), notes.MoveRequest{Before: "2"}).
+		Return("a", nil)
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.POST("/boards/:board/notes/:id/move", api.JWTMiddleware(), api.moveOnBoard)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	resp, err := client.R().
+		SetBody(`{"status":"Inactive","before":"2"}`).
+		Post(ts.URL + "/boards/default/notes/123/move")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.String(), `"position":"a"`)
+
+	resp, err = client.R().
+		SetBody(`{"status":"Done"}`).
+		Post(ts.URL + "/boards/default/notes/123/move")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBoardIsPerUser(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	bob := sessionToken(t, api, "uid-1")
+	alice := sessionToken(t, api, "uid-2")
+
+	noteService := api.noteService()
+	first, err := noteService.CreateUserNote("uid-1", notes.Note{Title: "Bob first"})
+	require.NoError(t, err)
+	second, err := noteService.CreateUserNote("uid-1", notes.Note{Title: "Bob second"})
+	require.NoError(t, err)
+	own, err := noteService.CreateUserNote("uid-2", notes.Note{Title: "Alice"})
+	require.NoError(t, err)
+
+	client := resty.New().SetBaseURL(ts.URL).SetHeader("Accept-Encoding", "identity")
+
+	resp, err := client.R().SetHeader("Authorization", alice).Get("/boards/default")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	var board notes.Board
+	require.NoError(t, json.Unmarshal(resp.Body(), &board))
+	assert.Equal(t, 1, board.Total)
+	assert.NotContains(t, resp.String(), "Bob")
+
+	resp, err = client.R().SetHeader("Authorization", alice).
+		SetBody(`{"status":"Active"}`).
+		Post("/boards/default/notes/" + first.NID + "/move")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+	resp, err = client.R().SetHeader("Authorization", alice).
+		SetBody(`{"status":"Active","before":"` + second.NID + `"}`).
+		Post("/boards/default/notes/" + own.NID + "/move")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+	stored, err := noteService.GetUserNote("uid-1", first.NID)
+	require.NoError(t, err)
+	assert.Equal(t, notes.Status(notes.New), stored.Status)
+
+	resp, err = client.R().SetHeader("Authorization", bob).Get("/boards/default")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(resp.Body(), &board))
+	assert.Equal(t, 2, board.Total)
+	assert.NotContains(t, resp.String(), "Alice")
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Старые маршруты с глаголами в пути (/notes/add, /users/del/:id и т.п.) оставлены
+// как псевдонимы /api/v1 до перевода клиентов и будут удалены после legacySunset.
+var (
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // its ok
+	legacySunset       = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)   //nolint:gochecknoglobals // its ok
+)
+
+// Deprecated помечает ответ заголовками Deprecation (RFC 9745) и Sunset (RFC 8594)
+// и ссылкой на маршрут-замену; ":id" в successor заменяется параметром запроса.
+func (nApi *NotesAPI) Deprecated(successor string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", "@"+strconv.FormatInt(legacyDeprecatedAt.Unix(), 10))
+		ctx.Header("Sunset", legacySunset.Format(http.TimeFormat))
+		link := strings.ReplaceAll(successor, ":id", ctx.Param("id"))
+		ctx.Header("Link", "<"+link+`>; rel="successor-version"`)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/idempotency"
+	"github.com/gin-gonic/gin"
+)
+
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey занимает ключ и возвращает true; если ключ уже занят и не истёк,
+	// возвращает его запись и false.
+	ReserveIdempotencyKey(record idempotency.Record, now time.Time) (idempotency.Record, bool, error)
+	SaveIdempotentResponse(record idempotency.Record) error
+	// ReleaseIdempotencyKey освобождает ключ, ответ для которого не сохранён.
+	ReleaseIdempotencyKey(key string) error
+	PurgeIdempotencyKeys(now time.Time) (int, error)
+}
+
+// WithIdempotencyStore включает поддержку заголовка Idempotency-Key.
+func WithIdempotencyStore(store IdempotencyStore) Option {
+	return func(nApi *NotesAPI) {
+		nApi.idempotency = store
+	}
+}
+
+// PurgeIdempotencyKeys удаляет ключи, срок хранения ответов которых истёк.
+func (nApi *NotesAPI) PurgeIdempotencyKeys() (int, error) {
+	if nApi.idempotency == nil {
+		return 0, nil
+	}
+	return nApi.idempotency.PurgeIdempotencyKeys(time.Now().UTC())
+}
+
+// Idempotent выполняет запрос с Idempotency-Key один раз: повтор с тем же ключом и телом
+// получает сохранённый ответ, а с другим телом — 422. Ключи разделены по маршруту и
+// пользователю. Ответы 5xx не сохраняются, чтобы запрос можно было повторить.
+func (nApi *NotesAPI) Idempotent() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotency.Header)
+		if nApi.idempotency == nil || key == `` {
+			ctx.Next()
+			return
+		}
+		if len(key) > idempotency.MaxKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": idempotency.ErrKeyTooLong.Error()})
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now().UTC()
+		record := idempotency.Record{
+			Key:         strings.Join([]string{ctx.Request.Method, ctx.FullPath(), ctx.GetString("uid"), key}, " "),
+			Fingerprint: idempotency.Fingerprint(body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotency.LockTimeout),
+		}
+		existing, reserved, err := nApi.idempotency.ReserveIdempotencyKey(record, now)
+		if err != nil {
+			nApi.log.Error().Err(err).Msg("failed to reserve idempotency key")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if !reserved {
+			nApi.replay(ctx, existing, record.Fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		status := recorder.Status()
+		if !idempotency.Cacheable(status) {
+			if err = nApi.idempotency.ReleaseIdempotencyKey(record.Key); err != nil {
+				nApi.log.Error().Err(err).Msg("failed to release idempotency key")
+			}
+			return
+		}
+		record.Status = status
+		record.Body = recorder.body.Bytes()
+		record.Headers = make(map[string]string)
+		for _, header := range idempotency.StoredHeaders {
+			if value := recorder.Header().Get(header); value != `` {
+				record.Headers[header] = value
+			}
+		}
+		record.ExpiresAt = now.Add(nApi.idempotencyTTL())
+		if err = nApi.idempotency.SaveIdempotentResponse(record); err != nil {
+			nApi.log.Error().Err(err).Msg("failed to save idempotent response")
+		}
+	}
+}
+
+func (nApi *NotesAPI) replay(ctx *gin.Context, record idempotency.Record, fingerprint string) {
+	err := record.Replayable(fingerprint)
+	switch {
+	case errors.Is(err, idempotency.ErrKeyReused):
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, idempotency.ErrInProgress):
+		ctx.Header("Retry-After", "1")
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	for header, value := range record.Headers {
+		ctx.Header(header, value)
+	}
+	ctx.Header(idempotency.ReplayedHeader, "true")
+	ctx.Data(record.Status, record.Headers["Content-Type"], record.Body)
+	ctx.Abort()
+}
+
+func (nApi *NotesAPI) idempotencyTTL() time.Duration {
+	if nApi.cfg == nil || nApi.cfg.IdempotencyTTL <= 0 {
+		return idempotency.DefaultTTL
+	}
+	return nApi.cfg.IdempotencyTTL
+}
+
+// responseRecorder копирует тело ответа, чтобы его можно было сохранить.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/idempotency"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/server/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotentServer(t *testing.T, handler gin.HandlerFunc) (*httptest.Server, *inmemory.Idempotency) {
+	t.Helper()
+	store := inmemory.NewIdempotency()
+	api := NewTestNotesAPI(mocks.NewRepositoryNote(t))
+	api.idempotency = store
+
+	r := gin.New()
+	r.POST("/notes", api.Idempotent(), handler)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts, store
+}
+
+func TestIdempotent_ReplaysResponse(t *testing.T) {
+	mockRepo := mocks.NewRepositoryNote(t)
+	mockRepo.On("AddNote", mock.AnythingOfType("notes.Note")).Return(nil).Once()
+	api := NewTestNotesAPI(mockRepo)
+	api.idempotency = inmemory.NewIdempotency()
+
+	r := gin.New()
+	r.POST("/notes", api.Idempotent(), api.createNote)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	send := func(body string) *resty.Response {
+		resp, err := resty.New().R().
+			SetHeader("Content-Type", "application/json").
+			SetHeader(idempotency.Header, "key-1").
+			SetBody(body).
+			Post(ts.URL + "/notes")
+		require.NoError(t, err)
+		return resp
+	}
+
+	first := send(`{"title":"New Note","status":0}`)
+	require.Equal(t, http.StatusCreated, first.StatusCode())
+	assert.Empty(t, first.Header().Get(idempotency.ReplayedHeader))
+
+	second := send(`{"title":"New Note","status":0}`)
+	assert.Equal(t, http.StatusCreated, second.StatusCode())
+	assert.Equal(t, first.String(), second.String())
+	assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	assert.Equal(t, "true", second.Header().Get(idempotency.ReplayedHeader))
+
+	reused := send(`{"title":"Other Note","status":0}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.StatusCode())
+	assert.Contains(t, reused.String(), idempotency.ErrKeyReused.Error())
+}
+
+func TestIdempotent_ServerErrorsAreNotStored(t *testing.T) {
+	calls := 0
+	ts, _ := newIdempotentServer(t, func(ctx *gin.Context) {
+		calls++
+		if calls == 1 {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		ctx.JSON(http.StatusCreated, gin.H{"calls": calls})
+	})
+
+	for range 3 {
+		_, err := resty.New().R().SetHeader(idempotency.Header, "key-1").SetBody(`{}`).Post(ts.URL + "/notes")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotent_WithoutKey(t *testing.T) {
+	calls := 0
+	ts, _ := newIdempotentServer(t, func(ctx *gin.Context) {
+		calls++
+		ctx.Status(http.StatusCreated)
+	})
+
+	for range 2 {
+		_, err := resty.New().R().SetBody(`{}`).Post(ts.URL + "/notes")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotent_InProgress(t *testing.T) {
+	ts, store := newIdempotentServer(t, func(ctx *gin.Context) {
+		ctx.Status(http.StatusCreated)
+	})
+	now := time.Now().UTC()
+	_, reserved, err := store.ReserveIdempotencyKey(idempotency.Record{
+		Key:         "POST /notes  key-1",
+		Fingerprint: idempotency.Fingerprint([]byte(`{}`)),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(idempotency.LockTimeout),
+	}, now)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	resp, err := resty.New().R().SetHeader(idempotency.Header, "key-1").SetBody(`{}`).Post(ts.URL + "/notes")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode())
+	assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+}
+
+func TestIdempotent_TokenIsNotStored(t *testing.T) {
+	ts, _ := newIdempotentServer(t, func(ctx *gin.Context) {
+		ctx.Header("Authorization", "jwt")
+		ctx.String(http.StatusOK, "user registered")
+	})
+
+	send := func() *resty.Response {
+		resp, err := resty.New().R().SetHeader(idempotency.Header, "key-1").SetBody(`{}`).Post(ts.URL + "/notes")
+		require.NoError(t, err)
+		return resp
+	}
+	assert.Equal(t, "jwt", send().Header().Get("Authorization"))
+
+	replayed := send()
+	assert.Equal(t, "true", replayed.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, "user registered", replayed.String())
+	assert.Empty(t, replayed.Header().Get("Authorization"))
+}
+
+func TestIdempotent_KeysArePerUser(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	api.idempotency = inmemory.NewIdempotency()
+
+	create := func(uid string) notes.NoteResponseFormat {
+		session, err := api.userService().CreateSession(uid, "test", "127.0.0.1", time.Hour)
+		require.NoError(t, err)
+		token, err := jwtToken(uid, session.ID)
+		require.NoError(t, err)
+
+		var created notes.NoteResponseFormat
+		resp, err := newV1Client(ts).R().SetAuthToken(token).
+			SetHeader(idempotency.Header, "key-1").
+			SetBody(map[string]any{"title": "Buy milk for " + uid}).
+			SetResult(&created).
+			Post("/notes")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode(), resp.String())
+		assert.Empty(t, resp.Header().Get(idempotency.ReplayedHeader), uid)
+		return created
+	}
+	// Общий ключ дал бы второму пользователю 422 или чужой ответ.
+	assert.NotEqual(t, create("uid-1").NID, create("uid-2").NID)
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/metrics"
+)
+
+// InstrumentRepository замеряет время операций хранилища пользователей; storage — метка
+// хранилища в метриках, например postgres или memory.
+func InstrumentRepository(repo Repository, storage string) Repository {
+	return instrumentedRepository{Repository: repo, storage: storage}
+}
+
+// InstrumentNotes замеряет время операций хранилища заметок.
+func InstrumentNotes(repo RepositoryNote, storage string) RepositoryNote {
+	return instrumentedNotes{RepositoryNote: repo, storage: storage}
+}
+
+type instrumentedRepository struct {
+	Repository
+	storage string
+}
+
+func (r instrumentedRepository) observe(operation string, start time.Time, err error) {
+	metrics.ObserveRepository(r.storage, operation, start, err)
+}
+
+func (r instrumentedRepository) SaveUser(user users.User) error {
+	start := time.Now()
+	err := r.Repository.SaveUser(user)
+	r.observe("SaveUser", start, err)
+	return err
+}
+
+func (r instrumentedRepository) GetUser(login string) (users.User, error) {
+	start := time.Now()
+	result, err := r.Repository.GetUser(login)
+	r.observe("GetUser", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) DeleteUser(userID string) error {
+	start := time.Now()
+	err := r.Repository.DeleteUser(userID)
+	r.observe("DeleteUser", start, err)
+	return err
+}
+
+func (r instrumentedRepository) GetAllUsers() ([]users.User, error) {
+	start := time.Now()
+	result, err := r.Repository.GetAllUsers()
+	r.observe("GetAllUsers", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) GetUserID(userID string) (users.User, error) {
+	start := time.Now()
+	result, err := r.Repository.GetUserID(userID)
+	r.observe("GetUserID", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) UpdateUserID(userID string, user users.User) error {
+	start := time.Now()
+	err := r.Repository.UpdateUserID(userID, user)
+	r.observe("UpdateUserID", start, err)
+	return err
+}
+
+func (r instrumentedRepository) VerifyUser(userID string) error {
+	start := time.Now()
+	err := r.Repository.VerifyUser(userID)
+	r.observe("VerifyUser", start, err)
+	return err
+}
+
+func (r instrumentedRepository) SaveResetToken(token users.ResetToken) error {
+	start := time.Now()
+	err := r.Repository.SaveResetToken(token)
+	r.observe("SaveResetToken", start, err)
+	return err
+}
+
+func (r instrumentedRepository) ConsumeResetToken(hash string) (users.ResetToken, error) {
+	start := time.Now()
+	result, err := r.Repository.ConsumeResetToken(hash)
+	r.observe("ConsumeResetToken", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) ResetPassword(userID, password string, changedAt time.Time) error {
+	start := time.Now()
+	err := r.Repository.ResetPassword(userID, password, changedAt)
+	r.observe("ResetPassword", start, err)
+	return err
+}
+
+func (r instrumentedRepository) UpdateTOTP(userID string, totp users.TOTP) error {
+	start := time.Now()
+	err := r.Repository.UpdateTOTP(userID, totp)
+	r.observe("UpdateTOTP", start, err)
+	return err
+}
+
+func (r instrumentedRepository) ConsumeRecoveryCode(userID, codeHash string) error {
+	start := time.Now()
+	err := r.Repository.ConsumeRecoveryCode(userID, codeHash)
+	r.observe("ConsumeRecoveryCode", start, err)
+	return err
+}
+
+func (r instrumentedRepository) UseTOTPStep(userID string, step int64) error {
+	start := time.Now()
+	err := r.Repository.UseTOTPStep(userID, step)
+	r.observe("UseTOTPStep", start, err)
+	return err
+}
+
+func (r instrumentedRepository) SaveAccessToken(token users.AccessToken) error {
+	start := time.Now()
+	err := r.Repository.SaveAccessToken(token)
+	r.observe("SaveAccessToken", start, err)
+	return err
+}
+
+func (r instrumentedRepository) GetAccessTokens(userID string) ([]users.AccessToken, error) {
+	start := time.Now()
+	result, err := r.Repository.GetAccessTokens(userID)
+	r.observe("GetAccessTokens", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) GetAccessTokenByHash(hash string) (users.AccessToken, error) {
+	start := time.Now()
+	result, err := r.Repository.GetAccessTokenByHash(hash)
+	r.observe("GetAccessTokenByHash", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) DeleteAccessToken(userID, tokenID string) error {
+	start := time.Now()
+	err := r.Repository.DeleteAccessToken(userID, tokenID)
+	r.observe("DeleteAccessToken", start, err)
+	return err
+}
+
+func (r instrumentedRepository) SaveIdentity(identity users.Identity) error {
+	start := time.Now()
+	err := r.Repository.SaveIdentity(identity)
+	r.observe("SaveIdentity", start, err)
+	return err
+}
+
+func (r instrumentedRepository) GetUserByIdentity(issuer, subject string) (users.User, error) {
+	start := time.Now()
+	result, err := r.Repository.GetUserByIdentity(issuer, subject)
+	r.observe("GetUserByIdentity", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) GetIdentities(userID string) ([]users.Identity, error) {
+	start := time.Now()
+	result, err := r.Repository.GetIdentities(userID)
+	r.observe("GetIdentities", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) SaveSession(session users.Session) error {
+	start := time.Now()
+	err := r.Repository.SaveSession(session)
+	r.observe("SaveSession", start, err)
+	return err
+}
+
+func (r instrumentedRepository) GetSessions(userID string) ([]users.Session, error) {
+	start := time.Now()
+	result, err := r.Repository.GetSessions(userID)
+	r.observe("GetSessions", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) GetSession(sessionID string) (users.Session, error) {
+	start := time.Now()
+	result, err := r.Repository.GetSession(sessionID)
+	r.observe("GetSession", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) TouchSession(sessionID string, lastSeen time.Time) error {
+	start := time.Now()
+	err := r.Repository.TouchSession(sessionID, lastSeen)
+	r.observe("TouchSession", start, err)
+	return err
+}
+
+func (r instrumentedRepository) DeleteSession(userID, sessionID string) error {
+	start := time.Now()
+	err := r.Repository.DeleteSession(userID, sessionID)
+	r.observe("DeleteSession", start, err)
+	return err
+}
+
+func (r instrumentedRepository) ScheduleDeletion(userID string, deleteAt *time.Time) error {
+	start := time.Now()
+	err := r.Repository.ScheduleDeletion(userID, deleteAt)
+	r.observe("ScheduleDeletion", start, err)
+	return err
+}
+
+func (r instrumentedRepository) GetUsersDueForDeletion(now time.Time) ([]users.User, error) {
+	start := time.Now()
+	result, err := r.Repository.GetUsersDueForDeletion(now)
+	r.observe("GetUsersDueForDeletion", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) GetPreferences(userID string) (users.Preferences, error) {
+	start := time.Now()
+	result, err := r.Repository.GetPreferences(userID)
+	r.observe("GetPreferences", start, err)
+	return result, err
+}
+
+func (r instrumentedRepository) SavePreferences(userID string, prefs users.Preferences) error {
+	start := time.Now()
+	err := r.Repository.SavePreferences(userID, prefs)
+	r.observe("SavePreferences", start, err)
+	return err
+}
+
+type instrumentedNotes struct {
+	RepositoryNote
+	storage string
+}
+
+func (r instrumentedNotes) observe(operation string, start time.Time, err error) {
+	metrics.ObserveRepository(r.storage, operation, start, err)
+}
+
+func (r instrumentedNotes) AddNote(note notes.Note) error {
+	start := time.Now()
+	err := r.RepositoryNote.AddNote(note)
+	r.observe("AddNote", start, err)
+	return err
+}
+
+func (r instrumentedNotes) GetNotes() ([]notes.Note, error) {
+	start := time.Now()
+	result, err := r.RepositoryNote.GetNotes()
+	r.observe("GetNotes", start, err)
+	return result, err
+}
+
+func (r instrumentedNotes) GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error) {
+	start := time.Now()
+	result, err := r.RepositoryNote.GetNotesSorted(sortBy)
+	r.observe("GetNotesSorted", start, err)
+	return result, err
+}
+
+func (r instrumentedNotes) GetNoteID(noteID string) (notes.Note, error) {
+	start := time.Now()
+	result, err := r.RepositoryNote.GetNoteID(noteID)
+	r.observe("GetNoteID", start, err)
+	return result, err
+}
+
+func (r instrumentedNotes) DeleteNote(noteID string) error {
+	start := time.Now()
+	err := r.RepositoryNote.DeleteNote(noteID)
+	r.observe("DeleteNote", start, err)
+	return err
+}
+
+func (r instrumentedNotes) UpdateNote(noteID string, note notes.Note) error {
+	start := time.Now()
+	err := r.RepositoryNote.UpdateNote(noteID, note)
+	r.observe("UpdateNote", start, err)
+	return err
+}
+
+func (r instrumentedNotes) MoveNote(noteID string, position string) error {
+	start := time.Now()
+	err := r.RepositoryNote.MoveNote(noteID, position)
+	r.observe("MoveNote", start, err)
+	return err
+}
+
+func (r instrumentedNotes) MoveNoteToStatus(noteID string, status notes.Status, move notes.MoveRequest) (string, error) {
+	start := time.Now()
+	result, err := r.RepositoryNote.MoveNoteToStatus(noteID, status, move)
+	r.observe("MoveNoteToStatus", start, err)
+	return result, err
+}
+
+func (r instrumentedNotes) ApplyBulk(ops []notes.BulkOperation, mode notes.BulkMode) ([]notes.BulkResult, error) {
+	start := time.Now()
+	result, err := r.RepositoryNote.ApplyBulk(ops, mode)
+	r.observe("ApplyBulk", start, err)
+	return result, err
+}
+
+func (r instrumentedNotes) StreamNotes(uid string, fn func(note notes.Note) error) error {
+	start := time.Now()
+	err := r.RepositoryNote.StreamNotes(uid, fn)
+	r.observe("StreamNotes", start, err)
+	return err
+}
+
+func (r instrumentedNotes) DeleteUserNotes(uid string) (int, error) {
+	start := time.Now()
+	result, err := r.RepositoryNote.DeleteUserNotes(uid)
+	r.observe("DeleteUserNotes", start, err)
+	return result, err
+}
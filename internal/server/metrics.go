@@ -0,0 +1,52 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+const metricsPath = "/metrics"
+
+// unmatchedRoute — метка запросов к несуществующим маршрутам: их пути в метки не попадают.
+const unmatchedRoute = "unmatched"
+
+// Metrics считает запросы и их длительность по шаблону маршрута и статусу ответа.
+func (nApi *NotesAPI) Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == `` {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTP(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status()), time.Since(start))
+	}
+}
+
+// MetricsAuth пускает к /metrics только с токеном Config.MetricsToken: метрики раскрывают
+// маршруты, нагрузку и объём данных. Без токена метрики отключены.
+func (nApi *NotesAPI) MetricsAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if nApi.cfg == nil || nApi.cfg.MetricsToken == `` {
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "metrics are not configured"})
+			return
+		}
+		token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(nApi.cfg.MetricsToken)) != 1 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func (nApi *NotesAPI) getMetrics(ctx *gin.Context) {
+	metrics.Handler().ServeHTTP(ctx.Writer, ctx.Request)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	api := &NotesAPI{
+		cfg:       &internal.Config{MetricsToken: "scrape"},
+		httpServe: &http.Server{},
+		repo:      inmemory.NewUsers(),
+		repoNote:  inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json")),
+		log:       zerolog.Nop(),
+		testMode:  true,
+	}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+	client := newV1Client(ts)
+
+	resp, err := client.R().Get("/notes")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	_, err = client.R().Get("/missing/42")
+	require.NoError(t, err)
+
+	scraper := resty.New().SetHeader("Accept-Encoding", "identity")
+	resp, err = scraper.R().Get(ts.URL + metricsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	resp, err = scraper.R().SetAuthToken("wrong").Get(ts.URL + metricsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+
+	resp, err = scraper.R().SetAuthToken("scrape").Get(ts.URL + metricsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, resp.String(), `todolist_http_requests_total{method="GET",route="/api/v1/notes",status="200"}`)
+	assert.Contains(t, resp.String(), `route="unmatched",status="404"`)
+	assert.NotContains(t, resp.String(), "/missing/42")
+	assert.Contains(t, resp.String(), "todolist_http_request_duration_seconds_bucket")
+}
+
+func TestInstrumentNotes(t *testing.T) {
+	repo := InstrumentNotes(inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json")), "memory")
+
+	require.NoError(t, repo.AddNote(notes.Note{NID: "1", Title: "Note"}))
+	_, err := repo.GetNoteID("missing")
+	require.Error(t, err, "errors are passed through")
+
+	note, err := repo.GetNoteID("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Note", note.Title)
+}
+
+func TestMetricsWithoutToken(t *testing.T) {
+	ts, _ := newV1TestServer(t)
+	resp, err := resty.New().R().Get(ts.URL + metricsPath)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	users "github.com/Snoop-Duck/ToDoList/internal/domain/users"
 )
 
@@ -31,6 +33,88 @@ func (_m *Repository) Close() error {
 	return r0
 }
 
+// ConsumeRecoveryCode provides a mock function with given fields: userID, codeHash
+func (_m *Repository) ConsumeRecoveryCode(userID string, codeHash string) error {
+	ret := _m.Called(userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeResetToken provides a mock function with given fields: hash
+func (_m *Repository) ConsumeResetToken(hash string) (users.ResetToken, error) {
+	ret := _m.Called(hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeResetToken")
+	}
+
+	var r0 users.ResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (users.ResetToken, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(string) users.ResetToken); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(users.ResetToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAccessToken provides a mock function with given fields: userID, tokenID
+func (_m *Repository) DeleteAccessToken(userID string, tokenID string) error {
+	ret := _m.Called(userID, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: userID, sessionID
+func (_m *Repository) DeleteSession(userID string, sessionID string) error {
+	ret := _m.Called(userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteUser provides a mock function with given fields: userID
 func (_m *Repository) DeleteUser(userID string) error {
 	ret := _m.Called(userID)
@@ -49,6 +133,64 @@ func (_m *Repository) DeleteUser(userID string) error {
 	return r0
 }
 
+// GetAccessTokenByHash provides a mock function with given fields: hash
+func (_m *Repository) GetAccessTokenByHash(hash string) (users.AccessToken, error) {
+	ret := _m.Called(hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccessTokenByHash")
+	}
+
+	var r0 users.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (users.AccessToken, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(string) users.AccessToken); ok {
+		r0 = rf(hash)
+	} else {
+		r0 = ret.Get(0).(users.AccessToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccessTokens provides a mock function with given fields: userID
+func (_m *Repository) GetAccessTokens(userID string) ([]users.AccessToken, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccessTokens")
+	}
+
+	var r0 []users.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]users.AccessToken, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []users.AccessToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]users.AccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllUsers provides a mock function with no fields
 func (_m *Repository) GetAllUsers() ([]users.User, error) {
 	ret := _m.Called()
@@ -79,6 +221,122 @@ func (_m *Repository) GetAllUsers() ([]users.User, error) {
 	return r0, r1
 }
 
+// GetIdentities provides a mock function with given fields: userID
+func (_m *Repository) GetIdentities(userID string) ([]users.Identity, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdentities")
+	}
+
+	var r0 []users.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]users.Identity, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []users.Identity); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]users.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPreferences provides a mock function with given fields: userID
+func (_m *Repository) GetPreferences(userID string) (users.Preferences, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPreferences")
+	}
+
+	var r0 users.Preferences
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (users.Preferences, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) users.Preferences); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(users.Preferences)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: sessionID
+func (_m *Repository) GetSession(sessionID string) (users.Session, error) {
+	ret := _m.Called(sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSession")
+	}
+
+	var r0 users.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (users.Session, error)); ok {
+		return rf(sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(string) users.Session); ok {
+		r0 = rf(sessionID)
+	} else {
+		r0 = ret.Get(0).(users.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: userID
+func (_m *Repository) GetSessions(userID string) ([]users.Session, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessions")
+	}
+
+	var r0 []users.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]users.Session, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []users.Session); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]users.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: login
 func (_m *Repository) GetUser(login string) (users.User, error) {
 	ret := _m.Called(login)
@@ -107,6 +365,34 @@ func (_m *Repository) GetUser(login string) (users.User, error) {
 	return r0, r1
 }
 
+// GetUserByIdentity provides a mock function with given fields: issuer, subject
+func (_m *Repository) GetUserByIdentity(issuer string, subject string) (users.User, error) {
+	ret := _m.Called(issuer, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByIdentity")
+	}
+
+	var r0 users.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (users.User, error)); ok {
+		return rf(issuer, subject)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) users.User); ok {
+		r0 = rf(issuer, subject)
+	} else {
+		r0 = ret.Get(0).(users.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(issuer, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserID provides a mock function with given fields: userID
 func (_m *Repository) GetUserID(userID string) (users.User, error) {
 	ret := _m.Called(userID)
@@ -135,6 +421,144 @@ func (_m *Repository) GetUserID(userID string) (users.User, error) {
 	return r0, r1
 }
 
+// GetUsersDueForDeletion provides a mock function with given fields: now
+func (_m *Repository) GetUsersDueForDeletion(now time.Time) ([]users.User, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersDueForDeletion")
+	}
+
+	var r0 []users.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]users.User, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []users.User); ok {
+		r0 = rf(now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]users.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetPassword provides a mock function with given fields: userID, password, changedAt
+func (_m *Repository) ResetPassword(userID string, password string, changedAt time.Time) error {
+	ret := _m.Called(userID, password, changedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) error); ok {
+		r0 = rf(userID, password, changedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveAccessToken provides a mock function with given fields: token
+func (_m *Repository) SaveAccessToken(token users.AccessToken) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(users.AccessToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveIdentity provides a mock function with given fields: identity
+func (_m *Repository) SaveIdentity(identity users.Identity) error {
+	ret := _m.Called(identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(users.Identity) error); ok {
+		r0 = rf(identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePreferences provides a mock function with given fields: userID, prefs
+func (_m *Repository) SavePreferences(userID string, prefs users.Preferences) error {
+	ret := _m.Called(userID, prefs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavePreferences")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, users.Preferences) error); ok {
+		r0 = rf(userID, prefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveResetToken provides a mock function with given fields: token
+func (_m *Repository) SaveResetToken(token users.ResetToken) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(users.ResetToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSession provides a mock function with given fields: session
+func (_m *Repository) SaveSession(session users.Session) error {
+	ret := _m.Called(session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(users.Session) error); ok {
+		r0 = rf(session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveUser provides a mock function with given fields: user
 func (_m *Repository) SaveUser(user users.User) error {
 	ret := _m.Called(user)
@@ -153,6 +577,60 @@ func (_m *Repository) SaveUser(user users.User) error {
 	return r0
 }
 
+// ScheduleDeletion provides a mock function with given fields: userID, deleteAt
+func (_m *Repository) ScheduleDeletion(userID string, deleteAt *time.Time) error {
+	ret := _m.Called(userID, deleteAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleDeletion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *time.Time) error); ok {
+		r0 = rf(userID, deleteAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: sessionID, lastSeen
+func (_m *Repository) TouchSession(sessionID string, lastSeen time.Time) error {
+	ret := _m.Called(sessionID, lastSeen)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(sessionID, lastSeen)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTOTP provides a mock function with given fields: userID, totp
+func (_m *Repository) UpdateTOTP(userID string, totp users.TOTP) error {
+	ret := _m.Called(userID, totp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, users.TOTP) error); ok {
+		r0 = rf(userID, totp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUserID provides a mock function with given fields: userID, user
 func (_m *Repository) UpdateUserID(userID string, user users.User) error {
 	ret := _m.Called(userID, user)
@@ -171,6 +649,42 @@ func (_m *Repository) UpdateUserID(userID string, user users.User) error {
 	return r0
 }
 
+// UseTOTPStep provides a mock function with given fields: userID, step
+func (_m *Repository) UseTOTPStep(userID string, step int64) error {
+	ret := _m.Called(userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int64) error); ok {
+		r0 = rf(userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyUser provides a mock function with given fields: userID
+func (_m *Repository) VerifyUser(userID string) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
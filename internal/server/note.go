@@ -1,10 +1,13 @@
 package server
 
 import (
+	"cmp"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
-	"github.com/Snoop-Duck/ToDoList/internal/services/note"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,31 +18,52 @@ func (s *NotesAPI) createNote(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
 		return
 	}
-	noteService := note.New(s.repoNote)
+	noteService := s.noteService()
 
-	noteID, err := noteService.CreateNote(nReq)
+	note, err := noteService.CreateUserNote(ctx.GetString("uid"), nReq)
 	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
-	ctx.String(http.StatusCreated, "Note add: %s", noteID)
+	ctx.String(http.StatusCreated, "Note add: %s", note.NID)
+}
+
+// querySort читает параметр sort; без него заметки упорядочиваются так, как выбрал пользователь.
+func querySort(ctx *gin.Context, prefs users.Preferences) (notes.SortBy, error) {
+	if sortParam, ok := ctx.GetQuery("sort"); ok {
+		return notes.ParseSortBy(sortParam)
+	}
+	return prefs.DefaultSort, nil
 }
 
 func (s *NotesAPI) getNotes(ctx *gin.Context) {
 	s.log.Debug().Str("uid", ctx.GetString("uid")).Msg("user id from gin context")
-	noteService := note.New(s.repoNote)
+	sortBy, err := querySort(ctx, s.preferences(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	noteService := s.noteService()
 
-	notes, err := noteService.GetNotes()
+	var notesList []notes.Note
+	if sortBy == notes.SortDefault {
+		notesList, err = noteService.GetNotes()
+	} else {
+		notesList, err = noteService.GetNotesSorted(sortBy)
+	}
 	if err != nil {
 		ctx.JSON(http.StatusNoContent, gin.H{"error": "No tasks"})
 		return
 	}
-	ctx.String(http.StatusAccepted, "Notes get: %v", notes)
+	ctx.String(http.StatusAccepted, "Notes get: %v", notesList)
 }
 
 func (s *NotesAPI) getNoteID(ctx *gin.Context) {
 	noteID := ctx.Param("id")
-	noteService := note.New(s.repoNote)
+	noteService := s.noteService()
 	note, err := noteService.GetNoteID(noteID)
 	if err != nil {
 		ctx.JSON(http.StatusNoContent, gin.H{"error": "No task"})
@@ -50,7 +74,7 @@ func (s *NotesAPI) getNoteID(ctx *gin.Context) {
 
 func (s *NotesAPI) deleteNote(ctx *gin.Context) {
 	noteID := ctx.Param("id")
-	noteService := note.New(s.repoNote)
+	noteService := s.noteService()
 	err := noteService.DeleteNoteID(noteID)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No task"})
@@ -66,11 +90,122 @@ func (s *NotesAPI) updateNote(ctx *gin.Context) {
 		return
 	}
 	noteID := ctx.Param("id")
-	noteService := note.New(s.repoNote)
-	err := noteService.UpdateNoteID(noteID, nReq)
+	noteService := s.noteService()
+	_, err := noteService.ReplaceUserNote(ctx.GetString("uid"), noteID, nReq)
 	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No task"})
 		return
 	}
 	ctx.String(http.StatusOK, "Note update: %s", noteID)
 }
+
+func (s *NotesAPI) moveNote(ctx *gin.Context) {
+	var mReq notes.MoveRequest
+	if err := ctx.BindJSON(&mReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+	noteID := ctx.Param("id")
+	noteService := s.noteService()
+	position, err := noteService.MoveNote(ctx.GetString("uid"), noteID, mReq)
+	if err != nil {
+		if errors.Is(err, notes.ErrNoteNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "No task"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.String(http.StatusOK, "Note moved: %s, position: %s", noteID, position)
+}
+
+func (s *NotesAPI) bulkNotes(ctx *gin.Context) {
+	var bReq notes.BulkRequest
+	if err := ctx.BindJSON(&bReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+	noteService := s.noteService()
+	response, err := noteService.ApplyBulk(ctx.GetString("uid"), bReq)
+	switch {
+	case errors.Is(err, notes.ErrBulkFailed):
+		ctx.JSON(http.StatusConflict, response)
+	case errors.Is(err, notes.ErrInvalidBulkMode),
+		errors.Is(err, notes.ErrBulkEmpty),
+		errors.Is(err, notes.ErrBulkTooLarge):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case response.Failed > 0:
+		ctx.JSON(http.StatusMultiStatus, response)
+	default:
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func (s *NotesAPI) importNotes(ctx *gin.Context) {
+	format, err := notes.ParseImportFormat(ctx.Query("format"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mapping, err := notes.ParseColumnMapping(ctx.Query("map"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun, err := strconv.ParseBool(cmp.Or(ctx.Query("dry_run"), "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid dry_run"})
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	noteService := s.noteService()
+	report, err := noteService.ImportNotes(body, notes.ImportOptions{
+		UID:     ctx.GetString("uid"),
+		Format:  format,
+		DryRun:  dryRun,
+		Mapping: mapping,
+	})
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "import file is too large"})
+			return
+		}
+		if errors.Is(err, notes.ErrInvalidImport) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 && !report.DryRun {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, report)
+}
+
+func (s *NotesAPI) exportNotes(ctx *gin.Context) {
+	format, err := notes.ParseExportFormat(ctx.Query("format"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", exportContentTypes[format])
+	ctx.Header("Content-Disposition", `attachment; filename="notes.`+string(format)+`"`)
+	ctx.Status(http.StatusOK)
+
+	noteService := s.noteService()
+	if err = noteService.ExportNotes(ctx.GetString("uid"), format, s.preferences(ctx).Location(), ctx.Writer); err != nil {
+		// Заголовки уже отправлены, поэтому ошибку можно только залогировать.
+		s.log.Error().Err(err).Str("format", string(format)).Msg("failed to export notes")
+	}
+}
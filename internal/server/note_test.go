@@ -3,15 +3,19 @@ package server
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
 	"github.com/Snoop-Duck/ToDoList/internal/server/mocks"
 	"github.com/gin-gonic/gin"
 	"github.com/go-resty/resty/v2"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type NotesAPITest struct {
@@ -57,12 +61,14 @@ func TestGetNotes(t *testing.T) {
 
 func TestCreateNote(t *testing.T) {
 	mockRepo := new(mocks.RepositoryNote)
-	mockRepo.On("AddNote", mock.AnythingOfType("notes.Note")).Return(nil)
+	mockRepo.On("AddNote", mock.MatchedBy(func(note notes.Note) bool {
+		return note.UID == "test-user" && !note.CreatedAt.IsZero()
+	})).Return(nil)
 
 	api := NewTestNotesAPI(mockRepo)
 
 	r := gin.New()
-	r.POST("/notes", api.createNote)
+	r.POST("/notes", api.JWTMiddleware(), api.createNote)
 
 	ts := httptest.NewServer(r)
 	defer ts.Close()
@@ -70,7 +76,7 @@ func TestCreateNote(t *testing.T) {
 	client := resty.New()
 	resp, err := client.R().
 		SetHeader("Content-Type", "application/json").
-		SetBody(`{"title":"New Note","status":0,"uid":"user1"}`).
+		SetBody(`{"title":"New Note","status":0}`).
 		Post(ts.URL + "/notes")
 
 	assert.NoError(t, err)
@@ -106,13 +112,20 @@ func TestGetNoteByID(t *testing.T) {
 }
 
 func TestUpdateNote(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	mockRepo := new(mocks.RepositoryNote)
-	mockRepo.On("UpdateNote", "123", mock.AnythingOfType("notes.Note")).Return(nil)
+	mockRepo.On("GetNoteID", "123").
+		Return(notes.Note{NID: "123", UID: "test-user", Title: "Old", Position: "m", CreatedAt: createdAt}, nil)
+	mockRepo.On("GetNoteID", "foreign").Return(notes.Note{NID: "foreign", UID: "other-user", Title: "Theirs"}, nil)
+	mockRepo.On("UpdateNote", "123", mock.MatchedBy(func(note notes.Note) bool {
+		return note.Title == "Updated Note" && note.UID == "test-user" && note.NID == "123" &&
+			note.Position == "m" && note.CreatedAt.Equal(createdAt)
+	})).Return(nil)
 
 	api := NewTestNotesAPI(mockRepo)
 
 	r := gin.New()
-	r.PUT("/notes/:id", api.updateNote)
+	r.PUT("/notes/:id", api.JWTMiddleware(), api.updateNote)
 
 	ts := httptest.NewServer(r)
 	defer ts.Close()
@@ -124,6 +137,13 @@ func TestUpdateNote(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().
+		SetBody(`{"title":"Updated Note","status":1}`).
+		Put(ts.URL + "/notes/foreign")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
 	mockRepo.AssertExpectations(t)
 }
 
@@ -146,3 +166,214 @@ func TestDeleteNote(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode())
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetNotesSorted(t *testing.T) {
+	testNotes := []notes.Note{
+		{NID: "1", Title: "Urgent Note", Priority: notes.Urgent},
+		{NID: "2", Title: "Low Note", Priority: notes.Low},
+	}
+
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("GetNotesSorted", notes.SortPriority).Return(testNotes, nil)
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.GET("/notes/list", api.JWTMiddleware(), api.getNotes)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	resp, err := client.R().Get(ts.URL + "/notes/list?sort=priority")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode())
+	assert.Contains(t, resp.String(), "Urgent Note")
+	mockRepo.AssertExpectations(t)
+
+	resp, err = client.R().Get(ts.URL + "/notes/list?sort=unknown")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+}
+
+func TestMoveNote(t *testing.T) {
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("GetNoteID", "123").Return(notes.Note{NID: "123", UID: "test-user", Position: "z"}, nil)
+	mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", UID: "test-user", Position: "a"}, nil)
+	mockRepo.On("GetNoteID", "2").Return(notes.Note{NID: "2", UID: "test-user", Position: "c"}, nil)
+	mockRepo.On("GetNoteID", "404").Return(notes.Note{}, notes.ErrNoteNotFound)
+	mockRepo.On("GetNoteID", "foreign").Return(notes.Note{NID: "foreign", UID: "other-user", Position: "b"}, nil)
+	mockRepo.On("MoveNote", "123", "b").Return(nil)
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.POST("/notes/:id/move", api.JWTMiddleware(), api.moveNote)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	resp, err := client.R().
+		SetBody(`{"after":"1","before":"2"}`).
+		Post(ts.URL + "/notes/123/move")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.String(), "position: b")
+
+	resp, err = client.R().
+		SetBody(`{"after":"1","before":"2"}`).
+		Post(ts.URL + "/notes/404/move")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+	resp, err = client.R().
+		SetBody(`{"after":"1","before":"2"}`).
+		Post(ts.URL + "/notes/foreign/move")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+	resp, err = client.R().
+		SetBody(`{"after":"foreign"}`).
+		Post(ts.URL + "/notes/123/move")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBulkNotes(t *testing.T) {
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", UID: "test-user"}, nil)
+	mockRepo.On("ApplyBulk", mock.Anything, notes.BulkAtomic).
+		Return([]notes.BulkResult{{Index: 0, Action: notes.BulkDelete, NID: "1"}}, nil).Once()
+	mockRepo.On("ApplyBulk", mock.Anything, notes.BulkAtomic).
+		Return([]notes.BulkResult{{Index: 0, Action: notes.BulkDelete, NID: "1", Error: "note not found"}},
+			notes.ErrBulkFailed).Once()
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.POST("/notes/bulk", api.JWTMiddleware(), api.bulkNotes)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	body := `{"mode":"atomic","operations":[{"action":"delete","nid":"1"}]}`
+
+	resp, err := client.R().SetBody(body).Post(ts.URL + "/notes/bulk")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.String(), `"succeeded":1`)
+
+	resp, err = client.R().SetBody(body).Post(ts.URL + "/notes/bulk")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode())
+	assert.Contains(t, resp.String(), `"applied":false`)
+
+	resp, err = client.R().SetBody(`{"operations":[]}`).Post(ts.URL + "/notes/bulk")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportNotes(t *testing.T) {
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("StreamNotes", "test-user", mock.Anything).
+		Return(func(_ string, fn func(notes.Note) error) error {
+			return fn(notes.Note{NID: "1", UID: "test-user", Title: "Existing"})
+		})
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.POST("/notes/import", api.JWTMiddleware(), api.importNotes)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	resp, err := client.R().
+		SetBody("- [ ] Existing\n- [ ] Fresh\n").
+		Post(ts.URL + "/notes/import?format=md&dry_run=true")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.String(), `"created":1`)
+	assert.Contains(t, resp.String(), `"duplicates":1`)
+
+	resp, err = client.R().SetBody("x").Post(ts.URL + "/notes/import?format=xml")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	oversized := strings.Repeat("- [ ] Note\n", maxImportSize/10+1)
+	resp, err = client.R().SetBody(oversized).Post(ts.URL + "/notes/import?format=md&dry_run=true")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportNotes(t *testing.T) {
+	mockRepo := new(mocks.RepositoryNote)
+	mockRepo.On("StreamNotes", "test-user", mock.Anything).Return(func(_ string, fn func(notes.Note) error) error {
+		return fn(notes.Note{NID: "1", Title: "Exported Note", UID: "test-user"})
+	})
+
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.GET("/notes/export", api.JWTMiddleware(), api.exportNotes)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := resty.New()
+	resp, err := client.R().Get(ts.URL + "/notes/export?format=md")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.Header().Get("Content-Type"), "text/markdown")
+	assert.Contains(t, resp.String(), "- [ ] Exported Note")
+
+	resp, err = client.R().Get(ts.URL + "/notes/export?format=pdf")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateNoteValidation(t *testing.T) {
+	mockRepo := mocks.NewRepositoryNote(t)
+	api := NewTestNotesAPI(mockRepo)
+
+	r := gin.New()
+	r.POST("/notes", api.createNote)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	var body struct {
+		Fields []validation.FieldError `json:"fields"`
+	}
+	resp, err := resty.New().R().
+		SetBody(`{"title":" ","status":9,"uid":"user1"}`).
+		SetHeader("Content-Type", "application/json").
+		SetError(&body).
+		Post(ts.URL + "/notes")
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode())
+	assert.ElementsMatch(t, []validation.FieldError{
+		{Field: "title", Code: "required", Message: "must not be empty"},
+		{Field: "status", Code: "invalid_value", Message: "has an unsupported value"},
+		{Field: "uid", Code: "not_allowed", Message: "must not be set by the client"},
+	}, body.Fields)
+}
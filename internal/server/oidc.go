@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+	oidcRandomBytes = 32
+)
+
+var errInvalidOIDCState = errors.New("invalid oidc state")
+
+// OIDCProvider — внешний провайдер входа (authorization code flow с PKCE).
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, challenge string) (string, error)
+	Exchange(ctx context.Context, code, verifier string) (string, error)
+	Verify(ctx context.Context, rawIDToken, nonce string) (users.Identity, error)
+}
+
+func WithOIDC(provider OIDCProvider) Option {
+	return func(nApi *NotesAPI) {
+		nApi.oidc = provider
+	}
+}
+
+// oidcState живёт в подписанной cookie между редиректом на провайдера и возвратом,
+// поэтому вход работает на любой реплике.
+type oidcState struct {
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+func (s *NotesAPI) oidcLogin(ctx *gin.Context) {
+	if s.oidc == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": users.ErrOIDCNotConfigured.Error()})
+		return
+	}
+
+	state, err := newOIDCState()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	challenge := sha256.Sum256([]byte(state.Verifier))
+	authURL, err := s.oidc.AuthCodeURL(
+		ctx.Request.Context(),
+		state.State,
+		state.Nonce,
+		base64.RawURLEncoding.EncodeToString(challenge[:]),
+	)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to build oidc auth url")
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "identity provider unavailable"})
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, encodeOIDCState(state), int(oidcStateTTL.Seconds()), "/users/oidc", "", s.secureCookies(), true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+func (s *NotesAPI) oidcCallback(ctx *gin.Context) {
+	if s.oidc == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": users.ErrOIDCNotConfigured.Error()})
+		return
+	}
+	if errParam := ctx.Query("error"); errParam != `` {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": errParam, "description": ctx.Query("error_description")})
+		return
+	}
+
+	cookie, err := ctx.Cookie(oidcStateCookie)
+	ctx.SetCookie(oidcStateCookie, "", -1, "/users/oidc", "", s.secureCookies(), true)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errInvalidOIDCState.Error()})
+		return
+	}
+	state, err := decodeOIDCState(cookie, time.Now())
+	if err != nil || !hmac.Equal([]byte(state.State), []byte(ctx.Query("state"))) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": errInvalidOIDCState.Error()})
+		return
+	}
+
+	rawIDToken, err := s.oidc.Exchange(ctx.Request.Context(), ctx.Query("code"), state.Verifier)
+	if err != nil {
+		s.log.Error().Err(err).Msg("oidc code exchange failed")
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "code exchange failed"})
+		return
+	}
+	identity, err := s.oidc.Verify(ctx.Request.Context(), rawIDToken, state.Nonce)
+	if err != nil {
+		s.log.Error().Err(err).Msg("oidc id token rejected")
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": users.ErrInvalidToken.Error()})
+		return
+	}
+
+	userService := s.userService()
+	userID, err := userService.LoginExternal(identity)
+	if err != nil {
+		var secondFactor *users.SecondFactorError
+		if errors.As(err, &secondFactor) {
+			ctx.JSON(http.StatusAccepted, users.PendingLogin{TwoFactorRequired: true, PendingToken: secondFactor.PendingToken})
+			return
+		}
+		if errors.Is(err, users.ErrUserAlredyExists) || errors.Is(err, users.ErrIdentityNoEmail) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := s.issueToken(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Header("Authorization", token)
+	ctx.String(http.StatusOK, "user logined: %s", userID)
+}
+
+// secureCookies включает флаг Secure, когда сервис доступен по HTTPS (по схеме BaseURL).
+func (s *NotesAPI) secureCookies() bool {
+	if s.cfg == nil {
+		return false
+	}
+	baseURL, err := url.Parse(s.cfg.BaseURL)
+	return err == nil && baseURL.Scheme == "https"
+}
+
+func newOIDCState() (oidcState, error) {
+	values := make([]string, 0, 3) //nolint:mnd // state, nonce, verifier
+	for range 3 {
+		raw := make([]byte, oidcRandomBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return oidcState{}, err
+		}
+		values = append(values, base64.RawURLEncoding.EncodeToString(raw))
+	}
+	return oidcState{
+		State:     values[0],
+		Nonce:     values[1],
+		Verifier:  values[2],
+		ExpiresAt: time.Now().Add(oidcStateTTL).Unix(),
+	}, nil
+}
+
+func encodeOIDCState(state oidcState) string {
+	payload, _ := json.Marshal(state) //nolint:errchkjson // структура из строк и числа
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signOIDCState(encoded))
+}
+
+func decodeOIDCState(cookie string, now time.Time) (oidcState, error) {
+	encoded, signature, ok := strings.Cut(cookie, ".")
+	if !ok {
+		return oidcState{}, errInvalidOIDCState
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, signOIDCState(encoded)) {
+		return oidcState{}, errInvalidOIDCState
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return oidcState{}, errInvalidOIDCState
+	}
+	var state oidcState
+	if err = json.Unmarshal(payload, &state); err != nil || now.Unix() > state.ExpiresAt {
+		return oidcState{}, errInvalidOIDCState
+	}
+	return state, nil
+}
+
+func signOIDCState(payload string) []byte {
+	mac := hmac.New(sha256.New, jwtKey)
+	mac.Write([]byte("oidc-state:" + payload))
+	return mac.Sum(nil)
+}
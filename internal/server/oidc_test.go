@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/oidc"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/oidc/oidctest"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	idp := oidctest.NewServer(t)
+	repo := inmemory.NewUsers()
+	srv := &NotesAPI{repo: repo, cfg: &internal.Config{EncryptionKey: "key", SigningKey: "signing"}, log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.GET("/users/oidc/login", srv.oidcLogin)
+	testRouter.GET("/users/oidc/callback", srv.oidcCallback)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	srv.oidc = oidc.New(internal.OIDCConfig{
+		Issuer:      idp.URL,
+		ClientID:    oidctest.ClientID,
+		RedirectURL: httpTest.URL + "/users/oidc/callback",
+	})
+
+	login := func() string {
+		t.Helper()
+		resp, err := resty.New().R().Get(httpTest.URL + "/users/oidc/login")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode(), resp.String())
+		require.NotEmpty(t, resp.Header().Get("Authorization"))
+		return strings.TrimPrefix(resp.String(), "user logined: ")
+	}
+
+	userID := login()
+	user, err := repo.GetUserID(userID)
+	require.NoError(t, err)
+	assert.Equal(t, idp.Email, user.Email)
+	assert.True(t, user.Verified)
+	assert.Empty(t, user.Password)
+
+	linked, err := repo.GetUserByIdentity(idp.URL, idp.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, userID, linked.UID)
+
+	assert.Equal(t, userID, login(), "second login reuses the provisioned user")
+
+	enrollment, err := srv.userService().EnrollTOTP(userID)
+	require.NoError(t, err)
+	_, err = srv.userService().ConfirmTOTP(userID, totpAt(t, enrollment.Secret, time.Now()))
+	require.NoError(t, err)
+
+	var pending users.PendingLogin
+	resp, err := resty.New().R().SetResult(&pending).Get(httpTest.URL + "/users/oidc/login")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode(), "2fa is not skipped for external logins")
+	assert.Empty(t, resp.Header().Get("Authorization"))
+	assert.True(t, pending.TwoFactorRequired)
+	assert.NotEmpty(t, pending.PendingToken)
+}
+
+func TestOIDCStateCookieSecure(t *testing.T) {
+	idp := oidctest.NewServer(t)
+	for _, tc := range []struct {
+		baseURL string
+		secure  bool
+	}{
+		{baseURL: "https://notes.example.com", secure: true},
+		{baseURL: "http://localhost:8080", secure: false},
+	} {
+		srv := &NotesAPI{
+			cfg:  &internal.Config{BaseURL: tc.baseURL},
+			oidc: oidc.New(internal.OIDCConfig{Issuer: idp.URL, ClientID: oidctest.ClientID}),
+			log:  zerolog.Nop(),
+		}
+		testRouter := gin.New()
+		testRouter.GET("/users/oidc/login", srv.oidcLogin)
+		httpTest := httptest.NewServer(testRouter)
+
+		resp, err := resty.New().SetRedirectPolicy(resty.NoRedirectPolicy()).R().Get(httpTest.URL + "/users/oidc/login")
+		httpTest.Close()
+		require.Error(t, err, "redirect is not followed")
+		require.Equal(t, http.StatusFound, resp.StatusCode())
+		assert.Equal(t, tc.secure, strings.Contains(resp.Header().Get("Set-Cookie"), "Secure"), tc.baseURL)
+	}
+}
+
+func TestOIDCCallbackRejectsForgedState(t *testing.T) {
+	srv := &NotesAPI{repo: inmemory.NewUsers(), oidc: oidc.New(internal.OIDCConfig{}), log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.GET("/users/oidc/callback", srv.oidcCallback)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	resp, err := resty.New().R().
+		SetHeader("Cookie", oidcStateCookie+"=forged.value").
+		SetQueryParams(map[string]string{"code": "c", "state": "s"}).
+		Get(httpTest.URL + "/users/oidc/callback")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+}
@@ -0,0 +1,908 @@
+package server
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/idempotency"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/ratelimit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	"github.com/Snoop-Duck/ToDoList/internal/server/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	specTitle   = "ToDoList API"
+	specVersion = "1.0.0"
+	bearerAuth  = "bearerAuth"
+	contentHTML = "text/html"
+)
+
+// specBuilder добавляет к документу общие для маршрутов ответы и требования авторизации.
+type specBuilder struct {
+	*openapi.Document
+	errorBody      *openapi.Schema
+	validationBody *openapi.Schema
+}
+
+func (b specBuilder) fail(description string) *openapi.Response {
+	return openapi.Content(description, openapi.ContentJSON, b.errorBody)
+}
+
+func (b specBuilder) invalid() *openapi.Response {
+	return openapi.Content("validation failed", openapi.ContentJSON, b.validationBody)
+}
+
+// secured требует JWT или персональный токен; scope — право, которое должен иметь токен.
+func (b specBuilder) secured(op *openapi.Operation, scope users.Scope) *openapi.Operation {
+	op.Security = []openapi.SecurityRequirement{{bearerAuth: {}}}
+	op.Responses["401"] = b.fail("missing or invalid token")
+	if scope == `` {
+		op.Responses["403"] = b.fail("personal access tokens are not accepted")
+		return op
+	}
+	op.Description = cmp.Or(op.Description, "Personal access tokens need the "+string(scope)+" scope.")
+	op.Responses["403"] = b.fail("email is not verified or the token lacks the scope")
+	return op
+}
+
+// idempotent описывает заголовок Idempotency-Key и ответы на повтор с ним.
+func (b specBuilder) idempotent(op *openapi.Operation) *openapi.Operation {
+	keySchema := openapi.String()
+	maxLength := idempotency.MaxKeyLength
+	keySchema.MaxLength = &maxLength
+	op.Parameters = append(op.Parameters, openapi.Parameter{
+		Name: idempotency.Header, In: "header", Schema: keySchema,
+		Description: "a repeated request with the same key gets the stored response",
+	})
+	op.Description = strings.TrimSpace(op.Description + " A repeated request with the same " + idempotency.Header +
+		" and body gets the stored response with " + idempotency.ReplayedHeader + ": true; " +
+		"the same key with a different body is rejected with 422, and repeats of a running request with 409.")
+	if _, ok := op.Responses["409"]; !ok {
+		op.Responses["409"] = b.fail("a request with the same idempotency key is in progress")
+	}
+	if _, ok := op.Responses["422"]; !ok {
+		op.Responses["422"] = b.fail("idempotency key was used with a different body")
+	}
+	return op
+}
+
+// buildSpec описывает все маршруты configRoutes. Схемы тел строятся из DTO,
+// а TestSpecCoversRoutes не даёт добавить маршрут без описания.
+func buildSpec() *openapi.Document {
+	doc := openapi.New(specTitle, specVersion)
+	doc.Components.SecuritySchemes = map[string]*openapi.SecurityScheme{
+		bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT or " + users.AccessTokenPrefix + " access token"},
+	}
+	doc.Enum(notes.Status(0), indexes(notes.Statuses)...)
+	doc.Enum(notes.Priority(0), indexes(notes.Priorities)...)
+	doc.Enum(notes.SortBy(``), notes.SortDefault, notes.SortPriority, notes.SortPosition)
+	doc.Enum(notes.BulkAction(``), notes.BulkCreate, notes.BulkUpdate, notes.BulkDelete, notes.BulkStatus)
+	doc.Enum(notes.BulkMode(``), ``, notes.BulkAtomic, notes.BulkBestEffort)
+	doc.Enum(notes.ImportFormat(``), notes.ImportCSV, notes.ImportJSON, notes.ImportMarkdown)
+	doc.Enum(notes.EventType(``), notes.EventCreated, notes.EventUpdated, notes.EventDeleted, notes.EventStatusChanged)
+	doc.Enum(webhooks.DeliveryStatus(``), webhooks.StatusPending, webhooks.StatusDelivered, webhooks.StatusDead)
+	doc.Enum(users.Scope(``), users.ScopeNotesRead, users.ScopeNotesWrite)
+	doc.Enum(audit.Outcome(``), ``, audit.OutcomeSuccess, audit.OutcomeFailure, audit.OutcomePending)
+
+	b := specBuilder{
+		Document: doc,
+		errorBody: doc.Define("Error", openapi.Object(map[string]*openapi.Schema{
+			"error": openapi.String(),
+		}, "error")),
+		validationBody: doc.Define("ValidationError", openapi.Object(map[string]*openapi.Schema{
+			"error":  openapi.String(),
+			"fields": doc.Schema([]validation.FieldError{}),
+		}, "error", "fields")),
+	}
+	b.addServiceRoutes()
+	b.addUserRoutes()
+	b.addAccountRoutes()
+	b.addNoteRoutes()
+	b.addV1Routes()
+	b.addWebhookRoutes()
+	b.addAdminRoutes()
+	b.addRateLimits()
+	return doc
+}
+
+// addRateLimits добавляет ответ 429 маршрутам групп, которые ограничивает RateLimit.
+func (b specBuilder) addRateLimits() {
+	limited := []string{"/users/", "/notes/", "/boards/", apiV1 + "/", "/webhooks", "/admin/"}
+	for path, item := range b.Paths {
+		if !slices.ContainsFunc(limited, func(prefix string) bool { return strings.HasPrefix(path, prefix) }) {
+			continue
+		}
+		for _, op := range *item {
+			if _, ok := op.Responses["429"]; !ok {
+				op.Responses["429"] = b.fail("rate limit exceeded, see Retry-After and " + ratelimit.LimitHeader + " headers")
+			}
+		}
+	}
+}
+
+// indexes перечисляет коды перечисления, которое хранится как int.
+func indexes(names []string) []any {
+	values := make([]any, len(names))
+	for i := range names {
+		values[i] = i
+	}
+	return values
+}
+
+func (b specBuilder) addServiceRoutes() {
+	b.Add(http.MethodGet, "/", &openapi.Operation{
+		Tags: []string{"service"}, Summary: "Health check", OperationID: "health",
+		Responses: map[string]*openapi.Response{"200": openapi.Empty("service is up")},
+	})
+	b.Add(http.MethodGet, "/openapi.json", &openapi.Operation{
+		Tags: []string{"service"}, Summary: "This OpenAPI document", OperationID: "getSpec",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Content("OpenAPI 3 document", openapi.ContentJSON, &openapi.Schema{Type: openapi.TypeObject}),
+		},
+	})
+	b.Add(http.MethodGet, "/docs", &openapi.Operation{
+		Tags: []string{"service"}, Summary: "Human-readable API documentation", OperationID: "getDocs",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Content("documentation page", contentHTML, openapi.String()),
+		},
+	})
+	b.Add(http.MethodGet, metricsPath, &openapi.Operation{
+		Tags: []string{"service"}, Summary: "Prometheus metrics", OperationID: "getMetrics",
+		Description: "Requires the metrics token from NOTES_METRICS_TOKEN as a bearer token.",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Content("metrics in the Prometheus text format", "text/plain", openapi.String()),
+			"401": b.fail("missing or invalid metrics token"),
+			"503": b.fail("metrics are not configured"),
+		},
+	})
+}
+
+func (b specBuilder) addUserRoutes() {
+	tags := []string{"users"}
+	rawError := openapi.Content("malformed JSON body", openapi.ContentJSON, openapi.String())
+
+	profiles := b.secured(&openapi.Operation{
+		Tags: tags, Summary: "List all users", OperationID: "getUsers", Deprecated: true,
+		Description: "Only for addresses listed in the admin configuration.",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("profiles", []users.Profile{}),
+			"202": b.fail("no users"),
+		},
+	}, ``)
+	profiles.Responses["403"] = b.fail("not an administrator")
+	b.Add(http.MethodGet, "/users/profile", profiles)
+	b.Add(http.MethodGet, "/users/profile/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Get a user", OperationID: "getUserID", Deprecated: true,
+		Description: "Only the owner of the account can read it.",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("profile", users.Profile{}),
+			"400": b.fail("user not found"),
+		},
+	}, ``))
+	b.Add(http.MethodPost, "/users/register", b.idempotent(&openapi.Operation{
+		Tags: tags, Summary: "Register a user", OperationID: "register",
+		Description: "The JWT is returned in the Authorization response header. " +
+			"A replayed response carries no token, log in instead.",
+		RequestBody: b.JSONBody(users.User{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("user registered"),
+			"400": rawError,
+			"409": b.fail("email is taken"),
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodPost, "/users/login", &openapi.Operation{
+		Tags: tags, Summary: "Log in with email and password", OperationID: "login",
+		Description: "The JWT is returned in the Authorization response header.",
+		RequestBody: b.JSONBody(users.UserRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("logged in"),
+			"202": b.JSON("second factor required", users.PendingLogin{}),
+			"400": rawError,
+			"401": b.fail("invalid credentials"),
+			"422": b.invalid(),
+			"429": b.fail("too many failed attempts, see Retry-After"),
+			"500": b.fail("internal error"),
+			"503": b.fail("2fa is enabled for the user but not configured on the server"),
+		},
+	})
+	b.Add(http.MethodPost, "/users/login/2fa", &openapi.Operation{
+		Tags: tags, Summary: "Complete a login with a TOTP or recovery code", OperationID: "loginSecondFactor",
+		RequestBody: b.JSONBody(users.SecondFactorRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("logged in"),
+			"400": b.fail("malformed JSON body"),
+			"401": b.fail("invalid code or pending token"),
+			"429": b.fail("too many failed attempts, see Retry-After"),
+			"500": b.fail("internal error"),
+		},
+	})
+	b.Add(http.MethodGet, "/users/oidc/login", &openapi.Operation{
+		Tags: tags, Summary: "Start an OpenID Connect login", OperationID: "oidcLogin",
+		Responses: map[string]*openapi.Response{
+			"302": openapi.Empty("redirect to the identity provider"),
+			"404": b.fail("OIDC is not configured"),
+			"500": b.fail("internal error"),
+			"502": b.fail("identity provider unavailable"),
+		},
+	})
+	b.Add(http.MethodGet, "/users/oidc/callback", &openapi.Operation{
+		Tags: tags, Summary: "Finish an OpenID Connect login", OperationID: "oidcCallback",
+		Parameters: []openapi.Parameter{
+			openapi.Query("code", "authorization code", openapi.String()),
+			openapi.Query("state", "state issued by /users/oidc/login", openapi.String()),
+			openapi.Query("error", "error reported by the provider", openapi.String()),
+			openapi.Query("error_description", ``, openapi.String()),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("logged in"),
+			"202": b.JSON("second factor required", users.PendingLogin{}),
+			"400": b.fail("invalid state"),
+			"401": b.fail("login rejected"),
+			"404": b.fail("OIDC is not configured"),
+			"409": b.fail("email belongs to another account"),
+			"500": b.fail("internal error"),
+		},
+	})
+	b.Add(http.MethodPut, "/users/upd/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Update a user", OperationID: "updateUserID", Deprecated: true,
+		Description: "Only the owner of the account can update it.",
+		RequestBody: b.JSONBody(users.User{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("user updated"),
+			"400": b.fail("malformed body or user not found"),
+			"422": b.invalid(),
+		},
+	}, ``))
+	b.Add(http.MethodDelete, "/users/del/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Delete a user", OperationID: "deleteUser", Deprecated: true,
+		Description: "Only the owner of the account can delete it.",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("user deleted"),
+			"400": b.fail("user not found"),
+		},
+	}, ``))
+	b.Add(http.MethodGet, "/users/verify", &openapi.Operation{
+		Tags: tags, Summary: "Confirm an email address", OperationID: "verifyEmail",
+		Parameters: []openapi.Parameter{
+			{Name: "token", In: "query", Description: "token from the verification email", Required: true, Schema: openapi.String()},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("email verified"),
+			"400": b.fail("invalid or expired token"),
+			"500": b.fail("internal error"),
+		},
+	})
+	b.Add(http.MethodPost, "/users/verify/resend", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Send the verification email again", OperationID: "resendVerification",
+		Responses: map[string]*openapi.Response{
+			"202": openapi.Text("verification email sent"),
+			"404": b.fail("user not found"),
+			"409": b.fail("email is already verified"),
+			"500": b.fail("internal error"),
+			"503": b.fail("email verification is not configured"),
+		},
+	}, ``))
+	b.Add(http.MethodPost, "/users/password/forgot", &openapi.Operation{
+		Tags: tags, Summary: "Request a password reset link", OperationID: "forgotPassword",
+		RequestBody: b.JSONBody(users.ForgotPasswordRequest{}),
+		Responses: map[string]*openapi.Response{
+			"202": openapi.Text("reset link sent if the account exists"),
+			"400": b.fail("malformed JSON body"),
+		},
+	})
+	b.Add(http.MethodPost, "/users/password/reset", &openapi.Operation{
+		Tags: tags, Summary: "Set a new password with a reset token", OperationID: "resetPassword",
+		RequestBody: b.JSONBody(users.ResetPasswordRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("password updated"),
+			"400": b.fail("invalid token or empty password"),
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	})
+
+	twoFactorErrors := func(op *openapi.Operation) *openapi.Operation {
+		op.Responses["404"] = b.fail("user not found")
+		op.Responses["409"] = b.fail("2FA is in the wrong state for this action")
+		op.Responses["500"] = b.fail("internal error")
+		return b.secured(op, ``)
+	}
+	b.Add(http.MethodPost, "/users/2fa/enroll", twoFactorErrors(&openapi.Operation{
+		Tags: tags, Summary: "Start TOTP enrollment", OperationID: "enrollTOTP",
+		Responses: map[string]*openapi.Response{"200": b.JSON("TOTP secret", users.TOTPEnrollment{})},
+	}))
+	b.Add(http.MethodPost, "/users/2fa/confirm", twoFactorErrors(&openapi.Operation{
+		Tags: tags, Summary: "Enable TOTP with the first code", OperationID: "confirmTOTP",
+		RequestBody: b.JSONBody(users.TOTPCodeRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("recovery codes, shown once", users.RecoveryCodes{}),
+			"400": b.fail("malformed JSON body"),
+		},
+	}))
+	b.Add(http.MethodPost, "/users/2fa/disable", twoFactorErrors(&openapi.Operation{
+		Tags: tags, Summary: "Disable TOTP", OperationID: "disableTOTP",
+		RequestBody: b.JSONBody(users.TOTPCodeRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("2fa disabled"),
+			"400": b.fail("malformed JSON body"),
+		},
+	}))
+
+	b.Add(http.MethodGet, "/users/tokens", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "List personal access tokens", OperationID: "listAccessTokens",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("tokens", []users.AccessToken{}),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodPost, "/users/tokens", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Create a personal access token", OperationID: "createAccessToken",
+		RequestBody: b.JSONBody(users.AccessTokenRequest{}),
+		Responses: map[string]*openapi.Response{
+			"201": b.JSON("token; the secret is shown once", users.CreatedAccessToken{}),
+			"400": b.fail("invalid name, scope or expiry"),
+			"404": b.fail("user not found"),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodDelete, "/users/tokens/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Revoke a personal access token", OperationID: "revokeAccessToken",
+		Responses: map[string]*openapi.Response{
+			"204": openapi.Empty("token revoked"),
+			"404": b.fail("token not found"),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodGet, "/users/sessions", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "List active sessions", OperationID: "listSessions",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("sessions", []users.Session{}),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodDelete, "/users/sessions/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Revoke a session", OperationID: "revokeSession",
+		Responses: map[string]*openapi.Response{
+			"204": openapi.Empty("session revoked"),
+			"404": b.fail("session not found"),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+}
+
+// addAccountRoutes описывает маршруты /users/me, часть которых повторяется в /api/v1.
+func (b specBuilder) addAccountRoutes() {
+	tags := []string{"users"}
+	deletion := func(operationID string) *openapi.Operation {
+		return b.secured(&openapi.Operation{
+			Tags: tags, Summary: "Schedule account deletion", OperationID: operationID,
+			Description: "The account is removed after the grace period unless the deletion is cancelled.",
+			Responses: map[string]*openapi.Response{
+				"202": b.JSON("deletion scheduled", users.DeletionRequest{}),
+				"404": b.fail("user not found"),
+				"500": b.fail("internal error"),
+			},
+		}, ``)
+	}
+	getPreferences := func(operationID string) *openapi.Operation {
+		return b.secured(&openapi.Operation{
+			Tags: tags, Summary: "Get preferences", OperationID: operationID,
+			Responses: map[string]*openapi.Response{
+				"200": b.JSON("preferences", users.Preferences{}),
+				"500": b.fail("internal error"),
+			},
+		}, ``)
+	}
+	updatePreferences := func(operationID string) *openapi.Operation {
+		return b.secured(&openapi.Operation{
+			Tags: tags, Summary: "Change preferences", OperationID: operationID,
+			RequestBody: b.JSONBody(users.PreferencesPatch{}),
+			Responses: map[string]*openapi.Response{
+				"200": b.JSON("preferences", users.Preferences{}),
+				"400": b.fail("malformed JSON body"),
+				"404": b.fail("user not found"),
+				"422": b.invalid(),
+				"500": b.fail("internal error"),
+			},
+		}, ``)
+	}
+
+	b.Add(http.MethodGet, "/users/me/export", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Export all account data", OperationID: "exportUser",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("account archive", users.Export{}),
+			"404": b.fail("user not found"),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodDelete, "/users/me", deletion("requestDeletion"))
+	b.Add(http.MethodPost, "/users/me/restore", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Cancel a scheduled account deletion", OperationID: "cancelDeletion",
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("account deletion cancelled"),
+			"404": b.fail("user not found"),
+			"409": b.fail("no deletion is scheduled"),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodGet, "/users/me/preferences", getPreferences("getPreferences"))
+	b.Add(http.MethodPatch, "/users/me/preferences", updatePreferences("updatePreferences"))
+
+	b.Add(http.MethodGet, apiV1+"/users/me", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Get the current user's profile", OperationID: "getMe",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("profile", users.Profile{}),
+			"404": b.fail("user not found"),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodPatch, apiV1+"/users/me", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Change the current user's profile", OperationID: "updateMe",
+		RequestBody: b.JSONBody(users.ProfilePatch{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("profile", users.Profile{}),
+			"400": b.fail("malformed JSON body"),
+			"404": b.fail("user not found"),
+			"409": b.fail("email is taken"),
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	}, ``))
+	b.Add(http.MethodDelete, apiV1+"/users/me", deletion("requestDeletionV1"))
+	b.Add(http.MethodGet, apiV1+"/users/me/preferences", getPreferences("getPreferencesV1"))
+	b.Add(http.MethodPatch, apiV1+"/users/me/preferences", updatePreferences("updatePreferencesV1"))
+}
+
+func (b specBuilder) addNoteRoutes() {
+	tags := []string{"notes"}
+	sortParam := openapi.Query("sort", "defaults to the default_sort preference", b.Schema(notes.SortBy(``)))
+
+	b.Add(http.MethodGet, "/notes/list", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "List notes", OperationID: "getNotes", Deprecated: true,
+		Parameters: []openapi.Parameter{sortParam},
+		Responses: map[string]*openapi.Response{
+			"202": openapi.Text("notes"),
+			"204": openapi.Empty("no notes"),
+			"400": b.fail("unknown sort order"),
+		},
+	}, users.ScopeNotesRead))
+	b.Add(http.MethodGet, "/notes/list/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Get a note", OperationID: "getNoteID", Deprecated: true,
+		Responses: map[string]*openapi.Response{
+			"202": openapi.Text("note"),
+			"204": openapi.Empty("note not found"),
+		},
+	}, users.ScopeNotesRead))
+	b.Add(http.MethodPost, "/notes/add", b.idempotent(b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Create a note", OperationID: "createNote", Deprecated: true,
+		RequestBody: b.JSONBody(notes.Note{}),
+		Responses: map[string]*openapi.Response{
+			"201": openapi.Text("note created"),
+			"400": b.fail("malformed JSON body"),
+			"409": b.fail("note already exists"),
+			"422": b.invalid(),
+		},
+	}, users.ScopeNotesWrite)))
+	b.Add(http.MethodPut, "/notes/upd/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Replace a note", OperationID: "updateNote", Deprecated: true,
+		RequestBody: b.JSONBody(notes.Note{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("note updated"),
+			"400": b.fail("malformed body or note not found"),
+			"422": b.invalid(),
+		},
+	}, users.ScopeNotesWrite))
+	b.Add(http.MethodDelete, "/notes/del/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Delete a note", OperationID: "deleteNote", Deprecated: true,
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("note deleted"),
+			"400": b.fail("note not found"),
+		},
+	}, users.ScopeNotesWrite))
+	b.Add(http.MethodPost, "/notes/:id/move", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Move a note between neighbours", OperationID: "moveNote",
+		RequestBody: b.JSONBody(notes.MoveRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("note moved"),
+			"400": b.fail("invalid neighbours"),
+			"404": b.fail("note not found"),
+		},
+	}, users.ScopeNotesWrite))
+	b.Add(http.MethodPost, "/notes/bulk", b.idempotent(b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Apply a batch of note operations", OperationID: "bulkNotes",
+		RequestBody: b.JSONBody(notes.BulkRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("all operations applied", notes.BulkResponse{}),
+			"207": b.JSON("some operations failed", notes.BulkResponse{}),
+			"400": b.fail("invalid batch"),
+			"409": b.JSON("atomic batch rolled back", notes.BulkResponse{}),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesWrite)))
+	importBody := &openapi.Schema{Type: openapi.TypeString, Format: "binary"}
+	b.Add(http.MethodPost, "/notes/import", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Import notes from a file", OperationID: "importNotes",
+		Parameters: []openapi.Parameter{
+			{Name: "format", In: "query", Required: true, Schema: b.Schema(notes.ImportFormat(``))},
+			openapi.Query("map", "CSV column mapping, e.g. title:Name,status:State", openapi.String()),
+			openapi.Query("dry_run", "report what would be imported without saving", openapi.Boolean()),
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]*openapi.MediaType{
+			"text/csv":      {Schema: importBody},
+			"text/markdown": {Schema: importBody},
+			// Формат JSON совпадает с выгрузкой GET /notes/export?format=json.
+			openapi.ContentJSON: {Schema: &openapi.Schema{Description: "document produced by the JSON export"}},
+		}},
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("dry run report or nothing imported", notes.ImportReport{}),
+			"201": b.JSON("notes imported", notes.ImportReport{}),
+			"400": b.fail("invalid parameters or file"),
+			"413": b.fail("file is larger than 10 MiB"),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesWrite))
+	exportBody := &openapi.Schema{Type: openapi.TypeString, Format: "binary"}
+	exportContent := make(map[string]*openapi.MediaType, len(exportContentTypes))
+	for _, contentType := range exportContentTypes {
+		exportContent[contentType] = &openapi.MediaType{Schema: exportBody}
+	}
+	exportContent[exportContentTypes[notes.ExportJSON]] = &openapi.MediaType{Schema: b.Schema(notes.ExportDocument{})}
+	b.Add(http.MethodGet, "/notes/export", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Export notes", OperationID: "exportNotes",
+		Parameters: []openapi.Parameter{{
+			Name: "format", In: "query", Required: true,
+			Schema: openapi.String().WithEnum(notes.ExportCSV, notes.ExportMarkdown, notes.ExportICS, notes.ExportJSON),
+		}},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "notes file", Content: exportContent},
+			"400": b.fail("unknown format"),
+		},
+	}, users.ScopeNotesRead))
+
+	b.Add(http.MethodGet, streamPath, b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Stream note changes", OperationID: "streamNotes",
+		Description: "Server-sent events, or WebSocket messages when the connection is upgraded. " +
+			"Event names are the event types plus " + streamReset + " (reload the notes, missed events are gone) and " +
+			streamOverflow + " (the client fell behind and is disconnected; reconnect with Last-Event-ID). " +
+			"Personal access tokens need the " + string(users.ScopeNotesRead) + " scope. " +
+			"Browsers, which cannot set the Authorization header here, pass a ticket instead.",
+		Parameters: []openapi.Parameter{
+			{Name: "Last-Event-ID", In: "header", Description: "resume after this event", Schema: openapi.Integer()},
+			openapi.Query("last_event_id", "same as the Last-Event-ID header", openapi.Integer()),
+			openapi.Query("ticket", "ticket from POST "+streamPath+"/ticket, instead of the header", openapi.String()),
+		},
+		Responses: map[string]*openapi.Response{
+			"101": openapi.Empty("switched to WebSocket, each message is an event payload"),
+			"200": openapi.Content("event stream", "text/event-stream", b.Schema(notes.EventPayload{})),
+			"400": b.fail("invalid Last-Event-ID"),
+			"503": b.fail("event stream is not configured"),
+		},
+	}, users.ScopeNotesRead))
+
+	b.Add(http.MethodPost, streamPath+"/ticket", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Issue a ticket for the note stream", OperationID: "issueStreamTicket",
+		Description: "The ticket authenticates GET " + streamPath + " for one minute, reconnects included. " +
+			"It stops working when the session it was issued for ends.",
+		Responses: map[string]*openapi.Response{
+			"201": b.JSON("ticket", users.StreamTicket{}),
+			"503": b.fail("stream tickets are not configured"),
+		},
+	}, users.ScopeNotesRead))
+
+	b.Add(http.MethodGet, "/boards/:board", b.secured(&openapi.Operation{
+		Tags: []string{"boards"}, Summary: "Get notes grouped by status", OperationID: "getBoard",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("board", notes.Board{}),
+			"404": b.fail("board not found"),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesRead))
+	b.Add(http.MethodPost, "/boards/:board/notes/:id/move", b.secured(&openapi.Operation{
+		Tags: []string{"boards"}, Summary: "Move a note to a column", OperationID: "moveOnBoard",
+		RequestBody: b.JSONBody(notes.BoardMoveRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Content("note moved", openapi.ContentJSON, openapi.Object(map[string]*openapi.Schema{
+				"nid":      openapi.String(),
+				"status":   openapi.String(),
+				"position": openapi.String(),
+			}, "nid", "status", "position")),
+			"400": b.fail("malformed body or invalid move"),
+			"404": b.fail("board or note not found"),
+		},
+	}, users.ScopeNotesWrite))
+}
+
+func (b specBuilder) addV1Routes() {
+	tags := []string{"notes"}
+	noteNotFound := b.fail("note not found")
+
+	b.Add(http.MethodGet, apiV1+"/notes", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "List my notes", OperationID: "listMyNotes",
+		Parameters: []openapi.Parameter{
+			openapi.Query("sort", "defaults to the default_sort preference", b.Schema(notes.SortBy(``))),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("notes", []notes.NoteResponseFormat{}),
+			"400": b.fail("unknown sort order"),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesRead))
+	b.Add(http.MethodPost, apiV1+"/notes", b.idempotent(b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Create a note", OperationID: "createMyNote",
+		Description: "Status defaults to the default_status preference.",
+		RequestBody: b.JSONBody(notes.NotePatch{}),
+		Responses: map[string]*openapi.Response{
+			"201": b.JSON("created note", notes.NoteResponseFormat{}),
+			"400": b.fail("malformed JSON body"),
+			"409": b.fail("note already exists"),
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesWrite)))
+	b.Add(http.MethodGet, apiV1+"/notes/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Get my note", OperationID: "getMyNote",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("note", notes.NoteResponseFormat{}),
+			"404": noteNotFound,
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesRead))
+	b.Add(http.MethodPut, apiV1+"/notes/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Replace my note", OperationID: "replaceMyNote",
+		RequestBody: b.JSONBody(notes.Note{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("updated note", notes.NoteResponseFormat{}),
+			"400": b.fail("malformed JSON body"),
+			"404": noteNotFound,
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesWrite))
+	b.Add(http.MethodPatch, apiV1+"/notes/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Change fields of my note", OperationID: "patchMyNote",
+		RequestBody: b.JSONBody(notes.NotePatch{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("updated note", notes.NoteResponseFormat{}),
+			"400": b.fail("malformed JSON body"),
+			"404": noteNotFound,
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesWrite))
+	b.Add(http.MethodDelete, apiV1+"/notes/:id", b.secured(&openapi.Operation{
+		Tags: tags, Summary: "Delete my note", OperationID: "deleteMyNote",
+		Responses: map[string]*openapi.Response{
+			"204": openapi.Empty("note deleted"),
+			"404": noteNotFound,
+			"500": b.fail("internal error"),
+		},
+	}, users.ScopeNotesWrite))
+}
+
+func (b specBuilder) addWebhookRoutes() {
+	tags := []string{"webhooks"}
+	hook := func(op *openapi.Operation) *openapi.Operation {
+		op = b.secured(op, ``)
+		op.Responses["503"] = b.fail("webhooks are not configured")
+		return op
+	}
+	webhookNotFound := b.fail("webhook not found")
+
+	b.Add(http.MethodPost, "/webhooks", hook(&openapi.Operation{
+		Tags: tags, Summary: "Subscribe to note changes", OperationID: "createWebhook",
+		Description: "Deliveries are POSTed as JSON and signed: " + webhooks.SignatureHeader + " is " +
+			webhooks.SignaturePrefix + "HMAC-SHA256(secret, " + webhooks.TimestampHeader + " + \".\" + body) in hex. " +
+			"Failed deliveries are retried with exponential backoff and end up in the dead-letter list.",
+		RequestBody: b.JSONBody(webhooks.WebhookRequest{}),
+		Responses: map[string]*openapi.Response{
+			"201": b.JSON("webhook; the secret is shown once", webhooks.CreatedWebhook{}),
+			"400": b.fail("malformed JSON body"),
+			"409": b.fail("too many webhooks"),
+			"422": b.invalid(),
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodGet, "/webhooks", hook(&openapi.Operation{
+		Tags: tags, Summary: "List my webhooks", OperationID: "listWebhooks",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("webhooks", []webhooks.Webhook{}),
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodGet, "/webhooks/:id", hook(&openapi.Operation{
+		Tags: tags, Summary: "Get a webhook", OperationID: "getWebhook",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("webhook", webhooks.Webhook{}),
+			"404": webhookNotFound,
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodDelete, "/webhooks/:id", hook(&openapi.Operation{
+		Tags: tags, Summary: "Delete a webhook and its deliveries", OperationID: "deleteWebhook",
+		Responses: map[string]*openapi.Response{
+			"204": openapi.Empty("webhook deleted"),
+			"404": webhookNotFound,
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodGet, "/webhooks/:id/deliveries", hook(&openapi.Operation{
+		Tags: tags, Summary: "Delivery history of a webhook", OperationID: "listDeliveries",
+		Parameters: b.QueryParams(webhooks.DeliveryFilter{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("deliveries, newest first", []webhooks.Delivery{}),
+			"400": b.fail("invalid filter"),
+			"404": webhookNotFound,
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodGet, "/webhooks/dead-letters", hook(&openapi.Operation{
+		Tags: tags, Summary: "Deliveries that ran out of attempts", OperationID: "listDeadLetters",
+		Parameters: []openapi.Parameter{openapi.Query("limit", ``, openapi.Integer())},
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("dead deliveries, newest first", []webhooks.Delivery{}),
+			"400": b.fail("invalid filter"),
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodPost, "/webhooks/dead-letters/:id/retry", hook(&openapi.Operation{
+		Tags: tags, Summary: "Queue a dead delivery again", OperationID: "retryDelivery",
+		Responses: map[string]*openapi.Response{
+			"202": b.JSON("delivery queued", webhooks.Delivery{}),
+			"404": b.fail("delivery not found"),
+			"409": b.fail("delivery is not dead"),
+			"500": b.fail("internal error"),
+		},
+	}))
+}
+
+func (b specBuilder) addAdminRoutes() {
+	tags := []string{"admin"}
+	admin := func(op *openapi.Operation) *openapi.Operation {
+		op = b.secured(op, ``)
+		op.Description = "Only for addresses listed in the admin configuration."
+		op.Responses["403"] = b.fail("not an administrator")
+		return op
+	}
+
+	b.Add(http.MethodPost, "/admin/users/unlock", admin(&openapi.Operation{
+		Tags: tags, Summary: "Reset failed login attempts", OperationID: "unlockUser",
+		RequestBody: b.JSONBody(users.UnlockRequest{}),
+		Responses: map[string]*openapi.Response{
+			"200": openapi.Text("unlocked"),
+			"400": b.fail("email or ip is required"),
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodGet, "/admin/audit", admin(&openapi.Operation{
+		Tags: tags, Summary: "Search the audit log", OperationID: "listAudit",
+		Parameters: b.QueryParams(audit.Filter{}),
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("events, newest first", []audit.Event{}),
+			"400": b.fail("invalid filter"),
+			"404": b.fail("audit log is not configured"),
+			"500": b.fail("internal error"),
+		},
+	}))
+	b.Add(http.MethodGet, "/admin/audit/verify", admin(&openapi.Operation{
+		Tags: tags, Summary: "Verify the audit log hash chain", OperationID: "verifyAudit",
+		Responses: map[string]*openapi.Response{
+			"200": b.JSON("chain is intact", audit.Report{}),
+			"404": b.fail("audit log is not configured"),
+			"409": b.JSON("chain is broken", audit.Report{}),
+			"500": b.fail("internal error"),
+		},
+	}))
+}
+
+func (nApi *NotesAPI) getSpec(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, nApi.spec)
+}
+
+func (nApi *NotesAPI) getDocs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, contentHTML+"; charset=utf-8", openapi.DocsPage)
+}
+
+// ValidateRequests отклоняет запросы, параметры или JSON-тело которых не соответствуют спецификации,
+// с тем же ответом 422, что и проверка DTO. Маршруты без описания пропускаются: их находит
+// TestSpecCoversRoutes, а не пользователи.
+func (nApi *NotesAPI) ValidateRequests() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		op, ok := nApi.spec.Operation(ctx.Request.Method, ctx.FullPath())
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		errs := nApi.spec.ValidateParams(op, ctx.Request.URL.Query())
+		if schema := jsonBodySchema(op, ctx.ContentType()); schema != nil {
+			body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+					return
+				}
+				ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+			errs = append(errs, nApi.spec.ValidateJSON(schema, body)...)
+		}
+		if len(errs) > 0 {
+			abortValidation(ctx, errs)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// routes регистрирует маршруты группы и ставит проверку запроса прямо перед обработчиком,
+// чтобы анонимный или лишённый прав клиент получал 401/403, а не разбор своего тела.
+type routes struct {
+	*gin.RouterGroup
+	validate gin.HandlerFunc
+}
+
+func (nApi *NotesAPI) routes(group *gin.RouterGroup) routes {
+	r := routes{RouterGroup: group}
+	if nApi.cfg != nil && nApi.cfg.ValidateRequests {
+		r.validate = nApi.ValidateRequests()
+	}
+	return r
+}
+
+func (r routes) Group(path string, handlers ...gin.HandlerFunc) routes {
+	return routes{RouterGroup: r.RouterGroup.Group(path, handlers...), validate: r.validate}
+}
+
+func (r routes) GET(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodGet, path, handlers)
+}
+
+func (r routes) POST(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPost, path, handlers)
+}
+
+func (r routes) PUT(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPut, path, handlers)
+}
+
+func (r routes) PATCH(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodPatch, path, handlers)
+}
+
+func (r routes) DELETE(path string, handlers ...gin.HandlerFunc) {
+	r.handle(http.MethodDelete, path, handlers)
+}
+
+func (r routes) handle(method, path string, handlers []gin.HandlerFunc) {
+	if r.validate != nil && len(handlers) > 0 {
+		last := len(handlers) - 1
+		handlers = append(slices.Clip(handlers[:last]), r.validate, handlers[last])
+	}
+	r.Handle(method, path, handlers...)
+}
+
+// jsonBodySchema возвращает схему JSON-тела операции; тело без Content-Type считается JSON.
+func jsonBodySchema(op *openapi.Operation, contentType string) *openapi.Schema {
+	if op.RequestBody == nil || cmp.Or(contentType, openapi.ContentJSON) != openapi.ContentJSON {
+		return nil
+	}
+	media, ok := op.RequestBody.Content[openapi.ContentJSON]
+	if !ok {
+		return nil
+	}
+	return media.Schema
+}
@@ -0,0 +1,8 @@
+package openapi
+
+import _ "embed"
+
+// DocsPage — страница документации, которая читает спецификацию с /openapi.json.
+//
+//go:embed docs.html
+var DocsPage []byte //nolint:gochecknoglobals // its ok
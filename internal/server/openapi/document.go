@@ -0,0 +1,173 @@
+// Package openapi описывает API документом OpenAPI 3 и проверяет запросы по нему.
+// Схемы тел строятся из DTO по тегам json и validate, поэтому не расходятся с кодом.
+package openapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	enums map[reflect.Type][]any
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// SecurityRequirement — имя схемы безопасности и требуемые права.
+type SecurityRequirement map[string][]string
+
+// PathItem хранит операции пути по HTTP-методу в нижнем регистре.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+func New(title, version string) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+}
+
+// Path переводит шаблон маршрута gin (/notes/:id) в шаблон OpenAPI (/notes/{id}).
+func Path(ginPath string) string {
+	parts := strings.Split(ginPath, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Add регистрирует операцию для маршрута gin; параметры пути добавляются автоматически.
+func (d *Document) Add(method, ginPath string, op *Operation) {
+	var params []Parameter
+	for _, part := range strings.Split(ginPath, "/") {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(params, Parameter{Name: part[1:], In: "path", Required: true, Schema: String()})
+		}
+	}
+	op.Parameters = append(params, op.Parameters...)
+	path := Path(ginPath)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// Operation находит операцию по методу и шаблону маршрута gin.
+func (d *Document) Operation(method, ginPath string) (*Operation, bool) {
+	item, ok := d.Paths[Path(ginPath)]
+	if !ok {
+		return nil, false
+	}
+	op, ok := (*item)[strings.ToLower(method)]
+	return op, ok
+}
+
+// Define добавляет схему в components под именем name и возвращает ссылку на неё.
+func (d *Document) Define(name string, schema *Schema) *Schema {
+	d.Components.Schemas[name] = schema
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// Resolve раскрывает ссылку на схему из components.
+func (d *Document) Resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != `` {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+	}
+	return schema
+}
+
+const (
+	ContentJSON = "application/json"
+	ContentText = "text/plain"
+)
+
+// JSONBody — обязательное тело application/json со схемой значения v.
+func (d *Document) JSONBody(v any) *RequestBody {
+	return Body(ContentJSON, d.Schema(v))
+}
+
+func Body(contentType string, schema *Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: map[string]*MediaType{contentType: {Schema: schema}}}
+}
+
+// JSON — ответ application/json со схемой значения v.
+func (d *Document) JSON(description string, v any) *Response {
+	return Content(description, ContentJSON, d.Schema(v))
+}
+
+func Content(description, contentType string, schema *Schema) *Response {
+	return &Response{Description: description, Content: map[string]*MediaType{contentType: {Schema: schema}}}
+}
+
+// Text — ответ text/plain: так отвечают маршруты, появившиеся до /api/v1.
+func Text(description string) *Response {
+	return Content(description, ContentText, String())
+}
+
+func Empty(description string) *Response {
+	return &Response{Description: description}
+}
+
+func Query(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
@@ -0,0 +1,150 @@
+package openapi
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type level int
+
+type item struct {
+	ID      string    `json:"id"      validate:"isdefault"`
+	Name    string    `json:"name"    validate:"notblank,max=5"`
+	Email   string    `json:"email"   validate:"omitempty,email"`
+	Sort    string    `json:"sort"    validate:"omitempty,oneof=asc desc"`
+	Level   level     `json:"level"`
+	Note    *string   `json:"note"`
+	Tags    []string  `json:"tags"`
+	Child   *item     `json:"child"`
+	Due     time.Time `json:"due"`
+	Secret  string    `json:"-"`
+	private string
+}
+
+func newTestDocument() *Document {
+	doc := New("test", "1")
+	doc.Enum(level(0), 0, 1, 2)
+	return doc
+}
+
+func TestSchema(t *testing.T) {
+	doc := newTestDocument()
+
+	ref := doc.Schema(item{})
+	assert.Equal(t, "#/components/schemas/openapi.item", ref.Ref)
+
+	schema := doc.Resolve(ref)
+	require.NotNil(t, schema)
+	assert.Equal(t, []string{"name"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "Secret")
+	assert.NotContains(t, schema.Properties, "private")
+	assert.True(t, schema.Properties["id"].ReadOnly)
+	assert.Equal(t, 1, *schema.Properties["name"].MinLength)
+	assert.Equal(t, 5, *schema.Properties["name"].MaxLength)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, []any{``, "asc", "desc"}, schema.Properties["sort"].Enum)
+	assert.Equal(t, []any{0, 1, 2}, schema.Properties["level"].Enum)
+	assert.True(t, schema.Properties["note"].Nullable)
+	assert.Equal(t, TypeArray, schema.Properties["tags"].Type)
+	assert.Equal(t, ref.Ref, schema.Properties["child"].Ref)
+	assert.Equal(t, "date-time", schema.Properties["due"].Format)
+}
+
+func TestPathAndOperation(t *testing.T) {
+	doc := newTestDocument()
+	doc.Add("GET", "/boards/:board/notes/:id", &Operation{Summary: "get"})
+
+	assert.Equal(t, "/boards/{board}/notes/{id}", Path("/boards/:board/notes/:id"))
+	op, ok := doc.Operation("get", "/boards/:board/notes/:id")
+	require.True(t, ok)
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "board", op.Parameters[0].Name)
+	assert.Equal(t, "id", op.Parameters[1].Name)
+	assert.True(t, op.Parameters[1].Required)
+
+	_, ok = doc.Operation("POST", "/boards/:board/notes/:id")
+	assert.False(t, ok)
+}
+
+func TestValidateJSON(t *testing.T) {
+	doc := newTestDocument()
+	schema := doc.Schema(item{})
+
+	tests := []struct {
+		name string
+		body string
+		want validation.Errors
+	}{
+		{
+			name: "valid",
+			body: `{"name":"milk","email":"a@b.c","level":2,"note":null,"tags":["x"],"child":{"name":"egg"},"due":"2026-01-02T03:04:05Z"}`,
+		},
+		{
+			name: "missing required",
+			body: `{}`,
+			want: validation.Errors{{Field: "name", Code: "required", Message: "must not be empty"}},
+		},
+		{
+			name: "read-only field",
+			body: `{"name":"milk","id":"x"}`,
+			want: validation.Errors{{Field: "id", Code: "not_allowed", Message: "must not be set by the client"}},
+		},
+		{
+			name: "too long and bad email",
+			body: `{"name":"oatmeal","email":"nope"}`,
+			want: validation.Errors{
+				{Field: "email", Code: "email", Message: "must be a valid email address"},
+				{Field: "name", Code: "too_long", Message: "must be at most 5 characters"},
+			},
+		},
+		{
+			name: "nested errors",
+			body: `{"name":"milk","level":7,"tags":[1],"child":{"name":""},"due":"tomorrow"}`,
+			want: validation.Errors{
+				{Field: "child.name", Code: "required", Message: "must not be empty"},
+				{Field: "due", Code: "invalid_value", Message: "must be an RFC 3339 date-time"},
+				{Field: "level", Code: "invalid_value", Message: "has an unsupported value"},
+				{Field: "tags[0]", Code: "invalid_type", Message: "must be of type string"},
+			},
+		},
+		{
+			name: "not an object",
+			body: `[]`,
+			want: validation.Errors{{Code: "invalid_type", Message: "must be of type object"}},
+		},
+		{
+			name: "null for a non-nullable field",
+			body: `{"name":null}`,
+			want: validation.Errors{{Field: "name", Code: "invalid_type", Message: "must be of type string"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, doc.ValidateJSON(schema, []byte(tc.body)))
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	doc := newTestDocument()
+	op := &Operation{Parameters: []Parameter{
+		{Name: "format", In: "query", Required: true, Schema: String().WithEnum("csv", "json")},
+		Query("limit", ``, Integer()),
+		Query("dry_run", ``, Boolean()),
+	}}
+
+	assert.Empty(t, doc.ValidateParams(op, url.Values{"format": {"csv"}, "limit": {"10"}, "dry_run": {"true"}}))
+	assert.Equal(t, validation.Errors{
+		{Field: "format", Code: "required", Message: "must not be empty"},
+		{Field: "limit", Code: "invalid_type", Message: "must be of type integer"},
+		{Field: "dry_run", Code: "invalid_type", Message: "must be of type boolean"},
+	}, doc.ValidateParams(op, url.Values{"limit": {"ten"}, "dry_run": {"maybe"}}))
+	assert.Equal(t, validation.Errors{
+		{Field: "format", Code: "invalid_value", Message: "has an unsupported value"},
+	}, doc.ValidateParams(op, url.Values{"format": {"xml"}}))
+}
@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+
+	schemaRefPrefix = "#/components/schemas/"
+
+	passwordMinLength = 8
+	passwordMaxLength = 72
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+func String() *Schema {
+	return &Schema{Type: TypeString}
+}
+
+func Integer() *Schema {
+	return &Schema{Type: TypeInteger}
+}
+
+func Boolean() *Schema {
+	return &Schema{Type: TypeBoolean}
+}
+
+func DateTime() *Schema {
+	return &Schema{Type: TypeString, Format: "date-time"}
+}
+
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: TypeArray, Items: items}
+}
+
+// WithEnum ограничивает схему перечисленными значениями.
+func (s *Schema) WithEnum(values ...any) *Schema {
+	s.Enum = values
+	return s
+}
+
+// Object — схема объекта с обязательными полями required.
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: TypeObject, Properties: properties, Required: required}
+}
+
+//nolint:gochecknoglobals // its ok
+var (
+	timeType = reflect.TypeFor[time.Time]()
+	rawType  = reflect.TypeFor[json.RawMessage]()
+)
+
+// Enum задаёт допустимые значения типа: перечисления вроде notes.Status — это int,
+// и без реестра их значения не вывести из типа.
+func (d *Document) Enum(v any, values ...any) {
+	if d.enums == nil {
+		d.enums = make(map[reflect.Type][]any)
+	}
+	d.enums[reflect.TypeOf(v)] = values
+}
+
+// Schema строит схему значения v. Структуры попадают в components и возвращаются ссылкой.
+func (d *Document) Schema(v any) *Schema {
+	return d.schemaOf(reflect.TypeOf(v))
+}
+
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		schema := d.schemaOf(t.Elem())
+		if schema.Ref != `` {
+			// В OpenAPI 3.0 соседние с $ref поля игнорируются.
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	}
+	if values, ok := d.enums[t]; ok {
+		schema := d.kindSchema(t)
+		schema.Enum = values
+		return schema
+	}
+	return d.kindSchema(t)
+}
+
+func (d *Document) kindSchema(t reflect.Type) *Schema {
+	if t == timeType {
+		return DateTime()
+	}
+	if t == rawType {
+		// Любое значение JSON.
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer()
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString, Format: "byte"}
+		}
+		return ArrayOf(d.schemaOf(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: d.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return d.structRef(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// structRef регистрирует структуру в components под именем "пакет.Тип".
+func (d *Document) structRef(t reflect.Type) *Schema {
+	if t.Name() == `` {
+		return d.structSchema(t)
+	}
+	name := t.String()
+	ref := &Schema{Ref: schemaRefPrefix + name}
+	if _, ok := d.Components.Schemas[name]; ok {
+		return ref
+	}
+	// Заглушка до заполнения защищает от бесконечной рекурсии на ссылающихся на себя типах.
+	d.Components.Schemas[name] = &Schema{}
+	*d.Components.Schemas[name] = *d.structSchema(t)
+	return ref
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: TypeObject, Properties: make(map[string]*Schema)}
+	d.addFields(schema, t)
+	return schema
+}
+
+func (d *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		if field.Anonymous && tag == `` && field.Type.Kind() == reflect.Struct {
+			d.addFields(schema, field.Type)
+			continue
+		}
+		name := tag
+		if name == `` {
+			name = field.Name
+		}
+
+		property := d.schemaOf(field.Type)
+		if property.Ref == `` {
+			if applyRules(property, field.Tag.Get("validate")) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// applyRules переносит правила из тега validate в схему и сообщает, обязательно ли поле.
+func applyRules(schema *Schema, tag string) bool {
+	required := false
+	rules := strings.Split(tag, ",")
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "notblank":
+			required = true
+			schema.MinLength = intPtr(1)
+		case "max", "min":
+			n, err := strconv.Atoi(param)
+			if err != nil || schema.Type != TypeString {
+				continue
+			}
+			if name == "max" {
+				schema.MaxLength = intPtr(n)
+			} else {
+				schema.MinLength = intPtr(n)
+			}
+		case "email":
+			schema.Format = "email"
+		case "http_url":
+			schema.Format = "uri"
+		case "oneof":
+			// oneof сужает перечисление из реестра, omitempty добавляет к нему пустое значение.
+			schema.Enum = nil
+			if slices.Contains(rules, "omitempty") {
+				schema.Enum = append(schema.Enum, ``)
+			}
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, value)
+			}
+		case "isdefault":
+			schema.ReadOnly = true
+		case "password":
+			// Границы совпадают с правилом password из пакета validation.
+			schema.Format = "password"
+			schema.MinLength = intPtr(passwordMinLength)
+			schema.MaxLength = intPtr(passwordMaxLength)
+		case "timezone":
+			schema.Description = "IANA time zone, e.g. Europe/Moscow"
+		case "bcp47_language_tag":
+			schema.Description = "BCP 47 language tag, e.g. en-US"
+		}
+	}
+	return required
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+// QueryParams описывает параметры запроса по тегам form структуры v.
+func (d *Document) QueryParams(v any) []Parameter {
+	t := reflect.TypeOf(v)
+	params := make([]Parameter, 0, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == `` || name == "-" {
+			continue
+		}
+		params = append(params, Query(name, ``, d.schemaOf(field.Type)))
+	}
+	return params
+}
@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+)
+
+// ValidateParams проверяет параметры запроса из query; параметры пути проверяет маршрутизатор.
+func (d *Document) ValidateParams(op *Operation, query url.Values) validation.Errors {
+	var errs validation.Errors
+	for _, param := range op.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		raw, ok := query[param.Name]
+		if !ok {
+			if param.Required {
+				errs = append(errs, fieldError(param.Name, "required", "must not be empty"))
+			}
+			continue
+		}
+		value, fieldErr := parseParam(param.Name, raw[0], d.Resolve(param.Schema))
+		if fieldErr != nil {
+			errs = append(errs, *fieldErr)
+			continue
+		}
+		errs = append(errs, d.Validate(param.Schema, value, param.Name)...)
+	}
+	return errs
+}
+
+// parseParam приводит строку из query к типу схемы.
+func parseParam(name, raw string, schema *Schema) (any, *validation.FieldError) {
+	switch schema.Type {
+	case TypeInteger, TypeNumber:
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, invalidType(name, schema.Type)
+		}
+		return json.Number(raw), nil
+	case TypeBoolean:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalidType(name, schema.Type)
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
+
+// ValidateJSON разбирает тело и проверяет его по схеме.
+func (d *Document) ValidateJSON(schema *Schema, body []byte) validation.Errors {
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return validation.Errors{fieldError(``, "invalid_json", "must be a valid JSON document")}
+	}
+	return d.Validate(schema, value, ``)
+}
+
+// Validate проверяет значение, разобранное из JSON с UseNumber, по схеме.
+func (d *Document) Validate(schema *Schema, value any, path string) validation.Errors {
+	schema = d.Resolve(schema)
+	if schema == nil {
+		return nil
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == `` {
+			return nil
+		}
+		return validation.Errors{*invalidType(path, schema.Type)}
+	}
+
+	var errs validation.Errors
+	switch schema.Type {
+	case TypeObject:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return validation.Errors{*invalidType(path, schema.Type)}
+		}
+		errs = d.validateObject(schema, object, path)
+	case TypeArray:
+		items, ok := value.([]any)
+		if !ok {
+			return validation.Errors{*invalidType(path, schema.Type)}
+		}
+		for i, item := range items {
+			errs = append(errs, d.Validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			return validation.Errors{*invalidType(path, schema.Type)}
+		}
+		errs = validateString(schema, str, path)
+	case TypeInteger:
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			return validation.Errors{*invalidType(path, schema.Type)}
+		}
+	case TypeNumber:
+		if _, ok := value.(json.Number); !ok {
+			return validation.Errors{*invalidType(path, schema.Type)}
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return validation.Errors{*invalidType(path, schema.Type)}
+		}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		errs = append(errs, fieldError(path, "invalid_value", "has an unsupported value"))
+	}
+	return errs
+}
+
+func (d *Document) validateObject(schema *Schema, object map[string]any, path string) validation.Errors {
+	var errs validation.Errors
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, fieldError(join(path, name), "required", "must not be empty"))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil {
+				errs = append(errs, d.Validate(schema.AdditionalProperties, object[name], join(path, name))...)
+			}
+			continue
+		}
+		if property.ReadOnly {
+			errs = append(errs, fieldError(join(path, name), "not_allowed", "must not be set by the client"))
+			continue
+		}
+		errs = append(errs, d.Validate(property, object[name], join(path, name))...)
+	}
+	return errs
+}
+
+func validateString(schema *Schema, value, path string) validation.Errors {
+	var errs validation.Errors
+	length := utf8.RuneCountInString(value)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		code, message := "too_short", fmt.Sprintf("must be at least %d characters", *schema.MinLength)
+		if *schema.MinLength == 1 {
+			code, message = "required", "must not be empty"
+		}
+		errs = append(errs, fieldError(path, code, message))
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		errs = append(errs, fieldError(path, "too_long", fmt.Sprintf("must be at most %d characters", *schema.MaxLength)))
+	}
+	switch schema.Format {
+	case "email":
+		if _, err := mail.ParseAddress(value); err != nil {
+			errs = append(errs, fieldError(path, "email", "must be a valid email address"))
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			errs = append(errs, fieldError(path, "invalid_value", "must be an RFC 3339 date-time"))
+		}
+	}
+	return errs
+}
+
+// inEnum сравнивает значения по строковому виду: json.Number(1) совпадает с int 1.
+func inEnum(enum []any, value any) bool {
+	str := fmt.Sprint(value)
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == str {
+			return true
+		}
+	}
+	return false
+}
+
+func join(path, name string) string {
+	if path == `` {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldError(field, code, message string) validation.FieldError {
+	return validation.FieldError{Field: field, Code: code, Message: message}
+}
+
+func invalidType(field, schemaType string) *validation.FieldError {
+	fieldErr := fieldError(field, "invalid_type", "must be of type "+schemaType)
+	return &fieldErr
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/server/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecCoversRoutes(t *testing.T) {
+	api := &NotesAPI{httpServe: &http.Server{}, log: zerolog.Nop(), testMode: true}
+	api.configRoutes()
+	engine, ok := api.httpServe.Handler.(*gin.Engine)
+	require.True(t, ok)
+
+	routes := engine.Routes()
+	for _, route := range routes {
+		_, ok = api.spec.Operation(route.Method, route.Path)
+		assert.True(t, ok, "route %s %s is missing from the OpenAPI document", route.Method, route.Path)
+	}
+
+	operations := 0
+	operationIDs := make(map[string]bool)
+	for path, item := range api.spec.Paths {
+		for method, op := range *item {
+			operations++
+			assert.NotEmpty(t, op.Responses, "%s %s has no responses", method, path)
+			assert.False(t, operationIDs[op.OperationID], "duplicate operationId %s", op.OperationID)
+			operationIDs[op.OperationID] = true
+		}
+	}
+	assert.Len(t, routes, operations, "the OpenAPI document describes routes that are not registered")
+}
+
+func TestServeSpec(t *testing.T) {
+	ts, _ := newV1TestServer(t)
+	client := newV1Client(ts).SetBaseURL(ts.URL)
+
+	var spec openapi.Document
+	resp, err := client.R().SetResult(&spec).Get("/openapi.json")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, openapi.Version, spec.OpenAPI)
+	require.Contains(t, spec.Paths, "/api/v1/notes/{id}")
+	assert.Contains(t, spec.Components.Schemas, "notes.Note")
+	assert.True(t, (*spec.Paths["/notes/add"])["post"].Deprecated)
+	patch := (*spec.Paths["/api/v1/notes/{id}"])["patch"]
+	require.NotNil(t, patch)
+	assert.Equal(t, "id", patch.Parameters[0].Name)
+	assert.Equal(t, []openapi.SecurityRequirement{{bearerAuth: {}}}, patch.Security)
+
+	resp, err = client.R().Get("/docs")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, resp.String(), "/openapi.json")
+}
+
+func TestValidateRequests(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "test-user", Name: "Bob", Email: "bob@example.com"}))
+	api := &NotesAPI{
+		cfg:       &internal.Config{ValidateRequests: true},
+		httpServe: &http.Server{},
+		repo:      repo,
+		repoNote:  inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json")),
+		log:       zerolog.Nop(),
+		testMode:  true,
+	}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+	client := newV1Client(ts)
+
+	type response struct {
+		Error  string            `json:"error"`
+		Fields validation.Errors `json:"fields"`
+	}
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		fields validation.Errors
+	}{
+		{
+			name:   "wrong type",
+			method: http.MethodPost,
+			path:   "/notes",
+			body:   map[string]any{"title": 5},
+			fields: validation.Errors{{Field: "title", Code: "invalid_type", Message: "must be of type string"}},
+		},
+		{
+			name:   "unknown status",
+			method: http.MethodPost,
+			path:   "/notes",
+			body:   map[string]any{"title": "Buy milk", "status": 9},
+			fields: validation.Errors{{Field: "status", Code: "invalid_value", Message: "has an unsupported value"}},
+		},
+		{
+			name:   "missing required field and read-only field",
+			method: http.MethodPut,
+			path:   "/notes/n1",
+			body:   map[string]any{"uid": "alice"},
+			fields: validation.Errors{
+				{Field: "title", Code: "required", Message: "must not be empty"},
+				{Field: "uid", Code: "not_allowed", Message: "must not be set by the client"},
+			},
+		},
+		{
+			name:   "query parameter outside enum",
+			method: http.MethodGet,
+			path:   "/notes?sort=title",
+			fields: validation.Errors{{Field: "sort", Code: "invalid_value", Message: "has an unsupported value"}},
+		},
+		{
+			name:   "broken JSON",
+			method: http.MethodPatch,
+			path:   "/users/me/preferences",
+			body:   `{"timezone":`,
+			fields: validation.Errors{{Code: "invalid_json", Message: "must be a valid JSON document"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var result response
+			req := client.R().SetResult(&result).SetError(&result).SetHeader("Content-Type", "application/json")
+			if tc.body != nil {
+				req.SetBody(tc.body)
+			}
+			resp, err := req.Execute(tc.method, tc.path)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode(), resp.String())
+			assert.Equal(t, "validation failed", result.Error)
+			assert.Equal(t, tc.fields, result.Fields)
+		})
+	}
+
+	t.Run("valid request reaches the handler", func(t *testing.T) {
+		resp, err := client.R().SetBody(map[string]any{"title": "Buy milk", "priority": 2}).Post("/notes")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode(), resp.String())
+
+		var created map[string]any
+		require.NoError(t, json.Unmarshal(resp.Body(), &created))
+		assert.Equal(t, "High", created["priority"])
+	})
+}
+
+func TestValidateRequestsAfterAuth(t *testing.T) {
+	_, api := newAuthTestServer(t)
+	api.cfg = &internal.Config{ValidateRequests: true}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+
+	body := map[string]any{"title": 5}
+	resp, err := newV1Client(ts).R().SetBody(body).Post("/notes")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode(), "an anonymous request must not learn the schema")
+
+	session, err := api.userService().CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	token, err := jwtToken("uid-1", session.ID)
+	require.NoError(t, err)
+	resp, err = newV1Client(ts).R().SetAuthToken(token).SetBody(body).Post("/notes")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode(), resp.String())
+}
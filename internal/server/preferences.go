@@ -0,0 +1,57 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+// preferences возвращает настройки текущего пользователя. Без авторизации и при ошибке
+// чтения используются настройки по умолчанию: форматирование не должно ломать ответ.
+func (s *NotesAPI) preferences(ctx *gin.Context) users.Preferences {
+	uid := ctx.GetString("uid")
+	if uid == `` || s.repo == nil {
+		return users.DefaultPreferences()
+	}
+	prefs, err := s.userService().GetPreferences(uid)
+	if err != nil {
+		s.log.Error().Err(err).Str("uid", uid).Msg("failed to get preferences")
+		return users.DefaultPreferences()
+	}
+	return prefs
+}
+
+func (s *NotesAPI) getPreferences(ctx *gin.Context) {
+	userService := s.userService()
+	prefs, err := userService.GetPreferences(ctx.GetString("uid"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, prefs)
+}
+
+func (s *NotesAPI) updatePreferences(ctx *gin.Context) {
+	var patch users.PreferencesPatch
+	if err := ctx.BindJSON(&patch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+
+	userService := s.userService()
+	prefs, err := userService.UpdatePreferences(ctx.GetString("uid"), patch)
+	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
+		if errors.Is(err, users.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, prefs)
+}
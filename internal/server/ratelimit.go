@@ -0,0 +1,96 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/ratelimit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitStore interface {
+	// TakeToken атомарно забирает токен из корзины key.
+	TakeToken(key string, limit ratelimit.Limit, now time.Time) (ratelimit.Decision, error)
+	// PurgeRateLimits удаляет корзины, к которым не обращались с idleSince.
+	PurgeRateLimits(idleSince time.Time) (int, error)
+}
+
+// WithRateLimitStore включает ограничение частоты запросов по лимитам из Config.RateLimit.
+func WithRateLimitStore(store RateLimitStore) Option {
+	return func(nApi *NotesAPI) {
+		nApi.rateLimits = store
+	}
+}
+
+// RateLimit ограничивает частоту запросов к группе маршрутов. Бюджет считается на
+// пользователя, а для анонимных запросов — на IP. Если хранилище недоступно, запрос
+// пропускается: ограничение не должно останавливать сервис.
+func (nApi *NotesAPI) RateLimit(group string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		limit, ok := nApi.rateLimit(group)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		decision, err := nApi.rateLimits.TakeToken(group+":"+nApi.rateLimitKey(ctx), limit, time.Now().UTC())
+		if err != nil {
+			nApi.log.Error().Err(err).Str("group", group).Msg("failed to check rate limit")
+			ctx.Next()
+			return
+		}
+		ctx.Header(ratelimit.PolicyHeader, limit.Policy())
+		ctx.Header(ratelimit.LimitHeader, strconv.Itoa(decision.Limit))
+		ctx.Header(ratelimit.RemainingHeader, strconv.Itoa(decision.Remaining))
+		ctx.Header(ratelimit.ResetHeader, ratelimit.Seconds(decision.Reset))
+		if !decision.Allowed {
+			ctx.Header("Retry-After", ratelimit.Seconds(decision.RetryAfter))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": ratelimit.ErrRateLimited.Error()})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func (nApi *NotesAPI) rateLimit(group string) (ratelimit.Limit, bool) {
+	if nApi.rateLimits == nil || nApi.cfg == nil {
+		return ratelimit.Limit{}, false
+	}
+	cfgLimit := nApi.cfg.RateLimit.Groups[group]
+	limit := ratelimit.Limit{Requests: cfgLimit.Requests, Per: cfgLimit.Per, Burst: cfgLimit.Burst}
+	return limit, limit.Enabled()
+}
+
+// rateLimitKey определяет, чей бюджет расходует запрос. Лимит проверяется до JWTMiddleware,
+// поэтому пользователь берётся из подписи JWT без проверки отзыва. Персональные токены
+// не проверяются: иначе случайными токенами можно было бы получать новые корзины.
+func (nApi *NotesAPI) rateLimitKey(ctx *gin.Context) string {
+	if uid := ctx.GetString("uid"); uid != `` {
+		return "uid:" + uid
+	}
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if token != `` && !strings.HasPrefix(token, users.AccessTokenPrefix) {
+		if claims, err := validateJwtToken(token); err == nil && claims.Subject != `` {
+			return "uid:" + claims.Subject
+		}
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+// PurgeRateLimits удаляет корзины, которые успели наполниться: они ничем не отличаются от новых.
+// Хранилище не различает группы, поэтому ждём самую медленную из них.
+func (nApi *NotesAPI) PurgeRateLimits() (int, error) {
+	if nApi.rateLimits == nil || nApi.cfg == nil {
+		return 0, nil
+	}
+	var idle time.Duration
+	for group := range nApi.cfg.RateLimit.Groups {
+		if limit, ok := nApi.rateLimit(group); ok {
+			idle = max(idle, limit.RefillTime())
+		}
+	}
+	return nApi.rateLimits.PurgeRateLimits(time.Now().UTC().Add(-idle))
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/ratelimit"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedServer(t *testing.T, limit internal.RateLimit) *httptest.Server {
+	t.Helper()
+	api := &NotesAPI{
+		cfg: &internal.Config{RateLimit: internal.RateLimitConfig{
+			Groups: map[string]internal.RateLimit{"notes": limit},
+		}},
+		rateLimits: inmemory.NewRateLimits(),
+		log:        zerolog.Nop(),
+	}
+
+	r := gin.New()
+	r.GET("/notes", api.RateLimit("notes"), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	r.GET("/free", api.RateLimit("free"), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestRateLimit(t *testing.T) {
+	ts := newRateLimitedServer(t, internal.RateLimit{Requests: 2, Per: time.Minute})
+	client := resty.New()
+
+	for remaining := 1; remaining >= 0; remaining-- {
+		resp, err := client.R().Get(ts.URL + "/notes")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, "2", resp.Header().Get(ratelimit.LimitHeader))
+		assert.Equal(t, "2;w=60", resp.Header().Get(ratelimit.PolicyHeader))
+		assert.Equal(t, strconv.Itoa(remaining), resp.Header().Get(ratelimit.RemainingHeader))
+	}
+
+	resp, err := client.R().Get(ts.URL + "/notes")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+	assert.Equal(t, "30", resp.Header().Get("Retry-After"))
+	assert.Equal(t, "60", resp.Header().Get(ratelimit.ResetHeader))
+
+	resp, err = client.R().Get(ts.URL + "/free")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode(), "group without a limit")
+	assert.Empty(t, resp.Header().Get(ratelimit.LimitHeader))
+}
+
+func TestRateLimit_KeyedByUser(t *testing.T) {
+	ts := newRateLimitedServer(t, internal.RateLimit{Requests: 1, Per: time.Minute})
+	client := resty.New()
+
+	alice, err := jwtToken("alice", "s1")
+	require.NoError(t, err)
+	bob, err := jwtToken("bob", "s2")
+	require.NoError(t, err)
+
+	for _, token := range []string{alice, bob, ``} {
+		resp, err := client.R().SetAuthToken(token).Get(ts.URL + "/notes")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode(), "each user and the anonymous IP have their own budget")
+	}
+
+	resp, err := client.R().SetAuthToken(alice).Get(ts.URL + "/notes")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+
+	resp, err = client.R().SetAuthToken("tdl_forged").Get(ts.URL + "/notes")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode(), "unverified access tokens share the IP budget")
+}
+
+func TestRateLimit_ForwardedForFromTrustedProxiesOnly(t *testing.T) {
+	newServer := func(trustedProxies []string) *httptest.Server {
+		api := &NotesAPI{
+			cfg: &internal.Config{
+				RateLimit: internal.RateLimitConfig{Groups: map[string]internal.RateLimit{
+					"users": {Requests: 1, Per: time.Minute},
+				}},
+				TrustedProxies: trustedProxies,
+			},
+			httpServe:  &http.Server{},
+			repo:       inmemory.NewUsers(),
+			rateLimits: inmemory.NewRateLimits(),
+			log:        zerolog.Nop(),
+		}
+		api.configRoutes()
+		ts := httptest.NewServer(api.httpServe.Handler)
+		t.Cleanup(ts.Close)
+		return ts
+	}
+	statuses := func(ts *httptest.Server) []int {
+		var got []int
+		for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+			resp, err := resty.New().R().SetHeader("X-Forwarded-For", ip).Get(ts.URL + "/users/verify")
+			require.NoError(t, err)
+			got = append(got, resp.StatusCode())
+		}
+		return got
+	}
+
+	assert.Equal(t, []int{http.StatusBadRequest, http.StatusTooManyRequests}, statuses(newServer(nil)),
+		"a spoofed header must not give a new budget")
+	assert.Equal(t, []int{http.StatusBadRequest, http.StatusBadRequest}, statuses(newServer([]string{"127.0.0.1"})))
+}
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/server/openapi"
+	"github.com/Snoop-Duck/ToDoList/internal/services/webhook"
 	logger "github.com/Snoop-Duck/ToDoList/pkg"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/rs/zerolog"
@@ -22,10 +26,18 @@ import (
 const (
 	tokenExpirationHours = 3
 	readHeaderTimeout    = 30 * time.Second
+	maxImportSize        = 10 << 20
 )
 
 var jwtKey = []byte("my_secret_key") //nolint:gochecknoglobals // its ok
 
+var exportContentTypes = map[notes.ExportFormat]string{ //nolint:gochecknoglobals // its ok
+	notes.ExportCSV:      "text/csv; charset=utf-8",
+	notes.ExportMarkdown: "text/markdown; charset=utf-8",
+	notes.ExportICS:      "text/calendar; charset=utf-8",
+	notes.ExportJSON:     "application/json; charset=utf-8",
+}
+
 type Repository interface {
 	SaveUser(user users.User) error
 	GetUser(login string) (users.User, error)
@@ -33,15 +45,55 @@ type Repository interface {
 	GetAllUsers() ([]users.User, error)
 	GetUserID(userID string) (users.User, error)
 	UpdateUserID(userID string, user users.User) error
+	VerifyUser(userID string) error
+	SaveResetToken(token users.ResetToken) error
+	ConsumeResetToken(hash string) (users.ResetToken, error)
+	ResetPassword(userID, password string, changedAt time.Time) error
+	UpdateTOTP(userID string, totp users.TOTP) error
+	ConsumeRecoveryCode(userID, codeHash string) error
+	UseTOTPStep(userID string, step int64) error
+	SaveAccessToken(token users.AccessToken) error
+	GetAccessTokens(userID string) ([]users.AccessToken, error)
+	GetAccessTokenByHash(hash string) (users.AccessToken, error)
+	DeleteAccessToken(userID, tokenID string) error
+	SaveIdentity(identity users.Identity) error
+	GetUserByIdentity(issuer, subject string) (users.User, error)
+	GetIdentities(userID string) ([]users.Identity, error)
+	SaveSession(session users.Session) error
+	GetSessions(userID string) ([]users.Session, error)
+	GetSession(sessionID string) (users.Session, error)
+	TouchSession(sessionID string, lastSeen time.Time) error
+	DeleteSession(userID, sessionID string) error
+	ScheduleDeletion(userID string, deleteAt *time.Time) error
+	GetUsersDueForDeletion(now time.Time) ([]users.User, error)
+	GetPreferences(userID string) (users.Preferences, error)
+	SavePreferences(userID string, prefs users.Preferences) error
 	Close() error
 }
 
 type RepositoryNote interface {
 	AddNote(note notes.Note) error
 	GetNotes() ([]notes.Note, error)
+	GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error)
 	GetNoteID(noteID string) (notes.Note, error)
 	DeleteNote(noteID string) error
 	UpdateNote(noteID string, note notes.Note) error
+	MoveNote(noteID string, position string) error
+	MoveNoteToStatus(noteID string, status notes.Status, move notes.MoveRequest) (string, error)
+	ApplyBulk(ops []notes.BulkOperation, mode notes.BulkMode) ([]notes.BulkResult, error)
+	StreamNotes(uid string, fn func(note notes.Note) error) error
+	DeleteUserNotes(uid string) (int, error)
+}
+
+type Mailer interface {
+	Send(msg mail.Message) error
+}
+
+type AttemptStore interface {
+	GetAttempts(key string) (users.LoginAttempts, error)
+	RecordFailure(key string, now time.Time, window time.Duration) (users.LoginAttempts, error)
+	LockUntil(key string, until time.Time) error
+	ResetAttempts(key string) error
 }
 
 type NotesAPI struct {
@@ -49,11 +101,37 @@ type NotesAPI struct {
 	httpServe *http.Server
 	repo      Repository
 	repoNote  RepositoryNote
-	log       zerolog.Logger
-	testMode  bool
+	mailer    Mailer
+	attempts  AttemptStore
+	oidc      OIDCProvider
+	auditLog  AuditLog
+	events    EventBus
+	webhooks  *webhook.Service
+	// idempotency хранит ответы на запросы с Idempotency-Key; без него заголовок игнорируется.
+	idempotency IdempotencyStore
+	rateLimits  RateLimitStore
+	shutdown    chan struct{}
+	spec        *openapi.Document
+	log         zerolog.Logger
+	testMode    bool
+}
+
+type Option func(*NotesAPI)
+
+func WithMailer(mailer Mailer) Option {
+	return func(nApi *NotesAPI) {
+		nApi.mailer = mailer
+	}
 }
 
-func New(cfg *internal.Config, repo Repository, repoNote RepositoryNote) *NotesAPI {
+// WithAttemptStore включает защиту /users/login от перебора паролей.
+func WithAttemptStore(store AttemptStore) Option {
+	return func(nApi *NotesAPI) {
+		nApi.attempts = store
+	}
+}
+
+func New(cfg *internal.Config, repo Repository, repoNote RepositoryNote, opts ...Option) *NotesAPI {
 	var log zerolog.Logger
 	if cfg != nil {
 		log = logger.Get(cfg.Debug)
@@ -71,8 +149,14 @@ func New(cfg *internal.Config, repo Repository, repoNote RepositoryNote) *NotesA
 		cfg:       cfg,
 		repo:      repo,
 		repoNote:  repoNote,
+		shutdown:  make(chan struct{}),
 		log:       log,
 	}
+	for _, opt := range opts {
+		opt(&notesAPI)
+	}
+	// Shutdown не ждёт завершения потоков событий: они закрываются сами.
+	httpServe.RegisterOnShutdown(func() { close(notesAPI.shutdown) })
 	notesAPI.configRoutes()
 	return &notesAPI
 }
@@ -89,16 +173,28 @@ func (nApi *NotesAPI) Stop(ctx context.Context) error {
 func (nApi *NotesAPI) configRoutes() {
 	nApi.log.Debug().Msg("configure routes")
 	router := gin.Default()
+	// X-Forwarded-For учитывается только от заданных прокси: иначе клиент подставил бы любой
+	// адрес и обошёл лимиты, блокировку входа и журнал аудита.
+	var trustedProxies []string
+	if nApi.cfg != nil {
+		trustedProxies = nApi.cfg.TrustedProxies
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		nApi.log.Error().Err(err).Msg("failed to set trusted proxies, X-Forwarded-For is ignored")
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.Use(nApi.Metrics())
 
 	router.Use(gzip.Gzip(
 		gzip.BestSpeed,
 		gzip.WithDecompressFn(gzip.DefaultDecompressHandle),
+		gzip.WithExcludedPaths([]string{streamPath}),
 	))
 
 	router.Use(gzip.Gzip(
 		gzip.BestSpeed,
 		gzip.WithExcludedExtensions([]string{".png", ".gif", ".jpeg", ".jpg"}),
-		gzip.WithExcludedPaths([]string{"/metrics"}),
+		gzip.WithExcludedPaths([]string{metricsPath, streamPath}),
 	))
 
 	router.Use(func(c *gin.Context) {
@@ -110,23 +206,106 @@ func (nApi *NotesAPI) configRoutes() {
 		c.Next()
 	})
 
+	nApi.spec = buildSpec()
+
+	readNotes := nApi.RequireScope(users.ScopeNotesRead)
+	writeNotes := nApi.RequireScope(users.ScopeNotesWrite)
+
 	router.GET("/")
-	users := router.Group("/users")
+	router.GET("/openapi.json", nApi.getSpec)
+	router.GET("/docs", nApi.getDocs)
+	router.GET(metricsPath, nApi.MetricsAuth(), nApi.getMetrics)
+	users := nApi.routes(router.Group("/users", nApi.RateLimit("users")))
 	{
-		users.GET("/profile", nApi.getUsers)
-		users.GET("/profile/:id", nApi.getUserID)
-		users.POST("/register", nApi.register)
+		users.GET("/profile", nApi.Deprecated(apiV1+"/users/me"),
+			nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.AdminMiddleware(), nApi.getUsers)
+		users.GET("/profile/:id", nApi.Deprecated(apiV1+"/users/me"),
+			nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.OwnerOnly(), nApi.getUserID)
+		users.POST("/register", nApi.Idempotent(), nApi.register)
 		users.POST("/login", nApi.login)
-		users.PUT("/upd/:id", nApi.updateUserID)
-		users.DELETE("/del/:id", nApi.deleteUser)
+		users.POST("/login/2fa", nApi.loginSecondFactor)
+		users.GET("/oidc/login", nApi.oidcLogin)
+		users.GET("/oidc/callback", nApi.oidcCallback)
+		users.PUT("/upd/:id", nApi.Deprecated(apiV1+"/users/me"),
+			nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.OwnerOnly(), nApi.updateUserID)
+		users.DELETE("/del/:id", nApi.Deprecated(apiV1+"/users/me"),
+			nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.OwnerOnly(), nApi.deleteUser)
+		users.GET("/verify", nApi.verifyEmail)
+		users.POST("/verify/resend", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.resendVerification)
+		users.POST("/password/forgot", nApi.forgotPassword)
+		users.POST("/password/reset", nApi.resetPassword)
+		users.POST("/2fa/enroll", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.enrollTOTP)
+		users.POST("/2fa/confirm", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.confirmTOTP)
+		users.POST("/2fa/disable", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.disableTOTP)
+		users.GET("/tokens", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.listAccessTokens)
+		users.POST("/tokens", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.createAccessToken)
+		users.DELETE("/tokens/:id", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.revokeAccessToken)
+		users.GET("/sessions", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.listSessions)
+		users.DELETE("/sessions/:id", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.revokeSession)
+		users.GET("/me/export", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.exportUser)
+		users.DELETE("/me", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.requestDeletion)
+		users.POST("/me/restore", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.cancelDeletion)
+		users.GET("/me/preferences", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.getPreferences)
+		users.PATCH("/me/preferences", nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.updatePreferences)
 	}
-	notes := router.Group("/notes")
+	notes := nApi.routes(router.Group("/notes",
+		nApi.RateLimit("notes"), nApi.JWTMiddleware(), nApi.VerifiedMiddleware()))
 	{
-		notes.GET("/list", nApi.JWTMiddleware(), nApi.getNotes)
-		notes.GET("/list/:id", nApi.getNoteID)
-		notes.POST("/add", nApi.createNote)
-		notes.PUT("/upd/:id", nApi.updateNote)
-		notes.DELETE("/del/:id", nApi.deleteNote)
+		notes.GET("/list", nApi.Deprecated(apiV1+"/notes"), readNotes, nApi.getNotes)
+		notes.GET("/list/:id", nApi.Deprecated(apiV1+"/notes/:id"), readNotes, nApi.getNoteID)
+		notes.POST("/add", nApi.Deprecated(apiV1+"/notes"), writeNotes, nApi.Idempotent(), nApi.createNote)
+		notes.PUT("/upd/:id", nApi.Deprecated(apiV1+"/notes/:id"), writeNotes, nApi.updateNote)
+		notes.DELETE("/del/:id", nApi.Deprecated(apiV1+"/notes/:id"), writeNotes, nApi.deleteNote)
+		notes.POST("/:id/move", writeNotes, nApi.moveNote)
+		notes.POST("/bulk", writeNotes, nApi.Idempotent(), nApi.bulkNotes)
+		notes.POST("/import", writeNotes, nApi.importNotes)
+		notes.GET("/export", readNotes, nApi.exportNotes)
+		notes.POST("/stream/ticket", readNotes, nApi.issueStreamTicket)
+	}
+	// Поток событий открывается и по билету, поэтому JWT группы /notes ему не подходит.
+	stream := nApi.routes(router.Group(streamPath,
+		nApi.RateLimit("notes"), nApi.StreamAuth(), nApi.VerifiedMiddleware()))
+	stream.GET(``, readNotes, nApi.streamNotes)
+	boards := nApi.routes(router.Group("/boards",
+		nApi.RateLimit("boards"), nApi.JWTMiddleware(), nApi.VerifiedMiddleware()))
+	{
+		boards.GET("/:board", readNotes, nApi.getBoard)
+		boards.POST("/:board/notes/:id/move", writeNotes, nApi.moveOnBoard)
+	}
+	v1 := nApi.routes(router.Group(apiV1, nApi.RateLimit("v1"), nApi.JWTMiddleware()))
+	{
+		v1Notes := v1.Group("/notes", nApi.VerifiedMiddleware())
+		v1Notes.GET("", readNotes, nApi.listMyNotes)
+		v1Notes.POST("", writeNotes, nApi.Idempotent(), nApi.createMyNote)
+		v1Notes.GET("/:id", readNotes, nApi.getMyNote)
+		v1Notes.PUT("/:id", writeNotes, nApi.replaceMyNote)
+		v1Notes.PATCH("/:id", writeNotes, nApi.patchMyNote)
+		v1Notes.DELETE("/:id", writeNotes, nApi.deleteMyNote)
+
+		me := v1.Group("/users/me", nApi.InteractiveOnly())
+		me.GET("", nApi.getMe)
+		me.PATCH("", nApi.updateMe)
+		me.DELETE("", nApi.requestDeletion)
+		me.GET("/preferences", nApi.getPreferences)
+		me.PATCH("/preferences", nApi.updatePreferences)
+	}
+	hooks := nApi.routes(router.Group("/webhooks", nApi.RateLimit("webhooks"),
+		nApi.JWTMiddleware(), nApi.VerifiedMiddleware(), nApi.InteractiveOnly(), nApi.WebhooksConfigured()))
+	{
+		hooks.POST("", nApi.createWebhook)
+		hooks.GET("", nApi.listWebhooks)
+		hooks.GET("/dead-letters", nApi.listDeadLetters)
+		hooks.POST("/dead-letters/:id/retry", nApi.retryDelivery)
+		hooks.GET("/:id", nApi.getWebhook)
+		hooks.DELETE("/:id", nApi.deleteWebhook)
+		hooks.GET("/:id/deliveries", nApi.listDeliveries)
+	}
+	admin := nApi.routes(router.Group("/admin",
+		nApi.RateLimit("admin"), nApi.JWTMiddleware(), nApi.InteractiveOnly(), nApi.AdminMiddleware()))
+	{
+		admin.POST("/users/unlock", nApi.unlockUser)
+		admin.GET("/audit", nApi.listAudit)
+		admin.GET("/audit/verify", nApi.verifyAudit)
 	}
 	nApi.httpServe.Handler = router
 }
@@ -139,28 +318,109 @@ func (nApi *NotesAPI) JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		token := ctx.GetHeader("Authorization")
+		token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
 		if token == `` {
 			nApi.log.Error().Msg("token not found")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			return
 		}
-		uid, err := validateJwtToken(token)
+		principal, err := nApi.Authenticate(token)
 		if err != nil {
-			nApi.log.Error().Err(err).Msg("failed to validate token")
+			nApi.log.Error().Err(err).Msg("failed to authenticate")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, "invalid token")
 			return
 		}
-		nApi.log.Debug().Str("uid", uid).Msg("user was authorized")
-		ctx.Set("uid", uid)
+		nApi.log.Debug().Str("uid", principal.UID).Bool("access_token", principal.AccessToken).Msg("user was authorized")
+		ctx.Set("uid", principal.UID)
+		if principal.AccessToken {
+			ctx.Set(scopesKey, principal.Scopes)
+		} else {
+			ctx.Set(sessionKey, principal.SessionID)
+		}
 		ctx.Next()
 	}
 }
 
-func jwtToken(uid string) (string, error) {
+// Authenticate проверяет JWT или персональный токен и возвращает его владельца.
+// Им же пользуется gRPC API, поэтому правила входа у обоих API одинаковые.
+func (nApi *NotesAPI) Authenticate(token string) (users.Principal, error) {
+	if strings.HasPrefix(token, users.AccessTokenPrefix) {
+		accessToken, err := nApi.userService().AuthenticateAccessToken(token)
+		if err != nil {
+			return users.Principal{}, fmt.Errorf("invalid access token: %w", err)
+		}
+		return users.Principal{UID: accessToken.UID, Scopes: accessToken.Scopes, AccessToken: true}, nil
+	}
+	claims, err := validateJwtToken(token)
+	if err != nil {
+		return users.Principal{}, err
+	}
+	uid := claims.Subject
+	if err = nApi.userService().CheckToken(uid, claims.IssuedAt.Time); err != nil {
+		return users.Principal{}, fmt.Errorf("token was revoked: %w", err)
+	}
+	if err = nApi.userService().CheckSession(uid, claims.ID); err != nil {
+		return users.Principal{}, fmt.Errorf("session %s was revoked: %w", claims.ID, err)
+	}
+	return users.Principal{UID: uid, SessionID: claims.ID}, nil
+}
+
+// VerifiedMiddleware не пускает пользователей, не подтвердивших почту после льготного периода.
+func (nApi *NotesAPI) VerifiedMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if nApi.testMode {
+			ctx.Next()
+			return
+		}
+
+		err := nApi.userService().CheckVerified(ctx.GetString("uid"))
+		if errors.Is(err, users.ErrEmailNotVerified) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			nApi.log.Error().Err(err).Msg("failed to check email verification")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// OwnerOnly пускает, только если :id в пути совпадает с пользователем из токена.
+func (nApi *NotesAPI) OwnerOnly() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Param("id") != ctx.GetString("uid") {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// AdminMiddleware пускает только пользователей, чьи адреса перечислены в Config.Admins.
+func (nApi *NotesAPI) AdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if nApi.testMode {
+			ctx.Next()
+			return
+		}
+
+		user, err := nApi.userService().GetUser(ctx.GetString("uid"))
+		if err != nil || nApi.cfg == nil || !slices.Contains(nApi.cfg.Admins, user.Email) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func jwtToken(uid, sessionID string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ID:        sessionID,
 		Subject:   uid,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenExpirationHours)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenExpirationHours * time.Hour)),
 	})
 	tokenString, err := token.SignedString(jwtKey)
 	if err != nil {
@@ -169,18 +429,18 @@ func jwtToken(uid string) (string, error) {
 	return tokenString, nil
 }
 
-func validateJwtToken(tokenString string) (string, error) {
+func validateJwtToken(tokenString string) (jwt.RegisteredClaims, error) {
 	claims := jwt.RegisteredClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, &claims, func(_ *jwt.Token) (interface{}, error) {
 		return jwtKey, nil
 	})
 	if err != nil {
-		return ``, err
+		return jwt.RegisteredClaims{}, err
 	}
 
-	if !token.Valid {
-		return ``, errors.New("invalid token")
+	if !token.Valid || claims.IssuedAt == nil || claims.ID == `` {
+		return jwt.RegisteredClaims{}, errors.New("invalid token")
 	}
 
-	return claims.Subject, nil
+	return claims, nil
 }
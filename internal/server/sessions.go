@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+// sessionKey хранит в контексте ID сессии, по которой выдан JWT.
+const sessionKey = "session"
+
+// issueToken открывает сессию для текущего устройства и подписывает JWT с её ID.
+func (s *NotesAPI) issueToken(ctx *gin.Context, userID string) (string, error) {
+	session, err := s.userService().CreateSession(
+		userID,
+		ctx.Request.UserAgent(),
+		ctx.ClientIP(),
+		tokenExpirationHours*time.Hour,
+	)
+	if err != nil {
+		return ``, err
+	}
+	return jwtToken(userID, session.ID)
+}
+
+func (s *NotesAPI) listSessions(ctx *gin.Context) {
+	userService := s.userService()
+	sessions, err := userService.ListSessions(ctx.GetString("uid"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	current := ctx.GetString(sessionKey)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == current
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+func (s *NotesAPI) revokeSession(ctx *gin.Context) {
+	userService := s.userService()
+	err := userService.RevokeSession(ctx.GetString("uid"), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, users.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
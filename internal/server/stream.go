@@ -0,0 +1,298 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamPath = "/notes/stream"
+	// streamHeartbeat не даёт прокси закрыть простаивающее соединение.
+	streamHeartbeat    = 15 * time.Second
+	streamWriteTimeout = 10 * time.Second
+	streamRetry        = 3 * time.Second
+
+	// streamReset просит клиента перечитать заметки: пропущенных событий уже нет в истории.
+	streamReset = "reset"
+	// streamOverflow предупреждает отстающего клиента перед отключением;
+	// переподключившись с Last-Event-ID, он дочитает пропущенное.
+	streamOverflow = "overflow"
+)
+
+type EventBus interface {
+	note.Publisher
+	Subscribe(uid string, lastID uint64) ([]notes.Event, notes.Subscription, error)
+}
+
+// WithEventBus включает публикацию изменений заметок и GET /notes/stream.
+func WithEventBus(bus EventBus) Option {
+	return func(nApi *NotesAPI) {
+		nApi.events = bus
+	}
+}
+
+// NoteService отдаёт сервис заметок с настройками сервера, например для gRPC API.
+func (s *NotesAPI) NoteService() *note.Service {
+	return s.noteService()
+}
+
+// noteService собирает сервис заметок; изменения публикуются в шину событий и вебхуки, если они заданы.
+func (s *NotesAPI) noteService() *note.Service {
+	opts := []note.Option{}
+	if s.events != nil {
+		opts = append(opts, note.WithPublisher(s.events))
+	}
+	if s.webhooks != nil {
+		opts = append(opts, note.WithPublisher(s.webhooks))
+	}
+	return note.New(s.repoNote, opts...)
+}
+
+// streamWriter отправляет события клиенту по SSE или WebSocket.
+type streamWriter interface {
+	event(payload notes.EventPayload) error
+	control(name string) error
+	ping() error
+}
+
+// streamNotes передаёт изменения видимых пользователю заметок: по WebSocket, если запрошено
+// обновление соединения, иначе как text/event-stream.
+func (s *NotesAPI) streamNotes(ctx *gin.Context) {
+	if s.events == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "event stream is not configured"})
+		return
+	}
+	lastID, err := lastEventID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+		return
+	}
+
+	uid := ctx.GetString("uid")
+	reset := false
+	backlog, sub, err := s.events.Subscribe(uid, lastID)
+	if errors.Is(err, notes.ErrEventsExpired) {
+		reset = true
+		backlog, sub, err = s.events.Subscribe(uid, 0)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer sub.Close()
+
+	loc := s.preferences(ctx).Location()
+	if websocket.IsWebSocketUpgrade(ctx.Request) {
+		s.streamWebSocket(ctx, reset, backlog, sub, loc)
+		return
+	}
+	s.streamSSE(ctx, reset, backlog, sub, loc)
+}
+
+// StreamAuth пускает в поток событий по заголовку Authorization или по билету из
+// POST /notes/stream/ticket в параметре ticket: браузер не может задать заголовок
+// ни для EventSource, ни для WebSocket.
+func (s *NotesAPI) StreamAuth() gin.HandlerFunc {
+	jwtAuth := s.JWTMiddleware()
+	return func(ctx *gin.Context) {
+		ticket := ctx.Query("ticket")
+		if ticket == `` || s.testMode {
+			jwtAuth(ctx)
+			return
+		}
+		principal, err := s.userService().AuthenticateStreamTicket(ticket)
+		if err != nil {
+			s.log.Error().Err(err).Msg("failed to authenticate stream ticket")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		ctx.Set("uid", principal.UID)
+		ctx.Set(scopesKey, principal.Scopes)
+		ctx.Next()
+	}
+}
+
+// issueStreamTicket выдаёт билет для подключения к потоку из браузера.
+func (s *NotesAPI) issueStreamTicket(ctx *gin.Context) {
+	ticket, err := s.userService().IssueStreamTicket(users.Principal{
+		UID:       ctx.GetString("uid"),
+		SessionID: ctx.GetString(sessionKey),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusCreated, ticket)
+}
+
+// lastEventID читает заголовок Last-Event-ID; параметр last_event_id нужен клиентам,
+// которые не могут задать заголовок при первом подключении.
+func lastEventID(ctx *gin.Context) (uint64, error) {
+	raw := ctx.GetHeader("Last-Event-ID")
+	if raw == `` {
+		raw = ctx.Query("last_event_id")
+	}
+	if raw == `` {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+// pump пишет историю и новые события, пока клиент не отключится или сервер не остановится.
+func (s *NotesAPI) pump(
+	done <-chan struct{},
+	w streamWriter,
+	reset bool,
+	backlog []notes.Event,
+	sub notes.Subscription,
+	loc *time.Location,
+) {
+	if reset {
+		if err := w.control(streamReset); err != nil {
+			return
+		}
+	}
+	for _, event := range backlog {
+		if err := w.event(notes.NewEventPayload(event, loc)); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				if errors.Is(sub.Err(), notes.ErrSubscriberLagging) {
+					_ = w.control(streamOverflow)
+				}
+				return
+			}
+			if err := w.event(notes.NewEventPayload(event, loc)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := w.ping(); err != nil {
+				return
+			}
+		case <-done:
+			return
+		case <-s.shutdown:
+			return
+		}
+	}
+}
+
+func (s *NotesAPI) streamSSE(ctx *gin.Context, reset bool, backlog []notes.Event, sub notes.Subscription, loc *time.Location) {
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	w := sseWriter{writer: ctx.Writer, rc: http.NewResponseController(ctx.Writer)}
+	if err := w.write(fmt.Sprintf("retry: %d\n\n", streamRetry.Milliseconds())); err != nil {
+		return
+	}
+	s.pump(ctx.Request.Context().Done(), w, reset, backlog, sub, loc)
+}
+
+type sseWriter struct {
+	writer gin.ResponseWriter
+	rc     *http.ResponseController
+}
+
+func (w sseWriter) event(payload notes.EventPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return w.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", payload.ID, payload.Type, data))
+}
+
+func (w sseWriter) control(name string) error {
+	return w.write(fmt.Sprintf("event: %s\ndata: {}\n\n", name))
+}
+
+func (w sseWriter) ping() error {
+	return w.write(": ping\n\n")
+}
+
+// write отправляет кадр сразу; срок записи не даёт зависшему клиенту держать обработчик.
+func (w sseWriter) write(frame string) error {
+	// Без поддержки сроков у обёртки ответа поток работает и без них.
+	_ = w.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if _, err := w.writer.WriteString(frame); err != nil {
+		return err
+	}
+	return w.rc.Flush()
+}
+
+//nolint:gochecknoglobals // its ok
+var upgrader = websocket.Upgrader{}
+
+func (s *NotesAPI) streamWebSocket(ctx *gin.Context, reset bool, backlog []notes.Event, sub notes.Subscription, loc *time.Location) {
+	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// Upgrade сам ответил клиенту ошибкой.
+		s.log.Error().Err(err).Msg("failed to upgrade event stream")
+		return
+	}
+	defer conn.Close()
+
+	// Клиент ничего не отправляет; чтение нужно, чтобы заметить закрытие соединения.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, readErr := conn.NextReader(); readErr != nil {
+				return
+			}
+		}
+	}()
+
+	w := wsWriter{conn: conn}
+	s.pump(done, w, reset, backlog, sub, loc)
+
+	code := websocket.CloseNormalClosure
+	if errors.Is(sub.Err(), notes.ErrSubscriberLagging) {
+		code = websocket.CloseTryAgainLater
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, ``),
+		time.Now().Add(streamWriteTimeout))
+}
+
+type wsWriter struct {
+	conn *websocket.Conn
+}
+
+func (w wsWriter) event(payload notes.EventPayload) error {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return w.conn.WriteJSON(payload)
+}
+
+func (w wsWriter) control(name string) error {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+	return w.conn.WriteJSON(gin.H{"type": name})
+}
+
+func (w wsWriter) ping() error {
+	return w.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	api := &NotesAPI{
+		httpServe: &http.Server{},
+		repo:      inmemory.NewUsers(),
+		repoNote:  inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json")),
+		events:    inmemory.NewEventBus(10, 10),
+		log:       zerolog.Nop(),
+		testMode:  true,
+	}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// openSSE подключается к потоку и пропускает первый кадр с retry.
+func openSSE(t *testing.T, ts *httptest.Server, lastEventID string) *bufio.Reader {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, ts.URL+streamPath, nil)
+	require.NoError(t, err)
+	if lastEventID != `` {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	assert.Contains(t, readFrame(t, reader), "retry")
+	return reader
+}
+
+// readFrame читает один кадр SSE как поле -> значение.
+func readFrame(t *testing.T, reader *bufio.Reader) map[string]string {
+	t.Helper()
+	frame := make(map[string]string)
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == `` {
+			return frame
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		frame[field] = value
+	}
+}
+
+func TestStreamNotesSSE(t *testing.T) {
+	ts := newStreamTestServer(t)
+	client := newV1Client(ts)
+	stream := openSSE(t, ts, ``)
+
+	var created notes.NoteResponseFormat
+	resp, err := client.R().SetBody(map[string]any{"title": "Buy milk"}).SetResult(&created).Post("/notes")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode())
+
+	frame := readFrame(t, stream)
+	assert.Equal(t, string(notes.EventCreated), frame["event"])
+	var payload notes.EventPayload
+	require.NoError(t, json.Unmarshal([]byte(frame["data"]), &payload))
+	assert.Equal(t, created.NID, payload.NID)
+	require.NotNil(t, payload.Note)
+	assert.Equal(t, "Buy milk", payload.Note.Title)
+	assert.Equal(t, strconv.FormatUint(payload.ID, 10), frame["id"])
+	createdID := frame["id"]
+
+	resp, err = client.R().SetBody(map[string]any{"status": notes.Active}).Patch("/notes/" + created.NID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	frame = readFrame(t, stream)
+	assert.Equal(t, string(notes.EventStatusChanged), frame["event"])
+	require.NoError(t, json.Unmarshal([]byte(frame["data"]), &payload))
+	assert.Equal(t, "New", payload.PrevStatus)
+	assert.Equal(t, "Active", payload.Note.Status)
+
+	t.Run("resume after last event id", func(t *testing.T) {
+		resumed := openSSE(t, ts, createdID)
+		assert.Equal(t, string(notes.EventStatusChanged), readFrame(t, resumed)["event"])
+	})
+
+	t.Run("reset when history is gone", func(t *testing.T) {
+		resumed := openSSE(t, ts, "1")
+		assert.Equal(t, streamReset, readFrame(t, resumed)["event"])
+	})
+
+	t.Run("invalid last event id", func(t *testing.T) {
+		req, reqErr := http.NewRequest(http.MethodGet, ts.URL+streamPath+"?last_event_id=abc", nil)
+		require.NoError(t, reqErr)
+		badResp, reqErr := http.DefaultClient.Do(req)
+		require.NoError(t, reqErr)
+		defer badResp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, badResp.StatusCode)
+	})
+}
+
+func TestStreamNotesWebSocket(t *testing.T) {
+	ts := newStreamTestServer(t)
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+streamPath, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	defer conn.Close()
+
+	var created notes.NoteResponseFormat
+	createResp, err := newV1Client(ts).R().SetBody(map[string]any{"title": "Buy milk"}).SetResult(&created).Post("/notes")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode())
+
+	var payload notes.EventPayload
+	require.NoError(t, conn.ReadJSON(&payload))
+	assert.Equal(t, notes.EventCreated, payload.Type)
+	assert.Equal(t, created.NID, payload.NID)
+}
+
+func TestStreamNotesWithoutBus(t *testing.T) {
+	ts, _ := newV1TestServer(t)
+	resp, err := newV1Client(ts).SetBaseURL(ts.URL).R().Get(streamPath)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+}
+
+func TestStreamNotesTicketWithoutSigningKey(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	token := sessionToken(t, api, "uid-1")
+
+	resp, err := resty.New().SetBaseURL(ts.URL).R().SetAuthToken(token).Post(streamPath + "/ticket")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+}
+
+func TestStreamNotesTicket(t *testing.T) {
+	_, api := newAuthTestServer(t)
+	api.events = inmemory.NewEventBus(10, 10)
+	api.cfg = &internal.Config{SigningKey: "signing"}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+
+	session, err := api.userService().CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	token, err := jwtToken("uid-1", session.ID)
+	require.NoError(t, err)
+
+	var ticket users.StreamTicket
+	resp, err := resty.New().SetBaseURL(ts.URL).R().SetAuthToken(token).SetResult(&ticket).Post(streamPath + "/ticket")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode(), resp.String())
+	require.NotEmpty(t, ticket.Ticket)
+
+	stream := func(query string) int {
+		streamResp, reqErr := http.Get(ts.URL + streamPath + query)
+		require.NoError(t, reqErr)
+		defer streamResp.Body.Close()
+		return streamResp.StatusCode
+	}
+	assert.Equal(t, http.StatusUnauthorized, stream(``))
+	assert.Equal(t, http.StatusUnauthorized, stream("?ticket=forged"))
+	assert.Equal(t, http.StatusOK, stream("?ticket="+url.QueryEscape(ticket.Ticket)))
+
+	require.NoError(t, api.userService().RevokeSession("uid-1", session.ID))
+	assert.Equal(t, http.StatusUnauthorized, stream("?ticket="+url.QueryEscape(ticket.Ticket)),
+		"the ticket ends with its session")
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+// scopesKey хранит в контексте права персонального токена; для JWT ключ не выставляется.
+const scopesKey = "scopes"
+
+// RequireScope проверяет право персонального токена; вход по JWT даёт полный доступ.
+func (nApi *NotesAPI) RequireScope(scope users.Scope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		value, ok := ctx.Get(scopesKey)
+		if !ok {
+			ctx.Next()
+			return
+		}
+		scopes, _ := value.([]users.Scope)
+		if !slices.Contains(scopes, scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": users.ErrInsufficientScope.Error(), "scope": scope})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// InteractiveOnly закрывает управление аккаунтом для персональных токенов.
+func (nApi *NotesAPI) InteractiveOnly() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.Get(scopesKey); ok {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": users.ErrInsufficientScope.Error()})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func (s *NotesAPI) listAccessTokens(ctx *gin.Context) {
+	userService := s.userService()
+	tokens, err := userService.ListAccessTokens(ctx.GetString("uid"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+func (s *NotesAPI) createAccessToken(ctx *gin.Context) {
+	var req users.AccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	token, err := userService.CreateAccessToken(ctx.GetString("uid"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, users.ErrEmptyTokenName), errors.Is(err, users.ErrInvalidScope),
+			errors.Is(err, users.ErrTokenExpired):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, users.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, token)
+}
+
+func (s *NotesAPI) revokeAccessToken(ctx *gin.Context) {
+	userService := s.userService()
+	err := userService.RevokeAccessToken(ctx.GetString("uid"), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, users.ErrTokenNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
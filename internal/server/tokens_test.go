@@ -0,0 +1,135 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessTokenScopes(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com"}))
+	srv := NotesAPI{repo: repo, log: zerolog.Nop()}
+
+	readOnly, err := srv.userService().CreateAccessToken("uid-1", users.AccessTokenRequest{
+		Name:   "reporting",
+		Scopes: []users.Scope{users.ScopeNotesRead},
+	})
+	require.NoError(t, err)
+	session, err := srv.userService().CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	jwt, err := jwtToken("uid-1", session.ID)
+	require.NoError(t, err)
+
+	ok := func(ctx *gin.Context) { ctx.String(http.StatusOK, ctx.GetString("uid")) }
+	testRouter := gin.New()
+	testRouter.GET("/read", srv.JWTMiddleware(), srv.RequireScope(users.ScopeNotesRead), ok)
+	testRouter.POST("/write", srv.JWTMiddleware(), srv.RequireScope(users.ScopeNotesWrite), ok)
+	testRouter.GET("/tokens", srv.JWTMiddleware(), srv.InteractiveOnly(), ok)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		want   int
+	}{
+		{name: "pat with scope", method: http.MethodGet, path: "/read", auth: readOnly.Token, want: http.StatusOK},
+		{name: "pat as bearer", method: http.MethodGet, path: "/read", auth: "Bearer " + readOnly.Token, want: http.StatusOK},
+		{name: "pat without scope", method: http.MethodPost, path: "/write", auth: readOnly.Token, want: http.StatusForbidden},
+		{name: "pat on account route", method: http.MethodGet, path: "/tokens", auth: readOnly.Token, want: http.StatusForbidden},
+		{name: "unknown pat", method: http.MethodGet, path: "/read", auth: "tdl_unknown", want: http.StatusUnauthorized},
+		{name: "jwt has full access", method: http.MethodPost, path: "/write", auth: jwt, want: http.StatusOK},
+		{name: "jwt on account route", method: http.MethodGet, path: "/tokens", auth: jwt, want: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := resty.New().R().SetHeader("Authorization", tc.auth).Execute(tc.method, httpTest.URL+tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, resp.StatusCode())
+		})
+	}
+}
+
+func TestSessionRevocation(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com", Password: "secret"}))
+	srv := NotesAPI{repo: repo, log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.POST("/login", srv.login)
+	testRouter.GET("/sessions", srv.JWTMiddleware(), srv.InteractiveOnly(), srv.listSessions)
+	testRouter.DELETE("/sessions/:id", srv.JWTMiddleware(), srv.InteractiveOnly(), srv.revokeSession)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	login := func() string {
+		resp, err := resty.New().R().
+			SetHeader("User-Agent", "test-agent").
+			SetBody(users.UserRequest{Email: "bob@example.com", Password: "secret"}).
+			Post(httpTest.URL + "/login")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+		return resp.Header().Get("Authorization")
+	}
+	first, second := login(), login()
+
+	var sessions []users.Session
+	resp, err := resty.New().R().SetHeader("Authorization", first).SetResult(&sessions).Get(httpTest.URL + "/sessions")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Len(t, sessions, 2)
+
+	var other users.Session
+	for _, session := range sessions {
+		assert.Equal(t, "test-agent", session.UserAgent)
+		if !session.Current {
+			other = session
+		}
+	}
+	require.NotEmpty(t, other.ID)
+
+	resp, err = resty.New().R().SetHeader("Authorization", first).Delete(httpTest.URL + "/sessions/" + other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+	resp, err = resty.New().R().SetHeader("Authorization", second).Get(httpTest.URL + "/sessions")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+
+	resp, err = resty.New().R().SetHeader("Authorization", first).Get(httpTest.URL + "/sessions")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+}
+
+func TestAccessTokenScopesOnLegacyRoutes(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	readOnly, err := api.userService().CreateAccessToken("uid-1", users.AccessTokenRequest{
+		Name:   "reporting",
+		Scopes: []users.Scope{users.ScopeNotesRead},
+	})
+	require.NoError(t, err)
+
+	resp, err := resty.New().R().
+		SetHeader("Authorization", readOnly.Token).
+		SetBody(map[string]string{"title": "Note"}).
+		Post(ts.URL + "/notes/add")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode())
+
+	resp, err = resty.New().R().SetHeader("Authorization", readOnly.Token).Get(ts.URL + "/notes/list/42")
+	require.NoError(t, err)
+	assert.NotEqual(t, http.StatusForbidden, resp.StatusCode())
+}
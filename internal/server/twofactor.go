@@ -0,0 +1,97 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *NotesAPI) loginSecondFactor(ctx *gin.Context) {
+	var req users.SecondFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	userID, err := userService.CompleteSecondFactor(req, ctx.ClientIP())
+	if err != nil {
+		var lockErr *users.LockoutError
+		if errors.As(err, &lockErr) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockErr.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := s.issueToken(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Header("Authorization", token)
+	ctx.String(http.StatusOK, "user logined: %s", userID)
+}
+
+func (s *NotesAPI) enrollTOTP(ctx *gin.Context) {
+	userService := s.userService()
+	enrollment, err := userService.EnrollTOTP(ctx.GetString("uid"))
+	if err != nil {
+		s.twoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+func (s *NotesAPI) confirmTOTP(ctx *gin.Context) {
+	var req users.TOTPCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	codes, err := userService.ConfirmTOTP(ctx.GetString("uid"), req.Code)
+	if err != nil {
+		s.twoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, codes)
+}
+
+func (s *NotesAPI) disableTOTP(ctx *gin.Context) {
+	var req users.TOTPCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	if err := userService.DisableTOTP(ctx.GetString("uid"), req.Code); err != nil {
+		s.twoFactorError(ctx, err)
+		return
+	}
+	ctx.String(http.StatusOK, "2fa disabled")
+}
+
+func (s *NotesAPI) twoFactorError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, users.ErrTOTPEnabled), errors.Is(err, users.ErrTOTPNotEnrolled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrInvalidCode):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrTOTPNotConfigured):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		s.log.Error().Err(err).Msg("2fa request failed")
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
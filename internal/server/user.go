@@ -1,10 +1,15 @@
 package server
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/services/user"
 
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
 
 	"github.com/gin-gonic/gin"
@@ -18,15 +23,33 @@ func (s *NotesAPI) login(ctx *gin.Context) {
 		return
 	}
 
-	userService := user.New(s.repo)
+	userService := s.userService()
 
-	userID, err := userService.LoginUser(uReq)
+	userID, err := userService.LoginUser(uReq, ctx.ClientIP())
 	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
+		var secondFactor *users.SecondFactorError
+		if errors.As(err, &secondFactor) {
+			ctx.JSON(http.StatusAccepted, users.PendingLogin{TwoFactorRequired: true, PendingToken: secondFactor.PendingToken})
+			return
+		}
+		var lockErr *users.LockoutError
+		if errors.As(err, &lockErr) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockErr.RetryAfter.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, users.ErrTOTPNotConfigured) {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	token, err := jwtToken(userID)
+	token, err := s.issueToken(ctx, userID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -43,14 +66,20 @@ func (s *NotesAPI) register(ctx *gin.Context) {
 		return
 	}
 
-	userService := user.New(s.repo)
+	userService := s.userService()
 
 	userID, err := userService.RegisterUser(uReq)
 	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
-	token, err := jwtToken(userID)
+	if err = userService.SendVerification(userID); err != nil {
+		s.log.Error().Err(err).Str("uid", userID).Msg("failed to send verification email")
+	}
+	token, err := s.issueToken(ctx, userID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -61,8 +90,9 @@ func (s *NotesAPI) register(ctx *gin.Context) {
 
 func (s *NotesAPI) deleteUser(ctx *gin.Context) {
 	userID := ctx.Param("id")
-	userService := user.New(s.repo)
+	userService := s.userService()
 	err := userService.DeleteUserID(userID)
+	s.recordAudit(ctx, audit.Event{Type: audit.EventUserDeleted, Target: userID}, err)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No user"})
 		return
@@ -70,35 +100,200 @@ func (s *NotesAPI) deleteUser(ctx *gin.Context) {
 	ctx.String(http.StatusOK, "User deleted: %s", userID)
 }
 
+// requestDeletion планирует удаление аккаунта; при нулевом сроке аккаунт удаляется сразу.
+func (s *NotesAPI) requestDeletion(ctx *gin.Context) {
+	userService := s.userService()
+	deleteAt, err := userService.RequestDeletion(ctx.GetString("uid"))
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, users.DeletionRequest{DeleteAt: deleteAt})
+}
+
+func (s *NotesAPI) cancelDeletion(ctx *gin.Context) {
+	userService := s.userService()
+	err := userService.CancelDeletion(ctx.GetString("uid"))
+	switch {
+	case err == nil:
+		ctx.String(http.StatusOK, "account deletion cancelled")
+	case errors.Is(err, users.ErrDeletionNotScheduled):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (s *NotesAPI) exportUser(ctx *gin.Context) {
+	userService := s.userService()
+	export, err := userService.ExportUser(ctx.GetString("uid"))
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="account-export.json"`)
+	ctx.JSON(http.StatusOK, export)
+}
+
+// PurgeDeletedUsers удаляет аккаунты, срок отмены удаления которых истёк.
+func (s *NotesAPI) PurgeDeletedUsers() (int, error) {
+	return s.userService().PurgeDeletedUsers(time.Now().UTC())
+}
+
 func (s *NotesAPI) getUsers(ctx *gin.Context) {
-	userService := user.New(s.repo)
+	userService := s.userService()
 	allUsers, err := userService.GetUsers()
 	if err != nil {
 		ctx.JSON(http.StatusAccepted, gin.H{"error": "No users"})
 		return
 	}
-	ctx.String(http.StatusOK, "Users get: %s", allUsers)
+	profiles := make([]users.Profile, 0, len(allUsers))
+	for _, user := range allUsers {
+		profiles = append(profiles, users.NewProfile(user))
+	}
+	ctx.JSON(http.StatusOK, profiles)
 }
 
 func (s *NotesAPI) getUserID(ctx *gin.Context) {
 	userID := ctx.Param("id")
-	userService := user.New(s.repo)
+	userService := s.userService()
 	getUser, err := userService.GetUser(userID)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No users"})
 		return
 	}
-	ctx.String(http.StatusOK, "User get: %s", getUser)
+	ctx.JSON(http.StatusOK, users.NewProfile(getUser))
 }
 
 func (s *NotesAPI) updateUserID(ctx *gin.Context) {
 	var uReq users.User
+	if err := ctx.ShouldBindJSON(&uReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	userID := ctx.Param("id")
-	userService := user.New(s.repo)
+	userService := s.userService()
 	err := userService.UpdateUser(userID, uReq)
 	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "No user"})
 		return
 	}
 	ctx.String(http.StatusOK, "User update: %s", userID)
 }
+
+func (s *NotesAPI) verifyEmail(ctx *gin.Context) {
+	userService := s.userService()
+	userID, err := userService.VerifyEmail(ctx.Query("token"))
+	if err != nil {
+		if errors.Is(err, users.ErrInvalidToken) || errors.Is(err, users.ErrUserNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": users.ErrInvalidToken.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.String(http.StatusOK, "email verified: %s", userID)
+}
+
+func (s *NotesAPI) resendVerification(ctx *gin.Context) {
+	userID := ctx.GetString("uid")
+	userService := s.userService()
+	err := userService.SendVerification(userID)
+	switch {
+	case err == nil:
+		ctx.String(http.StatusAccepted, "verification email sent")
+	case errors.Is(err, users.ErrAlreadyVerified):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrMailerNotSet), errors.Is(err, users.ErrSigningKeyNotSet):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (s *NotesAPI) forgotPassword(ctx *gin.Context) {
+	var req users.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Письмо отправляется в фоне: ни ответ, ни время ответа не должны зависеть от того,
+	// зарегистрирован ли адрес.
+	userService := s.userService()
+	go func() {
+		if err := userService.ForgotPassword(req.Email); err != nil {
+			s.log.Error().Err(err).Msg("failed to issue password reset")
+		}
+	}()
+	ctx.String(http.StatusAccepted, "if the account exists, a reset link has been sent")
+}
+
+func (s *NotesAPI) resetPassword(ctx *gin.Context) {
+	var req users.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userService := s.userService()
+	err := userService.ResetPassword(req)
+	if abortValidation(ctx, err) {
+		return
+	}
+	switch {
+	case err == nil:
+		ctx.String(http.StatusOK, "password updated")
+	case errors.Is(err, users.ErrEmptyPassword):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, users.ErrInvalidToken), errors.Is(err, users.ErrUserNotFound):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": users.ErrInvalidToken.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// UserService отдаёт сервис пользователей с настройками сервера, например для gRPC API.
+func (s *NotesAPI) UserService() *user.Service {
+	return s.userService()
+}
+
+// userService собирает сервис пользователей с настройками сервера.
+func (s *NotesAPI) userService() *user.Service {
+	opts := []user.Option{}
+	if s.mailer != nil {
+		opts = append(opts, user.WithMailer(s.mailer))
+	}
+	if s.attempts != nil {
+		opts = append(opts, user.WithAttemptStore(s.attempts))
+	}
+	if s.repoNote != nil {
+		opts = append(opts, user.WithNotes(s.repoNote))
+	}
+	if s.auditLog != nil {
+		opts = append(opts, user.WithAuditLog(s.auditLog))
+	}
+	if s.cfg != nil {
+		opts = append(opts, user.WithVerification([]byte(s.cfg.SigningKey), s.cfg.BaseURL, s.cfg.Mail.VerifyGrace))
+		opts = append(opts, user.WithDeletionGrace(s.cfg.DeletionGrace))
+	}
+	if s.cfg != nil && s.cfg.EncryptionKey != `` {
+		opts = append(opts, user.WithEncryptionKey([]byte(s.cfg.EncryptionKey)))
+	}
+	return user.New(s.repo, opts...)
+}
@@ -1,18 +1,31 @@
 package server
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Snoop-Duck/ToDoList/internal"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/mailer"
 	"github.com/Snoop-Duck/ToDoList/internal/server/mocks"
 	"github.com/gin-gonic/gin"
 	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLogin(t *testing.T) {
@@ -46,14 +59,14 @@ func TestLogin(t *testing.T) {
 		{
 			name: "test 1: success call",
 			uReq: users.UserRequest{
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			dbUser: users.User{
 				UID:      "uuid-1234-55rr",
 				Name:     "John Doe",
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			repoErr: nil,
 			request: "/login",
@@ -66,13 +79,13 @@ func TestLogin(t *testing.T) {
 		{
 			name: "test 2: invalid creds call",
 			uReq: users.UserRequest{
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			dbUser: users.User{
 				UID:      "uuid-1234-55rr",
 				Name:     "John Doe",
-				Email:    "email",
+				Email:    "john@example.com",
 				Password: "1234567",
 			},
 			repoErr: users.ErrInvalidUserCreds,
@@ -89,6 +102,7 @@ func TestLogin(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockRepo := mocks.NewRepository(t)
 			mockRepo.On("GetUser", tc.uReq.Email).Return(tc.dbUser, tc.repoErr)
+			mockRepo.On("SaveSession", mock.Anything).Return(nil).Maybe()
 			srv.repo = mockRepo
 
 			req := resty.New().R()
@@ -144,8 +158,8 @@ func TestReqister(t *testing.T) {
 			uReq: users.User{
 				UID:      "uuid-1234-55rr",
 				Name:     "John Doe",
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			repoErr: nil,
 			want: want{
@@ -161,8 +175,8 @@ func TestReqister(t *testing.T) {
 			uReq: users.User{
 				UID:      "uuid-1234-55rr",
 				Name:     "John Doe",
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			repoErr: users.ErrUserAlredyExists,
 			want: want{
@@ -180,6 +194,7 @@ func TestReqister(t *testing.T) {
 					user.Email == tc.uReq.Email &&
 					user.Password == tc.uReq.Password
 			})).Return(tc.repoErr)
+			mockRepo.On("SaveSession", mock.Anything).Return(nil).Maybe()
 			srv.repo = mockRepo
 
 			req := resty.New().R()
@@ -209,9 +224,11 @@ func TestGetUserIDHandler(t *testing.T) {
 	defer httpTest.Close()
 
 	testUser := users.User{
-		UID:   "123",
-		Name:  "Test User",
-		Email: "test@example.com",
+		UID:      "123",
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "hashed-password",
+		TOTP:     users.TOTP{Secret: "totp-secret", Enabled: true},
 	}
 
 	tests := []struct {
@@ -247,6 +264,8 @@ func TestGetUserIDHandler(t *testing.T) {
 
 			resp, _ := req.Send()
 			assert.Equal(t, tt.wantCode, resp.StatusCode())
+			assert.NotContains(t, resp.String(), "hashed-password")
+			assert.NotContains(t, resp.String(), "totp-secret")
 		})
 	}
 }
@@ -352,7 +371,7 @@ func TestGetUserID(t *testing.T) {
 		UID:      "123",
 		Name:     "Test User",
 		Email:    "test@example.com",
-		Password: "password",
+		Password: "password1",
 	}
 
 	tests := []struct {
@@ -433,7 +452,8 @@ func TestUpdateUserIDHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.Repository)
-			mockRepo.On("UpdateUserID", tt.userID, mock.Anything).Return(tt.mockErr)
+			mockRepo.On("GetUserID", tt.userID).Return(users.User{UID: tt.userID, Email: tt.user.Email}, tt.mockErr)
+			mockRepo.On("UpdateUserID", tt.userID, mock.Anything).Return(nil)
 			srv.repo = mockRepo
 
 			req := resty.New().R()
@@ -466,14 +486,14 @@ func BenchmarkLogin(b *testing.B) {
 	defer httpTest.Close()
 
 	uReq := users.UserRequest{
-		Email:    "email",
-		Password: "password",
+		Email:    "john@example.com",
+		Password: "password1",
 	}
 	dbUser := users.User{
 		UID:      "uuid-1234-55rr",
 		Name:     "John Doe",
-		Email:    "email",
-		Password: "password",
+		Email:    "john@example.com",
+		Password: "password1",
 	}
 
 	mockRepo := mocks.NewRepository(b)
@@ -511,8 +531,8 @@ func BenchmarkRegister(b *testing.B) {
 	uReq := users.User{
 		UID:      "uuid-1234-55rr",
 		Name:     "John Doe",
-		Email:    "email",
-		Password: "password",
+		Email:    "john@example.com",
+		Password: "password1",
 	}
 
 	mockRepo := mocks.NewRepository(b)
@@ -656,6 +676,7 @@ func BenchmarkUpdateUserID(b *testing.B) {
 	}
 
 	mockRepo := mocks.NewRepository(b)
+	mockRepo.On("GetUserID", "123").Return(users.User{UID: "123", Email: uReq.Email}, nil)
 	mockRepo.On("UpdateUserID", "123", mock.Anything).Return(nil)
 	srv.repo = mockRepo
 
@@ -672,3 +693,181 @@ func BenchmarkUpdateUserID(b *testing.B) {
 		req.Send()
 	}
 }
+
+func TestRegisterSendsVerification(t *testing.T) {
+	memMailer := mailer.NewMemory()
+	mockRepo := mocks.NewRepository(t)
+	srv := NotesAPI{repo: mockRepo, mailer: memMailer, cfg: &internal.Config{SigningKey: "signing"}, log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.POST("/register", srv.register)
+	testRouter.GET("/verify", srv.verifyEmail)
+
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	var saved users.User
+	mockRepo.On("SaveUser", mock.Anything).Run(func(args mock.Arguments) {
+		saved, _ = args.Get(0).(users.User)
+	}).Return(nil)
+	mockRepo.On("GetUserID", mock.Anything).Return(func(string) users.User { return saved }, nil)
+	mockRepo.On("VerifyUser", mock.Anything).Return(nil).Once()
+	mockRepo.On("SaveSession", mock.Anything).Return(nil).Once()
+
+	resp, err := resty.New().R().
+		SetBody(users.User{Name: "Bob", Email: "bob@example.com", Password: "password1", Verified: true}).
+		Post(httpTest.URL + "/register")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.False(t, saved.Verified)
+
+	messages := memMailer.Messages()
+	require.Len(t, messages, 1)
+	_, link, ok := strings.Cut(messages[0].Body, "/users/verify?token=")
+	require.True(t, ok)
+	token, err := url.QueryUnescape(strings.Fields(link)[0])
+	require.NoError(t, err)
+
+	resp, err = resty.New().R().SetQueryParam("token", token).Get(httpTest.URL + "/verify")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.String(), saved.UID)
+
+	resp, err = resty.New().R().SetQueryParam("token", token+"x").Get(httpTest.URL + "/verify")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+}
+
+func TestForgotPasswordDoesNotRevealEmail(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	lookups := make(chan struct{}, 2)
+	lookedUp := func(mock.Arguments) { lookups <- struct{}{} }
+	mockRepo.On("GetUser", "bob@example.com").Run(lookedUp).Return(users.User{UID: "uid-1", Email: "bob@example.com"}, nil)
+	mockRepo.On("GetUser", "nobody@example.com").Run(lookedUp).Return(users.User{}, users.ErrUserNotFound)
+	mockRepo.On("SaveResetToken", mock.Anything).Return(nil)
+
+	memMailer := mailer.NewMemory()
+	srv := NotesAPI{repo: mockRepo, mailer: memMailer, log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.POST("/password/forgot", srv.forgotPassword)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	var bodies []string
+	for _, email := range []string{"bob@example.com", "nobody@example.com"} {
+		resp, err := resty.New().R().
+			SetBody(users.ForgotPasswordRequest{Email: email}).
+			Post(httpTest.URL + "/password/forgot")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode())
+		bodies = append(bodies, resp.String())
+	}
+	assert.Equal(t, bodies[0], bodies[1])
+	<-lookups
+	<-lookups
+	assert.Eventually(t, func() bool { return len(memMailer.Messages()) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestJWTMiddlewareRejectsTokensBeforePasswordReset(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	srv := NotesAPI{repo: mockRepo, log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.GET("/private", srv.JWTMiddleware(), func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.GetString("uid"))
+	})
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	token, err := jwtToken("uid-1", "session-1")
+	require.NoError(t, err)
+
+	mockRepo.On("GetSession", "session-1").Return(users.Session{
+		ID: "session-1", UID: "uid-1", LastSeenAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour),
+	}, nil).Once()
+	mockRepo.On("GetUserID", "uid-1").Return(users.User{UID: "uid-1"}, nil).Once()
+	resp, err := resty.New().R().SetHeader("Authorization", token).Get(httpTest.URL + "/private")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	changed := users.User{UID: "uid-1", PasswordChangedAt: time.Now().Add(time.Hour)}
+	mockRepo.On("GetUserID", "uid-1").Return(changed, nil).Once()
+	resp, err = resty.New().R().SetHeader("Authorization", token).Get(httpTest.URL + "/private")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+}
+
+func TestLoginTooManyAttempts(t *testing.T) {
+	// Заблокированный аккаунт отсекается до обращения к хранилищу пользователей.
+	mockRepo := mocks.NewRepository(t)
+
+	attempts := inmemory.NewAttempts()
+	require.NoError(t, attempts.LockUntil("account:bob@example.com", time.Now().Add(90*time.Second)))
+	srv := NotesAPI{repo: mockRepo, attempts: attempts, log: zerolog.Nop()}
+
+	testRouter := gin.New()
+	testRouter.POST("/login", srv.login)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	resp, err := resty.New().R().
+		SetBody(users.UserRequest{Email: "bob@example.com", Password: "secret"}).
+		Post(httpTest.URL + "/login")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+	assert.Equal(t, "90", resp.Header().Get("Retry-After"))
+}
+
+func TestLoginWithTwoFactor(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com", Password: "secret"}))
+	srv := NotesAPI{repo: repo, cfg: &internal.Config{EncryptionKey: "key", SigningKey: "signing"}, log: zerolog.Nop()}
+
+	enrollment, err := srv.userService().EnrollTOTP("uid-1")
+	require.NoError(t, err)
+	_, err = srv.userService().ConfirmTOTP("uid-1", totpAt(t, enrollment.Secret, time.Now()))
+	require.NoError(t, err)
+	code := totpAt(t, enrollment.Secret, time.Now().Add(30*time.Second))
+
+	testRouter := gin.New()
+	testRouter.POST("/login", srv.login)
+	testRouter.POST("/login/2fa", srv.loginSecondFactor)
+	httpTest := httptest.NewServer(testRouter)
+	defer httpTest.Close()
+
+	var pending users.PendingLogin
+	resp, err := resty.New().R().
+		SetBody(users.UserRequest{Email: "bob@example.com", Password: "secret"}).
+		SetResult(&pending).
+		Post(httpTest.URL + "/login")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode())
+	assert.Empty(t, resp.Header().Get("Authorization"))
+	require.True(t, pending.TwoFactorRequired)
+
+	_, err = validateJwtToken(pending.PendingToken)
+	assert.Error(t, err, "pending token must not work as a JWT")
+
+	resp, err = resty.New().R().
+		SetBody(users.SecondFactorRequest{PendingToken: pending.PendingToken, Code: code}).
+		Post(httpTest.URL + "/login/2fa")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.NotEmpty(t, resp.Header().Get("Authorization"))
+}
+
+// totpAt считает код RFC 6238 на момент at так же, как приложение-аутентификатор.
+func totpAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1_000_000)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/gin-gonic/gin"
+)
+
+// apiV1 — префикс версионированного API.
+const apiV1 = "/api/v1"
+
+func noteResponses(notesSlice []notes.Note, loc *time.Location) []notes.NoteResponseFormat {
+	response := make([]notes.NoteResponseFormat, 0, len(notesSlice))
+	for _, n := range notesSlice {
+		response = append(response, notes.NoteResponse(n, loc))
+	}
+	return response
+}
+
+// abortNoteError отвечает на ошибку операции с заметкой подходящим статусом.
+func abortNoteError(ctx *gin.Context, err error) {
+	switch {
+	case abortValidation(ctx, err):
+	case errors.Is(err, notes.ErrNoteNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, notes.ErrNoteAlreadyExists):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (s *NotesAPI) listMyNotes(ctx *gin.Context) {
+	prefs := s.preferences(ctx)
+	sortBy, err := querySort(ctx, prefs)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	noteService := s.noteService()
+	notesList, err := noteService.ListUserNotes(ctx.GetString("uid"), sortBy)
+	if err != nil {
+		abortNoteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, noteResponses(notesList, prefs.Location()))
+}
+
+func (s *NotesAPI) getMyNote(ctx *gin.Context) {
+	noteService := s.noteService()
+	n, err := noteService.GetUserNote(ctx.GetString("uid"), ctx.Param("id"))
+	if err != nil {
+		abortNoteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notes.NoteResponse(n, s.preferences(ctx).Location()))
+}
+
+// createMyNote создаёт заметку текущего пользователя; без status берётся статус из настроек.
+func (s *NotesAPI) createMyNote(ctx *gin.Context) {
+	var patch notes.NotePatch
+	if err := ctx.BindJSON(&patch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+	prefs := s.preferences(ctx)
+
+	noteService := s.noteService()
+	n, err := noteService.CreateUserNote(ctx.GetString("uid"), patch.Apply(notes.Note{Status: prefs.DefaultStatus}))
+	if err != nil {
+		abortNoteError(ctx, err)
+		return
+	}
+	ctx.Header("Location", apiV1+"/notes/"+n.NID)
+	ctx.JSON(http.StatusCreated, notes.NoteResponse(n, prefs.Location()))
+}
+
+func (s *NotesAPI) replaceMyNote(ctx *gin.Context) {
+	var nReq notes.Note
+	if err := ctx.BindJSON(&nReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+
+	noteService := s.noteService()
+	n, err := noteService.ReplaceUserNote(ctx.GetString("uid"), ctx.Param("id"), nReq)
+	if err != nil {
+		abortNoteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notes.NoteResponse(n, s.preferences(ctx).Location()))
+}
+
+func (s *NotesAPI) patchMyNote(ctx *gin.Context) {
+	var patch notes.NotePatch
+	if err := ctx.BindJSON(&patch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+
+	noteService := s.noteService()
+	n, err := noteService.PatchUserNote(ctx.GetString("uid"), ctx.Param("id"), patch)
+	if err != nil {
+		abortNoteError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, notes.NoteResponse(n, s.preferences(ctx).Location()))
+}
+
+func (s *NotesAPI) deleteMyNote(ctx *gin.Context) {
+	noteService := s.noteService()
+	if err := noteService.DeleteUserNote(ctx.GetString("uid"), ctx.Param("id")); err != nil {
+		abortNoteError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (s *NotesAPI) getMe(ctx *gin.Context) {
+	userService := s.userService()
+	user, err := userService.GetUser(ctx.GetString("uid"))
+	if err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, users.NewProfile(user))
+}
+
+func (s *NotesAPI) updateMe(ctx *gin.Context) {
+	var patch users.ProfilePatch
+	if err := ctx.BindJSON(&patch); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+
+	userService := s.userService()
+	user, err := userService.UpdateProfile(ctx.GetString("uid"), patch)
+	if err != nil {
+		switch {
+		case abortValidation(ctx, err):
+		case errors.Is(err, users.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, users.ErrUserAlredyExists):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, users.NewProfile(user))
+}
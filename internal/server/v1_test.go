@@ -0,0 +1,252 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newV1TestServer(t *testing.T) (*httptest.Server, *inmemory.Notes) {
+	t.Helper()
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "test-user", Name: "Bob", Email: "bob@example.com"}))
+	repoNote := inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json"))
+
+	api := &NotesAPI{
+		httpServe: &http.Server{},
+		repo:      repo,
+		repoNote:  repoNote,
+		log:       zerolog.Nop(),
+		testMode:  true,
+	}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+	return ts, repoNote
+}
+
+// newV1Client отключает сжатие: в configRoutes подключено два gzip-middleware.
+func newV1Client(ts *httptest.Server) *resty.Client {
+	return resty.New().SetBaseURL(ts.URL+apiV1).SetHeader("Accept-Encoding", "identity")
+}
+
+func TestAPIV1Notes(t *testing.T) {
+	ts, repoNote := newV1TestServer(t)
+	require.NoError(t, repoNote.AddNote(notes.Note{NID: "foreign", Title: "Alice's", UID: "alice"}))
+	client := newV1Client(ts)
+
+	var created notes.NoteResponseFormat
+	resp, err := client.R().
+		SetBody(map[string]any{"title": "Buy milk", "priority": notes.High}).
+		SetResult(&created).
+		Post("/notes")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode())
+	assert.Equal(t, apiV1+"/notes/"+created.NID, resp.Header().Get("Location"))
+	assert.Equal(t, "New", created.Status)
+	assert.Equal(t, "High", created.Priority)
+	assert.Equal(t, "test-user", created.UID)
+
+	var patched notes.NoteResponseFormat
+	resp, err = client.R().
+		SetBody(map[string]any{"status": notes.Active}).
+		SetResult(&patched).
+		Patch("/notes/" + created.NID)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "Active", patched.Status)
+	assert.Equal(t, "Buy milk", patched.Title)
+	assert.Equal(t, "High", patched.Priority)
+
+	resp, err = client.R().SetBody(map[string]any{"title": " "}).Put("/notes/" + created.NID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode())
+
+	var list []notes.NoteResponseFormat
+	resp, err = client.R().SetResult(&list).Get("/notes")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Len(t, list, 1)
+	assert.Equal(t, created.NID, list[0].NID)
+
+	resp, err = client.R().Get("/notes/foreign")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+	resp, err = client.R().Delete("/notes/foreign")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+	resp, err = client.R().Delete("/notes/" + created.NID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode())
+	resp, err = client.R().Get("/notes/" + created.NID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+}
+
+func TestAPIV1Me(t *testing.T) {
+	ts, _ := newV1TestServer(t)
+	client := newV1Client(ts)
+
+	var profile users.Profile
+	resp, err := client.R().
+		SetBody(map[string]any{"name": "Robert"}).
+		SetResult(&profile).
+		Patch("/users/me")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "Robert", profile.Name)
+	assert.Equal(t, "bob@example.com", profile.Email)
+
+	resp, err = client.R().SetResult(&profile).Get("/users/me")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "test-user", profile.UID)
+	assert.NotContains(t, resp.String(), "password")
+}
+
+func TestLegacyRoutesDeprecated(t *testing.T) {
+	ts, _ := newV1TestServer(t)
+
+	resp, err := resty.New().R().Get(ts.URL + "/notes/list/42")
+	require.NoError(t, err)
+	assert.Equal(t, "@1792368000", resp.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", resp.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/notes/42>; rel="successor-version"`, resp.Header().Get("Link"))
+
+	resp, err = resty.New().R().Get(ts.URL + apiV1 + "/notes")
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header().Get("Deprecation"))
+}
+
+// newAuthTestServer поднимает все маршруты с настоящей проверкой токенов.
+func newAuthTestServer(t *testing.T) (*httptest.Server, *NotesAPI) {
+	t.Helper()
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com", Verified: true}))
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-2", Email: "alice@example.com", Verified: true}))
+
+	api := &NotesAPI{
+		httpServe: &http.Server{},
+		repo:      repo,
+		repoNote:  inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json")),
+		auditLog:  inmemory.NewAuditLog(100),
+		log:       zerolog.Nop(),
+	}
+	api.configRoutes()
+	ts := httptest.NewServer(api.httpServe.Handler)
+	t.Cleanup(ts.Close)
+	return ts, api
+}
+
+func sessionToken(t *testing.T, api *NotesAPI, uid string) string {
+	t.Helper()
+	session, err := api.userService().CreateSession(uid, "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	token, err := jwtToken(uid, session.ID)
+	require.NoError(t, err)
+	return token
+}
+
+func TestLegacyRoutesRequireAuth(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	session, err := api.userService().CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	token, err := jwtToken("uid-1", session.ID)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		want   int
+	}{
+		{name: "anonymous create", method: http.MethodPost, path: "/notes/add", want: http.StatusUnauthorized},
+		{name: "anonymous update", method: http.MethodPut, path: "/notes/upd/1", want: http.StatusUnauthorized},
+		{name: "anonymous delete", method: http.MethodDelete, path: "/notes/del/1", want: http.StatusUnauthorized},
+		{name: "anonymous get", method: http.MethodGet, path: "/notes/list/1", want: http.StatusUnauthorized},
+		{name: "anonymous move", method: http.MethodPost, path: "/notes/1/move", want: http.StatusUnauthorized},
+		{name: "anonymous bulk", method: http.MethodPost, path: "/notes/bulk", want: http.StatusUnauthorized},
+		{name: "anonymous import", method: http.MethodPost, path: "/notes/import", want: http.StatusUnauthorized},
+		{name: "anonymous user update", method: http.MethodPut, path: "/users/upd/uid-1", want: http.StatusUnauthorized},
+		{name: "anonymous user delete", method: http.MethodDelete, path: "/users/del/uid-1", want: http.StatusUnauthorized},
+		{name: "foreign user update", method: http.MethodPut, path: "/users/upd/uid-2", auth: token, want: http.StatusForbidden},
+		{name: "foreign user delete", method: http.MethodDelete, path: "/users/del/uid-2", auth: token, want: http.StatusForbidden},
+		{name: "anonymous profiles", method: http.MethodGet, path: "/users/profile", want: http.StatusUnauthorized},
+		{
+			name: "anonymous profile", method: http.MethodGet, path: "/users/profile/uid-1",
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "profiles without admin", method: http.MethodGet, path: "/users/profile", auth: token,
+			want: http.StatusForbidden,
+		},
+		{
+			name: "foreign profile", method: http.MethodGet, path: "/users/profile/uid-2", auth: token,
+			want: http.StatusForbidden,
+		},
+		{name: "own profile", method: http.MethodGet, path: "/users/profile/uid-1", auth: token, want: http.StatusOK},
+		{
+			name: "own note create", method: http.MethodPost, path: "/notes/add", auth: token,
+			want: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := resty.New().R().SetBody(notes.Note{NID: "1", Title: "Note"})
+			if tc.auth != `` {
+				req.SetHeader("Authorization", tc.auth)
+			}
+			resp, err := req.Execute(tc.method, ts.URL+tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, resp.StatusCode())
+		})
+	}
+}
+
+func TestLegacyNotesBelongToCaller(t *testing.T) {
+	ts, api := newAuthTestServer(t)
+	bob := sessionToken(t, api, "uid-1")
+	alice := sessionToken(t, api, "uid-2")
+	client := resty.New().SetBaseURL(ts.URL)
+
+	resp, err := client.R().SetHeader("Authorization", bob).
+		SetBody(notes.Note{Title: "Legacy"}).
+		Post("/notes/add")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode())
+
+	owned, err := api.noteService().ListUserNotes("uid-1", notes.SortDefault)
+	require.NoError(t, err)
+	require.Len(t, owned, 1)
+	noteID := owned[0].NID
+
+	resp, err = client.R().SetHeader("Authorization", alice).
+		SetBody(notes.Note{Title: "Stolen"}).
+		Put("/notes/upd/" + noteID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	resp, err = client.R().SetHeader("Authorization", bob).
+		SetBody(notes.Note{Title: "Renamed"}).
+		Put("/notes/upd/" + noteID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	stored, err := api.noteService().GetUserNote("uid-1", noteID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", stored.Title)
+	assert.Equal(t, owned[0].CreatedAt, stored.CreatedAt)
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// abortValidation отвечает 422 со списком нарушенных правил, если err — ошибка валидации.
+func abortValidation(ctx *gin.Context, err error) bool {
+	var fieldErrs validation.Errors
+	if !errors.As(err, &fieldErrs) {
+		return false
+	}
+	ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "fields": fieldErrs})
+	return true
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	"github.com/Snoop-Duck/ToDoList/internal/services/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+// WithWebhooks включает /webhooks и доставку изменений заметок по подпискам.
+// Очередь доставок разбирает webhook.Service.Run, его запускает вызывающий.
+func WithWebhooks(service *webhook.Service) Option {
+	return func(nApi *NotesAPI) {
+		nApi.webhooks = service
+	}
+}
+
+// WebhooksConfigured отвечает 503, если сервер запущен без вебхуков.
+func (nApi *NotesAPI) WebhooksConfigured() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if nApi.webhooks == nil {
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "webhooks are not configured"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func (s *NotesAPI) createWebhook(ctx *gin.Context) {
+	var req webhooks.WebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := s.webhooks.CreateWebhook(ctx.GetString("uid"), req)
+	if err != nil {
+		if abortValidation(ctx, err) {
+			return
+		}
+		if errors.Is(err, webhooks.ErrTooManyWebhooks) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Header("Location", "/webhooks/"+created.ID)
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (s *NotesAPI) listWebhooks(ctx *gin.Context) {
+	hooks, err := s.webhooks.ListWebhooks(ctx.GetString("uid"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, hooks)
+}
+
+func (s *NotesAPI) getWebhook(ctx *gin.Context) {
+	hook, err := s.webhooks.GetWebhook(ctx.GetString("uid"), ctx.Param("id"))
+	if err != nil {
+		abortWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, hook)
+}
+
+func (s *NotesAPI) deleteWebhook(ctx *gin.Context) {
+	if err := s.webhooks.DeleteWebhook(ctx.GetString("uid"), ctx.Param("id")); err != nil {
+		abortWebhookError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (s *NotesAPI) listDeliveries(ctx *gin.Context) {
+	var filter webhooks.DeliveryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deliveries, err := s.webhooks.ListDeliveries(ctx.GetString("uid"), ctx.Param("id"), filter)
+	if err != nil {
+		abortWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (s *NotesAPI) listDeadLetters(ctx *gin.Context) {
+	var filter webhooks.DeliveryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deliveries, err := s.webhooks.ListDeadLetters(ctx.GetString("uid"), filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (s *NotesAPI) retryDelivery(ctx *gin.Context) {
+	delivery, err := s.webhooks.RetryDelivery(ctx.GetString("uid"), ctx.Param("id"))
+	if err != nil {
+		abortWebhookError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+func abortWebhookError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhooks.ErrWebhookNotFound), errors.Is(err, webhooks.ErrDeliveryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, webhooks.ErrDeliveryNotDead):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
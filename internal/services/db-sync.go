@@ -3,8 +3,10 @@ package services
 import (
 	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/metrics"
 	"gorm.io/gorm"
 )
 
@@ -21,20 +23,30 @@ func New(db *gorm.DB, filePath string) *SyncDB {
 }
 
 func (s *SyncDB) SyncToDB() error {
+	start := time.Now()
+	synced, failed, err := s.syncToDB()
+	metrics.ObserveSync(start, synced, failed, err)
+	return err
+}
+
+func (s *SyncDB) syncToDB() (int, int, error) {
 	data, err := os.ReadFile(s.notesFile)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	var fileNotes map[string]notes.Note
 	if err = json.Unmarshal(data, &fileNotes); err != nil {
-		return err
+		return 0, 0, err
 	}
 
+	synced, failed := 0, 0
 	for _, note := range fileNotes {
 		if err = s.db.Create(&note).Error; err != nil {
+			failed++
 			continue
 		}
+		synced++
 	}
-	return os.WriteFile(s.notesFile, []byte("{}"), 0600)
+	return synced, failed, os.WriteFile(s.notesFile, []byte("{}"), 0600)
 }
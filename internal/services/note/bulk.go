@@ -0,0 +1,145 @@
+package note
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+
+	"github.com/google/uuid"
+)
+
+// ApplyBulk применяет пакет операций над заметками владельца. Операция над чужой
+// заметкой завершается так же, как над отсутствующей.
+func (ns *Service) ApplyBulk(uid string, req notes.BulkRequest) (notes.BulkResponse, error) {
+	mode, err := notes.ParseBulkMode(string(req.Mode))
+	if err != nil {
+		return notes.BulkResponse{}, err
+	}
+	if len(req.Operations) == 0 {
+		return notes.BulkResponse{}, notes.ErrBulkEmpty
+	}
+	if len(req.Operations) > notes.MaxBulkOperations {
+		return notes.BulkResponse{}, notes.ErrBulkTooLarge
+	}
+
+	response := notes.BulkResponse{Mode: mode, Results: make([]notes.BulkResult, len(req.Operations))}
+	ops := make([]notes.BulkOperation, 0, len(req.Operations))
+	indexes := make([]int, 0, len(req.Operations))
+	before := make(map[string]notes.Note, len(req.Operations))
+	now := time.Now()
+	for i, op := range req.Operations {
+		prepared, stored, prepareErr := ns.prepareBulkOperation(uid, op, now.Add(time.Duration(i)))
+		response.Results[i] = notes.BulkResult{Index: i, Action: op.Action, NID: prepared.NID}
+		if prepareErr != nil {
+			response.Results[i].Error = prepareErr.Error()
+			continue
+		}
+		if prepared.Action != notes.BulkCreate {
+			before[prepared.NID] = stored
+		}
+		ops = append(ops, prepared)
+		indexes = append(indexes, i)
+	}
+
+	if mode == notes.BulkAtomic && len(ops) != len(req.Operations) {
+		err = notes.ErrBulkFailed
+	} else if len(ops) > 0 {
+		var results []notes.BulkResult
+		results, err = ns.repo.ApplyBulk(ops, mode)
+		if err != nil && !errors.Is(err, notes.ErrBulkFailed) {
+			return notes.BulkResponse{}, err
+		}
+		for j, result := range results {
+			response.Results[indexes[j]].Error = result.Error
+		}
+		if err == nil || mode == notes.BulkBestEffort {
+			ns.publishBulk(ops, results, before)
+		}
+	}
+
+	response.Applied = err == nil
+	for _, result := range response.Results {
+		switch {
+		case result.Error != ``:
+			response.Failed++
+		case response.Applied:
+			response.Succeeded++
+		}
+	}
+	return response, err
+}
+
+// prepareBulkOperation дополняет операцию так же, как это делают одиночные методы сервиса,
+// и возвращает изменяемую заметку владельца в том виде, в каком она хранилась до пакета.
+func (ns *Service) prepareBulkOperation(
+	uid string,
+	op notes.BulkOperation,
+	now time.Time,
+) (notes.BulkOperation, notes.Note, error) {
+	switch op.Action {
+	case notes.BulkCreate, notes.BulkUpdate:
+		if err := validation.Struct(op.Note); err != nil {
+			return op, notes.Note{}, err
+		}
+	}
+
+	switch op.Action {
+	case notes.BulkCreate:
+		op.Note.NID = uuid.New().String()
+		op.Note.Position = notes.InitialRank(now)
+		op.Note.CreatedAt = now.UTC()
+		op.Note.UID = uid
+		op.NID = op.Note.NID
+		return op, notes.Note{}, nil
+	case notes.BulkStatus:
+		status := notes.ParseStatus(op.Status)
+		if !status.Valid() {
+			return op, notes.Note{}, notes.ErrInvalidStatus
+		}
+		op.Note.Status = status
+	case notes.BulkUpdate, notes.BulkDelete:
+	default:
+		return op, notes.Note{}, notes.ErrInvalidBulkAction
+	}
+
+	if op.NID == `` {
+		return op, notes.Note{}, notes.ErrNoteNotFound
+	}
+	stored, err := ns.GetUserNote(uid, op.NID)
+	if err != nil {
+		return op, notes.Note{}, err
+	}
+
+	if op.Action == notes.BulkUpdate {
+		// Обновление меняет только редактируемые поля, остальное берётся из хранилища.
+		op.Note = notes.NotePatch{
+			Title:       &op.Note.Title,
+			Description: &op.Note.Description,
+			Status:      &op.Note.Status,
+			Priority:    &op.Note.Priority,
+		}.Apply(stored)
+	}
+	return op, stored, nil
+}
+
+// publishBulk публикует события успешно применённых операций.
+func (ns *Service) publishBulk(ops []notes.BulkOperation, results []notes.BulkResult, before map[string]notes.Note) {
+	if len(ns.publishers) == 0 {
+		return
+	}
+	for j, op := range ops {
+		if j < len(results) && results[j].Error != `` {
+			continue
+		}
+		switch op.Action {
+		case notes.BulkCreate:
+			ns.publish(notes.Event{Type: notes.EventCreated, NID: op.NID, UID: op.Note.UID, Note: op.Note})
+		case notes.BulkDelete:
+			ns.publishDeleted(op.NID, before[op.NID])
+		default:
+			ns.publishChange(op.NID, before[op.NID])
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package note
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteService_ApplyBulk(t *testing.T) {
+	t.Run("best effort", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "2").Return(notes.Note{NID: "2", UID: "uid-1"}, nil)
+		mockRepo.On("ApplyBulk", mock.MatchedBy(func(ops []notes.BulkOperation) bool {
+			return len(ops) == 2 &&
+				ops[0].Note.NID != `` && ops[0].Note.Position != `` &&
+				ops[0].Note.UID == "uid-1" && !ops[0].Note.CreatedAt.IsZero() &&
+				ops[1].NID == "2" && ops[1].Note.Status == notes.Inactive
+		}), notes.BulkBestEffort).Return([]notes.BulkResult{
+			{Index: 0, Action: notes.BulkCreate},
+			{Index: 1, Action: notes.BulkStatus, NID: "2", Error: "note not found"},
+		}, nil)
+
+		response, err := service.ApplyBulk("uid-1", notes.BulkRequest{
+			Mode: notes.BulkBestEffort,
+			Operations: []notes.BulkOperation{
+				{Action: notes.BulkCreate, Note: notes.Note{Title: "New"}},
+				{Action: "archive", NID: "1"},
+				{Action: notes.BulkStatus, NID: "2", Status: "Inactive"},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, response.Applied)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, 2, response.Failed)
+		assert.NotEmpty(t, response.Results[0].NID)
+		assert.Equal(t, notes.ErrInvalidBulkAction.Error(), response.Results[1].Error)
+		assert.Equal(t, "note not found", response.Results[2].Error)
+	})
+
+	t.Run("create uses note validation rules", func(t *testing.T) {
+		service := New(mocks.NewRepositoryNote(t))
+
+		response, err := service.ApplyBulk("uid-1", notes.BulkRequest{
+			Operations: []notes.BulkOperation{
+				{Action: notes.BulkCreate, Note: notes.Note{Title: "Mine", UID: "someone-else"}},
+			},
+		})
+
+		assert.ErrorIs(t, err, notes.ErrBulkFailed)
+		assert.Contains(t, response.Results[0].Error, "uid")
+	})
+
+	t.Run("atomic rejects invalid batch without applying it", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", UID: "uid-1"}, nil)
+
+		response, err := service.ApplyBulk("uid-1", notes.BulkRequest{
+			Operations: []notes.BulkOperation{
+				{Action: notes.BulkDelete, NID: "1"},
+				{Action: notes.BulkStatus, NID: "2", Status: "Done"},
+			},
+		})
+
+		assert.ErrorIs(t, err, notes.ErrBulkFailed)
+		assert.False(t, response.Applied)
+		assert.Equal(t, notes.BulkAtomic, response.Mode)
+		assert.Equal(t, 1, response.Failed)
+		assert.Zero(t, response.Succeeded)
+	})
+
+	t.Run("foreign notes fail as not found", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", UID: "uid-1", Title: "Mine"}, nil)
+		mockRepo.On("GetNoteID", "2").Return(notes.Note{NID: "2", UID: "uid-2"}, nil)
+		mockRepo.On("GetNoteID", "3").Return(notes.Note{NID: "3", UID: "uid-2"}, nil)
+		mockRepo.On("GetNoteID", "4").Return(notes.Note{NID: "4", UID: "uid-2"}, nil)
+		mockRepo.On("ApplyBulk", mock.MatchedBy(func(ops []notes.BulkOperation) bool {
+			return len(ops) == 1 && ops[0].NID == "1"
+		}), notes.BulkBestEffort).Return([]notes.BulkResult{{Index: 0, Action: notes.BulkDelete, NID: "1"}}, nil)
+
+		response, err := service.ApplyBulk("uid-1", notes.BulkRequest{
+			Mode: notes.BulkBestEffort,
+			Operations: []notes.BulkOperation{
+				{Action: notes.BulkDelete, NID: "1"},
+				{Action: notes.BulkDelete, NID: "2"},
+				{Action: notes.BulkStatus, NID: "3", Status: "Active"},
+				{Action: notes.BulkUpdate, NID: "4", Note: notes.Note{Title: "Stolen"}},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, 3, response.Failed)
+		for _, result := range response.Results[1:] {
+			assert.Equal(t, notes.ErrNoteNotFound.Error(), result.Error)
+		}
+	})
+
+	t.Run("update keeps stored fields", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		stored := notes.Note{
+			NID: "1", UID: "uid-1", Title: "Old", Position: "m", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		mockRepo.On("GetNoteID", "1").Return(stored, nil)
+		mockRepo.On("ApplyBulk", mock.MatchedBy(func(ops []notes.BulkOperation) bool {
+			note := ops[0].Note
+			return note.Title == "New" && note.NID == "1" && note.UID == "uid-1" &&
+				note.Position == "m" && note.CreatedAt.Equal(stored.CreatedAt)
+		}), notes.BulkAtomic).Return([]notes.BulkResult{{Index: 0, Action: notes.BulkUpdate, NID: "1"}}, nil)
+
+		response, err := service.ApplyBulk("uid-1", notes.BulkRequest{
+			Operations: []notes.BulkOperation{
+				{Action: notes.BulkUpdate, NID: "1", Note: notes.Note{Title: "New"}},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		service := New(mocks.NewRepositoryNote(t))
+
+		_, err := service.ApplyBulk("uid-1", notes.BulkRequest{})
+		assert.ErrorIs(t, err, notes.ErrBulkEmpty)
+
+		_, err = service.ApplyBulk("uid-1", notes.BulkRequest{Mode: "partial", Operations: []notes.BulkOperation{{}}})
+		assert.ErrorIs(t, err, notes.ErrInvalidBulkMode)
+
+		_, err = service.ApplyBulk("uid-1", notes.BulkRequest{
+			Operations: make([]notes.BulkOperation, notes.MaxBulkOperations+1),
+		})
+		assert.ErrorIs(t, err, notes.ErrBulkTooLarge)
+	})
+}
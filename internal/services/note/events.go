@@ -0,0 +1,54 @@
+package note
+
+import (
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+// Publisher получает события об изменении заметок. Publish вызывается в запросе,
+// изменившем заметку, поэтому не должен ждать медленных получателей.
+type Publisher interface {
+	Publish(event notes.Event)
+}
+
+// WithPublisher включает публикацию событий создания, изменения и удаления заметок.
+// Опцию можно передать несколько раз: событие получит каждый издатель.
+func WithPublisher(publisher Publisher) Option {
+	return func(ns *Service) {
+		ns.publishers = append(ns.publishers, publisher)
+	}
+}
+
+func (ns *Service) publish(event notes.Event) {
+	event.At = time.Now().UTC()
+	for _, publisher := range ns.publishers {
+		publisher.Publish(event)
+	}
+}
+
+// snapshot читает заметку до изменения, чтобы событие знало владельца и прежний статус.
+// Без издателя чтение не нужно и пропускается.
+func (ns *Service) snapshot(noteID string) (notes.Note, bool) {
+	if len(ns.publishers) == 0 {
+		return notes.Note{}, false
+	}
+	note, err := ns.repo.GetNoteID(noteID)
+	return note, err == nil
+}
+
+// publishChange публикует изменение заметки, перечитав её после записи в хранилище.
+func (ns *Service) publishChange(noteID string, before notes.Note) {
+	if len(ns.publishers) == 0 {
+		return
+	}
+	after, err := ns.repo.GetNoteID(noteID)
+	if err != nil {
+		return
+	}
+	ns.publish(notes.ChangeEvent(before, after))
+}
+
+func (ns *Service) publishDeleted(noteID string, before notes.Note) {
+	ns.publish(notes.Event{Type: notes.EventDeleted, NID: noteID, UID: before.UID})
+}
@@ -0,0 +1,79 @@
+package note
+
+import (
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	events []notes.Event
+}
+
+func (r *recorder) Publish(event notes.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestNoteService_PublishesEvents(t *testing.T) {
+	t.Run("board move changes status", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		events := &recorder{}
+		service := New(mockRepo, WithPublisher(events))
+
+		mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", Status: notes.New, UID: "alice"}, nil).Once()
+		mockRepo.On("MoveNoteToStatus", "1", notes.ParseStatus("Active"), notes.MoveRequest{}).Return("m", nil)
+		mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", Status: notes.Active, UID: "alice"}, nil).Once()
+
+		_, err := service.MoveOnBoard("alice", "1", notes.BoardMoveRequest{Status: "Active"})
+		require.NoError(t, err)
+		require.Len(t, events.events, 1)
+		assert.Equal(t, notes.EventStatusChanged, events.events[0].Type)
+		assert.Equal(t, "New", events.events[0].PrevStatus.String())
+		assert.Equal(t, "alice", events.events[0].UID)
+		assert.False(t, events.events[0].At.IsZero())
+	})
+
+	t.Run("failed owner check publishes nothing", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		events := &recorder{}
+		service := New(mockRepo, WithPublisher(events))
+
+		mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", UID: "bob"}, nil)
+
+		require.ErrorIs(t, service.DeleteUserNote("alice", "1"), notes.ErrNoteNotFound)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("bulk publishes applied operations only", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		events := &recorder{}
+		service := New(mockRepo, WithPublisher(events))
+
+		mockRepo.On("GetNoteID", "2").Return(notes.Note{NID: "2", UID: "alice"}, nil)
+		mockRepo.On("GetNoteID", "3").Return(notes.Note{NID: "3", UID: "alice"}, nil)
+		mockRepo.On("ApplyBulk", mock.Anything, notes.BulkBestEffort).Return([]notes.BulkResult{
+			{Index: 0, Action: notes.BulkCreate},
+			{Index: 1, Action: notes.BulkDelete, NID: "2"},
+			{Index: 2, Action: notes.BulkDelete, NID: "3", Error: "note not found"},
+		}, nil)
+
+		_, err := service.ApplyBulk("alice", notes.BulkRequest{
+			Mode: notes.BulkBestEffort,
+			Operations: []notes.BulkOperation{
+				{Action: notes.BulkCreate, Note: notes.Note{Title: "New"}},
+				{Action: notes.BulkDelete, NID: "2"},
+				{Action: notes.BulkDelete, NID: "3"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, events.events, 2)
+		assert.Equal(t, notes.EventCreated, events.events[0].Type)
+		assert.Equal(t, "alice", events.events[0].UID)
+		assert.Equal(t, notes.EventDeleted, events.events[1].Type)
+		assert.Equal(t, "alice", events.events[1].UID)
+	})
+}
@@ -0,0 +1,269 @@
+package note
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+)
+
+const (
+	icsTimeFormat = "20060102T150405Z"
+	icsLineLimit  = 75
+)
+
+// exporter пишет заметки в w по мере их чтения из репозитория.
+type exporter interface {
+	begin() error
+	write(note notes.Note) error
+	end() error
+}
+
+// ExportNotes выгружает заметки пользователя; время в CSV и JSON выводится в поясе loc,
+// в ICS — всегда в UTC.
+func (ns *Service) ExportNotes(uid string, format notes.ExportFormat, loc *time.Location, w io.Writer) error {
+	exp, err := newExporter(format, w, time.Now().In(loc), loc)
+	if err != nil {
+		return err
+	}
+
+	if err = exp.begin(); err != nil {
+		return err
+	}
+	if err = ns.repo.StreamNotes(uid, exp.write); err != nil {
+		return err
+	}
+	return exp.end()
+}
+
+func newExporter(format notes.ExportFormat, w io.Writer, now time.Time, loc *time.Location) (exporter, error) {
+	switch format {
+	case notes.ExportCSV:
+		return &csvExporter{w: csv.NewWriter(w), loc: loc}, nil
+	case notes.ExportMarkdown:
+		return &markdownExporter{w: w}, nil
+	case notes.ExportICS:
+		return &icsExporter{w: w, now: now.UTC()}, nil
+	case notes.ExportJSON:
+		return &jsonExporter{w: w, now: now, loc: loc}, nil
+	default:
+		return nil, notes.ErrInvalidExportFormat
+	}
+}
+
+type csvExporter struct {
+	w   *csv.Writer
+	loc *time.Location
+}
+
+func (e *csvExporter) begin() error {
+	return e.w.Write([]string{
+		notes.FieldTitle, notes.FieldDescription, notes.FieldStatus, notes.FieldPriority,
+		"position", "created_at", "nid",
+	})
+}
+
+func (e *csvExporter) write(note notes.Note) error {
+	return e.w.Write([]string{
+		note.Title, note.Description, statusName(note.Status), priorityName(note.Priority),
+		note.Position, note.CreatedAt.In(e.loc).Format(time.RFC3339), note.NID,
+	})
+}
+
+func (e *csvExporter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type markdownExporter struct {
+	w io.Writer
+}
+
+func (e *markdownExporter) begin() error {
+	_, err := io.WriteString(e.w, "# Notes\n\n")
+	return err
+}
+
+func (e *markdownExporter) write(note notes.Note) error {
+	box := " "
+	if note.Status == notes.Inactive || note.Status == notes.Deleted {
+		box = "x"
+	}
+	title := strings.Join(strings.Fields(note.Title), " ")
+	if _, err := fmt.Fprintf(e.w, "- [%s] %s\n", box, title); err != nil {
+		return err
+	}
+	if note.Description == `` {
+		return nil
+	}
+	for _, line := range strings.Split(note.Description, "\n") {
+		if _, err := fmt.Fprintf(e.w, "  > %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *markdownExporter) end() error {
+	return nil
+}
+
+type icsExporter struct {
+	w   io.Writer
+	now time.Time
+}
+
+func (e *icsExporter) begin() error {
+	return e.lines("BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Snoop-Duck//ToDoList//EN")
+}
+
+func (e *icsExporter) write(note notes.Note) error {
+	lines := []string{
+		"BEGIN:VTODO",
+		"UID:" + note.NID + "@todolist",
+		"DTSTAMP:" + e.now.Format(icsTimeFormat),
+	}
+	if !note.CreatedAt.IsZero() {
+		lines = append(lines, "CREATED:"+note.CreatedAt.UTC().Format(icsTimeFormat))
+	}
+	lines = append(lines, "SUMMARY:"+icsEscape(note.Title))
+	if note.Description != `` {
+		lines = append(lines, "DESCRIPTION:"+icsEscape(note.Description))
+	}
+	lines = append(lines,
+		"STATUS:"+icsStatus(note.Status),
+		"PRIORITY:"+strconv.Itoa(icsPriority(note.Priority)),
+		"END:VTODO",
+	)
+	return e.lines(lines...)
+}
+
+func (e *icsExporter) end() error {
+	return e.lines("END:VCALENDAR")
+}
+
+func (e *icsExporter) lines(lines ...string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(e.w, icsFold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonExporter struct {
+	w       io.Writer
+	now     time.Time
+	loc     *time.Location
+	written bool
+}
+
+// begin пишет поля notes.ExportDocument, сами заметки дописываются в массив по одной.
+func (e *jsonExporter) begin() error {
+	exportedAt, err := json.Marshal(e.now)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, `{"version":%d,"exported_at":%s,"notes":[`, notes.ExportVersion, exportedAt)
+	return err
+}
+
+func (e *jsonExporter) write(note notes.Note) error {
+	note.CreatedAt = note.CreatedAt.In(e.loc)
+	data, err := json.Marshal(note)
+	if err != nil {
+		return err
+	}
+	if e.written {
+		if _, err = io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.written = true
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExporter) end() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+func statusName(status notes.Status) string {
+	if !status.Valid() {
+		return ``
+	}
+	return status.String()
+}
+
+func priorityName(priority notes.Priority) string {
+	if !priority.Valid() {
+		return ``
+	}
+	return priority.String()
+}
+
+func icsStatus(status notes.Status) string {
+	switch status {
+	case notes.Active:
+		return "IN-PROCESS"
+	case notes.Inactive:
+		return "COMPLETED"
+	case notes.Deleted:
+		return "CANCELLED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// icsPriority переводит приоритет в шкалу RFC 5545, где 1 - самый высокий.
+func icsPriority(priority notes.Priority) int {
+	switch priority {
+	case notes.Urgent:
+		return 1
+	case notes.High:
+		return 3 //nolint:mnd // RFC 5545
+	case notes.Medium:
+		return 5 //nolint:mnd // RFC 5545
+	default:
+		return 9 //nolint:mnd // RFC 5545
+	}
+}
+
+func icsEscape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// icsFold переносит строки длиннее 75 октетов, не разрывая символы UTF-8.
+func icsFold(line string) string {
+	if len(line) <= icsLineLimit {
+		return line
+	}
+
+	var folded strings.Builder
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsLineLimit - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
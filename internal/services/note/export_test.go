@@ -0,0 +1,130 @@
+package note
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func streamMock(t *testing.T, uid string, notesSlice []notes.Note) *mocks.RepositoryNote {
+	t.Helper()
+	mockRepo := mocks.NewRepositoryNote(t)
+	mockRepo.On("StreamNotes", uid, mock.Anything).Return(func(_ string, fn func(notes.Note) error) error {
+		for _, note := range notesSlice {
+			if err := fn(note); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return mockRepo
+}
+
+func TestNoteService_ExportNotes(t *testing.T) {
+	created := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	testNotes := []notes.Note{
+		{NID: "1", Title: "Buy milk, bread", Description: "2l\nfresh", Status: notes.Active,
+			Priority: notes.High, Position: "a", CreatedAt: created, UID: "user1"},
+		{NID: "2", Title: "Send invoice", Status: notes.Inactive, Position: "b", CreatedAt: created, UID: "user1"},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := New(streamMock(t, "user1", testNotes)).ExportNotes("user1", notes.ExportCSV, time.UTC, &buf)
+
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Equal(t, "title,description,status,priority,position,created_at,nid", lines[0])
+		assert.Contains(t, buf.String(), `"Buy milk, bread","2l`)
+		assert.Contains(t, buf.String(), "Send invoice,,Inactive,Low,b,2025-05-01T10:00:00Z,2")
+	})
+
+	t.Run("csv in user timezone", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Moscow")
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		err = New(streamMock(t, "user1", testNotes)).ExportNotes("user1", notes.ExportCSV, loc, &buf)
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "Send invoice,,Inactive,Low,b,2025-05-01T13:00:00+03:00,2")
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := New(streamMock(t, "user1", testNotes)).ExportNotes("user1", notes.ExportMarkdown, time.UTC, &buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, "# Notes\n\n- [ ] Buy milk, bread\n  > 2l\n  > fresh\n- [x] Send invoice\n", buf.String())
+	})
+
+	t.Run("ics", func(t *testing.T) {
+		long := notes.Note{NID: "3", Title: strings.Repeat("я", 60), Status: notes.Deleted, Priority: notes.Urgent}
+		var buf bytes.Buffer
+		err := New(streamMock(t, "user1", append(testNotes, long))).ExportNotes("user1", notes.ExportICS, time.UTC, &buf)
+
+		require.NoError(t, err)
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+		assert.Equal(t, 3, strings.Count(out, "BEGIN:VTODO"))
+		assert.Contains(t, out, "SUMMARY:Buy milk\\, bread\r\n")
+		assert.Contains(t, out, "DESCRIPTION:2l\\nfresh\r\n")
+		assert.Contains(t, out, "STATUS:IN-PROCESS\r\nPRIORITY:3\r\n")
+		assert.Contains(t, out, "STATUS:COMPLETED\r\nPRIORITY:9\r\n")
+		assert.Contains(t, out, "STATUS:CANCELLED\r\nPRIORITY:1\r\n")
+		assert.Contains(t, out, "CREATED:20250501T100000Z\r\n")
+		for _, line := range strings.Split(out, "\r\n") {
+			assert.LessOrEqual(t, len(line), icsLineLimit)
+		}
+	})
+
+	t.Run("json round-trips through import", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := New(streamMock(t, "user1", testNotes)).ExportNotes("user1", notes.ExportJSON, time.UTC, &buf)
+		require.NoError(t, err)
+
+		var document notes.ExportDocument
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+		assert.Equal(t, notes.ExportVersion, document.Version)
+		assert.Len(t, document.Notes, 2)
+
+		importRepo := mocks.NewRepositoryNote(t)
+		importRepo.On("StreamNotes", "user1", mock.Anything).Return(nil)
+		report, err := New(importRepo).ImportNotes(&buf, notes.ImportOptions{
+			UID:    "user1",
+			Format: notes.ImportJSON,
+			DryRun: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, "Active", report.Items[0].Status)
+		assert.Equal(t, "Inactive", report.Items[1].Status)
+	})
+
+	t.Run("empty json export", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := New(streamMock(t, "user2", nil)).ExportNotes("user2", notes.ExportJSON, time.UTC, &buf)
+
+		require.NoError(t, err)
+		var document notes.ExportDocument
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+		assert.Empty(t, document.Notes)
+	})
+
+	t.Run("unsupported import version", func(t *testing.T) {
+		importRepo := mocks.NewRepositoryNote(t)
+		_, err := New(importRepo).ImportNotes(strings.NewReader(`{"version":99,"notes":[]}`),
+			notes.ImportOptions{Format: notes.ImportJSON})
+
+		assert.ErrorIs(t, err, notes.ErrInvalidImport)
+	})
+}
@@ -0,0 +1,262 @@
+package note
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+)
+
+var checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.+?)\s*$`) //nolint:gochecknoglobals // its ok
+
+type importEntry struct {
+	line int
+	note notes.Note
+	err  error
+}
+
+func (ns *Service) ImportNotes(r io.Reader, opts notes.ImportOptions) (notes.ImportReport, error) {
+	entries, err := parseImport(r, opts)
+	if err != nil {
+		return notes.ImportReport{}, err
+	}
+	for i := range entries {
+		if entries[i].err == nil {
+			entries[i].err = validation.Struct(entries[i].note)
+		}
+	}
+
+	titles, err := ns.existingTitles(opts.UID)
+	if err != nil {
+		return notes.ImportReport{}, err
+	}
+
+	report := notes.ImportReport{Format: opts.Format, DryRun: opts.DryRun, Items: make([]notes.ImportItem, 0, len(entries))}
+	var (
+		ops     []notes.BulkOperation
+		indexes []int
+	)
+	for _, entry := range entries {
+		item := notes.ImportItem{Line: entry.line, Title: entry.note.Title}
+		if entry.note.Status.Valid() {
+			item.Status = entry.note.Status.String()
+		}
+
+		switch {
+		case entry.err != nil:
+			item.Error = entry.err.Error()
+			report.Failed++
+		case titles[entry.note.Title]:
+			item.Duplicate = true
+			item.Error = notes.ErrNoteAlreadyExists.Error()
+			report.Duplicates++
+		default:
+			titles[entry.note.Title] = true
+			ops = append(ops, notes.BulkOperation{Action: notes.BulkCreate, Note: entry.note})
+			indexes = append(indexes, len(report.Items))
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	if opts.DryRun {
+		report.Created = len(ops)
+		return report, nil
+	}
+
+	for start := 0; start < len(ops); start += notes.MaxBulkOperations {
+		end := min(start+notes.MaxBulkOperations, len(ops))
+		response, bulkErr := ns.ApplyBulk(opts.UID, notes.BulkRequest{
+			Mode:       notes.BulkBestEffort,
+			Operations: ops[start:end],
+		})
+		if bulkErr != nil {
+			return notes.ImportReport{}, bulkErr
+		}
+		for i, result := range response.Results {
+			item := &report.Items[indexes[start+i]]
+			switch {
+			case result.Error == notes.ErrNoteAlreadyExists.Error():
+				item.Duplicate = true
+				item.Error = result.Error
+				report.Duplicates++
+			case result.Error != ``:
+				item.Error = result.Error
+				report.Failed++
+			default:
+				item.NID = result.NID
+				report.Created++
+			}
+		}
+	}
+	return report, nil
+}
+
+func (ns *Service) existingTitles(uid string) (map[string]bool, error) {
+	titles := make(map[string]bool)
+	err := ns.StreamUserNotes(uid, func(note notes.Note) error {
+		titles[note.Title] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+func parseImport(r io.Reader, opts notes.ImportOptions) ([]importEntry, error) {
+	switch opts.Format {
+	case notes.ImportCSV:
+		return parseCSV(r, opts.Mapping)
+	case notes.ImportJSON:
+		return parseJSON(r)
+	case notes.ImportMarkdown:
+		return parseMarkdown(r)
+	default:
+		return nil, notes.ErrInvalidImportFormat
+	}
+}
+
+func parseCSV(r io.Reader, mapping map[string]string) ([]importEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", notes.ErrInvalidImport, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	index := func(field string) int {
+		name := field
+		if column, ok := mapping[field]; ok {
+			name = column
+		}
+		if i, ok := columns[strings.ToLower(name)]; ok {
+			return i
+		}
+		return -1
+	}
+
+	titleIdx := index(notes.FieldTitle)
+	if titleIdx < 0 {
+		return nil, fmt.Errorf("%w: title column not found", notes.ErrInvalidImport)
+	}
+	descriptionIdx, statusIdx, priorityIdx := index(notes.FieldDescription), index(notes.FieldStatus), index(notes.FieldPriority)
+
+	var entries []importEntry
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("%w: %w", notes.ErrInvalidImport, readErr)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ``
+			}
+			return strings.TrimSpace(record[i])
+		}
+		entries = append(entries, newImportEntry(line, field(titleIdx), field(descriptionIdx),
+			field(statusIdx), field(priorityIdx)))
+	}
+	return entries, nil
+}
+
+// parseJSON принимает массив notes.Note или документ notes.ExportDocument.
+func parseJSON(r io.Reader) ([]importEntry, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", notes.ErrInvalidImport, err)
+	}
+
+	var notesSlice []notes.Note
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+		var document notes.ExportDocument
+		if err := json.Unmarshal(raw, &document); err != nil {
+			return nil, fmt.Errorf("%w: %w", notes.ErrInvalidImport, err)
+		}
+		if document.Version != notes.ExportVersion {
+			return nil, fmt.Errorf("%w: unsupported version %d", notes.ErrInvalidImport, document.Version)
+		}
+		notesSlice = document.Notes
+	} else if err := json.Unmarshal(raw, &notesSlice); err != nil {
+		return nil, fmt.Errorf("%w: %w", notes.ErrInvalidImport, err)
+	}
+
+	entries := make([]importEntry, 0, len(notesSlice))
+	for i, note := range notesSlice {
+		entry := importEntry{line: i + 1, note: notes.Note{
+			Title:       strings.TrimSpace(note.Title),
+			Description: note.Description,
+			Status:      note.Status,
+			Priority:    note.Priority,
+		}}
+		switch {
+		case entry.note.Title == ``:
+			entry.err = notes.ErrEmptyTitle
+		case !note.Status.Valid():
+			entry.err = notes.ErrInvalidStatus
+		case !note.Priority.Valid():
+			entry.err = notes.ErrInvalidPriority
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseMarkdown(r io.Reader) ([]importEntry, error) {
+	var entries []importEntry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		match := checklistItem.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		status := notes.Statuses[notes.New]
+		if match[1] != " " {
+			status = notes.Statuses[notes.Inactive]
+		}
+		entries = append(entries, newImportEntry(line, match[2], ``, status, ``))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", notes.ErrInvalidImport, err)
+	}
+	return entries, nil
+}
+
+func newImportEntry(line int, title, description, status, priority string) importEntry {
+	entry := importEntry{line: line, note: notes.Note{Title: title, Description: description}}
+	if title == `` {
+		entry.err = notes.ErrEmptyTitle
+	}
+
+	if status != `` {
+		entry.note.Status = notes.ParseStatus(status)
+		if !entry.note.Status.Valid() && entry.err == nil {
+			entry.err = notes.ErrInvalidStatus
+		}
+	}
+	if priority != `` {
+		entry.note.Priority = notes.ParsePriority(priority)
+		if !entry.note.Priority.Valid() && entry.err == nil {
+			entry.err = notes.ErrInvalidPriority
+		}
+	}
+	return entry
+}
@@ -0,0 +1,127 @@
+package note
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/services/note/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteService_ImportNotes(t *testing.T) {
+	existing := []notes.Note{{NID: "1", UID: "uid-1", Title: "Pay rent"}}
+	stream := func(notesSlice []notes.Note) func(string, func(notes.Note) error) error {
+		return func(_ string, fn func(notes.Note) error) error {
+			for _, note := range notesSlice {
+				if err := fn(note); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("csv dry run with mapping", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("StreamNotes", "uid-1", mock.Anything).Return(stream(existing))
+
+		input := "Name,State,Prio\nBuy milk,Active,High\nPay rent,,\nBuy milk,New,\n,New,\nCall mom,Done,\n"
+		report, err := service.ImportNotes(strings.NewReader(input), notes.ImportOptions{
+			UID:     "uid-1",
+			Format:  notes.ImportCSV,
+			DryRun:  true,
+			Mapping: map[string]string{"title": "Name", "status": "State", "priority": "Prio"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Duplicates)
+		assert.Equal(t, 2, report.Failed)
+		require.Len(t, report.Items, 5)
+		assert.Equal(t, 2, report.Items[0].Line)
+		assert.Equal(t, "Active", report.Items[0].Status)
+		assert.True(t, report.Items[1].Duplicate)
+		assert.True(t, report.Items[2].Duplicate)
+		assert.Equal(t, notes.ErrEmptyTitle.Error(), report.Items[3].Error)
+		assert.Equal(t, notes.ErrInvalidStatus.Error(), report.Items[4].Error)
+	})
+
+	t.Run("markdown checklist", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("StreamNotes", "uid-1", mock.Anything).Return(nil)
+		mockRepo.On("ApplyBulk", mock.MatchedBy(func(ops []notes.BulkOperation) bool {
+			return len(ops) == 2 &&
+				ops[0].Note.Title == "Write report" && ops[0].Note.Status == notes.New && ops[0].Note.UID == "uid-1" &&
+				ops[1].Note.Title == "Send invoice" && ops[1].Note.Status == notes.Inactive
+		}), notes.BulkBestEffort).Return([]notes.BulkResult{
+			{Index: 0, Action: notes.BulkCreate, NID: "a"},
+			{Index: 1, Action: notes.BulkCreate, NID: "b"},
+		}, nil)
+
+		input := "# Todo\n- [ ] Write report\n  * [x] Send invoice\nplain text\n"
+		report, err := service.ImportNotes(strings.NewReader(input), notes.ImportOptions{
+			UID:    "uid-1",
+			Format: notes.ImportMarkdown,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 2, report.Items[0].Line)
+		assert.NotEmpty(t, report.Items[0].NID)
+		assert.Equal(t, 3, report.Items[1].Line)
+	})
+
+	t.Run("json array", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("StreamNotes", "uid-1", mock.Anything).Return(stream(existing))
+
+		input := `[{"title":"Plan sprint","status":1},{"title":"Pay rent"},{"title":"Bad","status":9}]`
+		report, err := service.ImportNotes(strings.NewReader(input), notes.ImportOptions{
+			UID:    "uid-1",
+			Format: notes.ImportJSON,
+			DryRun: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Duplicates)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, "Active", report.Items[0].Status)
+	})
+
+	t.Run("duplicates are checked among the owner's notes", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("StreamNotes", "uid-2", mock.Anything).Return(nil)
+
+		report, err := service.ImportNotes(strings.NewReader("- [ ] Pay rent\n"), notes.ImportOptions{
+			UID:    "uid-2",
+			Format: notes.ImportMarkdown,
+			DryRun: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Zero(t, report.Duplicates)
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		service := New(mocks.NewRepositoryNote(t))
+
+		_, err := service.ImportNotes(strings.NewReader("Name\nx\n"), notes.ImportOptions{Format: notes.ImportCSV})
+		assert.ErrorIs(t, err, notes.ErrInvalidImport)
+
+		_, err = service.ImportNotes(strings.NewReader("{"), notes.ImportOptions{Format: notes.ImportJSON})
+		assert.ErrorIs(t, err, notes.ErrInvalidImport)
+	})
+}
@@ -31,6 +31,36 @@ func (_m *RepositoryNote) AddNote(_a0 notes.Note) error {
 	return r0
 }
 
+// ApplyBulk provides a mock function with given fields: ops, mode
+func (_m *RepositoryNote) ApplyBulk(ops []notes.BulkOperation, mode notes.BulkMode) ([]notes.BulkResult, error) {
+	ret := _m.Called(ops, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBulk")
+	}
+
+	var r0 []notes.BulkResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]notes.BulkOperation, notes.BulkMode) ([]notes.BulkResult, error)); ok {
+		return rf(ops, mode)
+	}
+	if rf, ok := ret.Get(0).(func([]notes.BulkOperation, notes.BulkMode) []notes.BulkResult); ok {
+		r0 = rf(ops, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]notes.BulkResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]notes.BulkOperation, notes.BulkMode) error); ok {
+		r1 = rf(ops, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteNote provides a mock function with given fields: noteID
 func (_m *RepositoryNote) DeleteNote(noteID string) error {
 	ret := _m.Called(noteID)
@@ -49,6 +79,34 @@ func (_m *RepositoryNote) DeleteNote(noteID string) error {
 	return r0
 }
 
+// DeleteUserNotes provides a mock function with given fields: uid
+func (_m *RepositoryNote) DeleteUserNotes(uid string) (int, error) {
+	ret := _m.Called(uid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserNotes")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(uid)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(uid)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNoteID provides a mock function with given fields: noteID
 func (_m *RepositoryNote) GetNoteID(noteID string) (notes.Note, error) {
 	ret := _m.Called(noteID)
@@ -107,6 +165,100 @@ func (_m *RepositoryNote) GetNotes() ([]notes.Note, error) {
 	return r0, r1
 }
 
+// GetNotesSorted provides a mock function with given fields: sortBy
+func (_m *RepositoryNote) GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error) {
+	ret := _m.Called(sortBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNotesSorted")
+	}
+
+	var r0 []notes.Note
+	var r1 error
+	if rf, ok := ret.Get(0).(func(notes.SortBy) ([]notes.Note, error)); ok {
+		return rf(sortBy)
+	}
+	if rf, ok := ret.Get(0).(func(notes.SortBy) []notes.Note); ok {
+		r0 = rf(sortBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]notes.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(notes.SortBy) error); ok {
+		r1 = rf(sortBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveNote provides a mock function with given fields: noteID, position
+func (_m *RepositoryNote) MoveNote(noteID string, position string) error {
+	ret := _m.Called(noteID, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveNote")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(noteID, position)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MoveNoteToStatus provides a mock function with given fields: noteID, status, move
+func (_m *RepositoryNote) MoveNoteToStatus(noteID string, status notes.Status, move notes.MoveRequest) (string, error) {
+	ret := _m.Called(noteID, status, move)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveNoteToStatus")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, notes.Status, notes.MoveRequest) (string, error)); ok {
+		return rf(noteID, status, move)
+	}
+	if rf, ok := ret.Get(0).(func(string, notes.Status, notes.MoveRequest) string); ok {
+		r0 = rf(noteID, status, move)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, notes.Status, notes.MoveRequest) error); ok {
+		r1 = rf(noteID, status, move)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamNotes provides a mock function with given fields: uid, fn
+func (_m *RepositoryNote) StreamNotes(uid string, fn func(notes.Note) error) error {
+	ret := _m.Called(uid, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamNotes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, func(notes.Note) error) error); ok {
+		r0 = rf(uid, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateNote provides a mock function with given fields: noteID, _a1
 func (_m *RepositoryNote) UpdateNote(noteID string, _a1 notes.Note) error {
 	ret := _m.Called(noteID, _a1)
@@ -1,7 +1,10 @@
 package note
 
 import (
+	"time"
+
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
 
 	"github.com/google/uuid"
 )
@@ -9,25 +12,44 @@ import (
 type RepositoryNote interface {
 	AddNote(note notes.Note) error
 	GetNotes() ([]notes.Note, error)
+	GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error)
 	GetNoteID(noteID string) (notes.Note, error)
 	DeleteNote(noteID string) error
 	UpdateNote(noteID string, note notes.Note) error
+	MoveNote(noteID string, position string) error
+	MoveNoteToStatus(noteID string, status notes.Status, move notes.MoveRequest) (string, error)
+	ApplyBulk(ops []notes.BulkOperation, mode notes.BulkMode) ([]notes.BulkResult, error)
+	StreamNotes(uid string, fn func(note notes.Note) error) error
+	DeleteUserNotes(uid string) (int, error)
 }
 
 type Service struct {
-	repo RepositoryNote
+	repo       RepositoryNote
+	publishers []Publisher
 }
 
-func New(repo RepositoryNote) *Service {
-	return &Service{repo: repo}
+type Option func(*Service)
+
+func New(repo RepositoryNote, opts ...Option) *Service {
+	ns := &Service{repo: repo}
+	for _, opt := range opts {
+		opt(ns)
+	}
+	return ns
 }
+
 func (ns *Service) CreateNote(note notes.Note) (string, error) {
+	if err := validation.Struct(note); err != nil {
+		return ``, err
+	}
 	note.NID = uuid.New().String()
+	note.Position = notes.InitialRank(time.Now())
 
 	err := ns.repo.AddNote(note)
 	if err != nil {
 		return ``, err
 	}
+	ns.publish(notes.Event{Type: notes.EventCreated, NID: note.NID, UID: note.UID, Note: note})
 	return note.NID, nil
 }
 
@@ -39,6 +61,34 @@ func (ns *Service) GetNotes() ([]notes.Note, error) {
 	return notes, nil
 }
 
+// unknownStatus — метка заметок со статусом вне перечня, например из старого импорта.
+const unknownStatus = "unknown"
+
+// CountByStatus считает заметки по статусам для метрик.
+func (ns *Service) CountByStatus() (map[string]int, error) {
+	list, err := ns.repo.GetNotes()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, note := range list {
+		if !note.Status.Valid() {
+			counts[unknownStatus]++
+			continue
+		}
+		counts[note.Status.String()]++
+	}
+	return counts, nil
+}
+
+func (ns *Service) GetNotesSorted(sortBy notes.SortBy) ([]notes.Note, error) {
+	notes, err := ns.repo.GetNotesSorted(sortBy)
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
 func (ns *Service) GetNoteID(noteID string) (notes.Note, error) {
 	note, err := ns.repo.GetNoteID(noteID)
 	if err != nil {
@@ -48,17 +98,98 @@ func (ns *Service) GetNoteID(noteID string) (notes.Note, error) {
 }
 
 func (ns *Service) DeleteNoteID(noteID string) error {
+	before, _ := ns.snapshot(noteID)
 	err := ns.repo.DeleteNote(noteID)
 	if err != nil {
 		return err
 	}
+	ns.publishDeleted(noteID, before)
 	return nil
 }
 
 func (ns *Service) UpdateNoteID(noteID string, note notes.Note) error {
+	if err := validation.Struct(note); err != nil {
+		return err
+	}
+	before, _ := ns.snapshot(noteID)
 	err := ns.repo.UpdateNote(noteID, note)
 	if err != nil {
 		return err
 	}
+	ns.publishChange(noteID, before)
 	return nil
 }
+
+// MoveNote ставит заметку владельца между соседями. Чужие заметка и соседи неотличимы
+// от отсутствующих, чтобы ответ не раскрывал чужие идентификаторы.
+func (ns *Service) MoveNote(uid, noteID string, move notes.MoveRequest) (string, error) {
+	stored, err := ns.GetUserNote(uid, noteID)
+	if err != nil {
+		return ``, err
+	}
+	var lower, upper string
+	if move.After != `` {
+		after, afterErr := ns.GetUserNote(uid, move.After)
+		if afterErr != nil {
+			return ``, afterErr
+		}
+		lower = after.Position
+	}
+	if move.Before != `` {
+		before, beforeErr := ns.GetUserNote(uid, move.Before)
+		if beforeErr != nil {
+			return ``, beforeErr
+		}
+		upper = before.Position
+	}
+
+	position, err := notes.RankBetween(lower, upper)
+	if err != nil {
+		return ``, err
+	}
+
+	if err = ns.repo.MoveNote(noteID, position); err != nil {
+		return ``, err
+	}
+	ns.publishChange(noteID, stored)
+	return position, nil
+}
+
+// GetBoard собирает доску из заметок владельца.
+func (ns *Service) GetBoard(uid, name string, loc *time.Location) (notes.Board, error) {
+	if name != notes.DefaultBoard {
+		return notes.Board{}, notes.ErrBoardNotFound
+	}
+
+	notesSlice, err := ns.ListUserNotes(uid, notes.SortPosition)
+	if err != nil {
+		return notes.Board{}, err
+	}
+	return notes.NewBoard(name, notesSlice, loc), nil
+}
+
+// MoveOnBoard переносит заметку владельца в колонку. Соседи тоже должны принадлежать владельцу.
+func (ns *Service) MoveOnBoard(uid, noteID string, move notes.BoardMoveRequest) (string, error) {
+	status := notes.ParseStatus(move.Status)
+	if !status.Valid() {
+		return ``, notes.ErrInvalidStatus
+	}
+	stored, err := ns.GetUserNote(uid, noteID)
+	if err != nil {
+		return ``, err
+	}
+	for _, neighbour := range []string{move.After, move.Before} {
+		if neighbour == `` {
+			continue
+		}
+		if _, err = ns.GetUserNote(uid, neighbour); err != nil {
+			return ``, err
+		}
+	}
+	position, err := ns.repo.MoveNoteToStatus(noteID, status, notes.MoveRequest{After: move.After, Before: move.Before})
+	if err != nil {
+		return ``, err
+	}
+	ns.publishChange(noteID, stored)
+	return position, nil
+}
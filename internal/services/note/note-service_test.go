@@ -3,6 +3,7 @@ package note
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
 	"github.com/Snoop-Duck/ToDoList/internal/services/note/mocks"
@@ -155,3 +156,185 @@ func TestNoteService_UpdateNoteID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestNoteService_MoveNote(t *testing.T) {
+	owned := func(nid, position string) notes.Note {
+		return notes.Note{NID: nid, UID: "uid-1", Position: position}
+	}
+
+	t.Run("between neighbours", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(owned("123", "z"), nil)
+		mockRepo.On("GetNoteID", "after").Return(owned("after", "a"), nil)
+		mockRepo.On("GetNoteID", "before").Return(owned("before", "c"), nil)
+		mockRepo.On("MoveNote", "123", "b").Return(nil)
+
+		position, err := service.MoveNote("uid-1", "123", notes.MoveRequest{After: "after", Before: "before"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "b", position)
+	})
+
+	t.Run("to the top", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(owned("123", "z"), nil)
+		mockRepo.On("GetNoteID", "before").Return(owned("before", "i"), nil)
+		mockRepo.On("MoveNote", "123", mock.AnythingOfType("string")).Return(nil)
+
+		position, err := service.MoveNote("uid-1", "123", notes.MoveRequest{Before: "before"})
+
+		require.NoError(t, err)
+		assert.Less(t, position, "i")
+	})
+
+	t.Run("neighbour not found", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(owned("123", "z"), nil)
+		mockRepo.On("GetNoteID", "after").Return(notes.Note{}, notes.ErrNoteNotFound)
+
+		_, err := service.MoveNote("uid-1", "123", notes.MoveRequest{After: "after"})
+
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+
+	t.Run("neighbours in wrong order", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(owned("123", "z"), nil)
+		mockRepo.On("GetNoteID", "after").Return(owned("after", "c"), nil)
+		mockRepo.On("GetNoteID", "before").Return(owned("before", "a"), nil)
+
+		_, err := service.MoveNote("uid-1", "123", notes.MoveRequest{After: "after", Before: "before"})
+
+		assert.ErrorIs(t, err, notes.ErrInvalidPosition)
+	})
+
+	t.Run("foreign note", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(notes.Note{NID: "123", UID: "uid-2", Position: "z"}, nil)
+
+		_, err := service.MoveNote("uid-1", "123", notes.MoveRequest{})
+
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+
+	t.Run("foreign neighbour", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(owned("123", "z"), nil)
+		mockRepo.On("GetNoteID", "before").Return(notes.Note{NID: "before", UID: "uid-2", Position: "c"}, nil)
+
+		_, err := service.MoveNote("uid-1", "123", notes.MoveRequest{Before: "before"})
+
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+}
+
+func TestNoteService_GetBoard(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("StreamNotes", "uid-1", mock.Anything).
+			Return(func(_ string, fn func(notes.Note) error) error {
+				return fn(notes.Note{NID: "1", UID: "uid-1", Status: notes.Active})
+			})
+
+		board, err := service.GetBoard("uid-1", notes.DefaultBoard, time.UTC)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, board.Total)
+		assert.Equal(t, 1, board.Columns[notes.Active].Count)
+	})
+
+	t.Run("empty storage", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("StreamNotes", "uid-1", mock.Anything).Return(nil)
+
+		board, err := service.GetBoard("uid-1", notes.DefaultBoard, time.UTC)
+
+		require.NoError(t, err)
+		assert.Zero(t, board.Total)
+	})
+
+	t.Run("unknown board", func(t *testing.T) {
+		service := New(mocks.NewRepositoryNote(t))
+
+		_, err := service.GetBoard("uid-1", "other", time.UTC)
+
+		assert.ErrorIs(t, err, notes.ErrBoardNotFound)
+	})
+}
+
+func TestNoteService_MoveOnBoard(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(notes.Note{NID: "123", UID: "uid-1"}, nil)
+		mockRepo.On("GetNoteID", "1").Return(notes.Note{NID: "1", UID: "uid-1"}, nil)
+		mockRepo.On("MoveNoteToStatus", "123", notes.Status(notes.Active), notes.MoveRequest{After: "1"}).
+			Return("b", nil)
+
+		position, err := service.MoveOnBoard("uid-1", "123", notes.BoardMoveRequest{Status: "Active", After: "1"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "b", position)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		service := New(mocks.NewRepositoryNote(t))
+
+		_, err := service.MoveOnBoard("uid-1", "123", notes.BoardMoveRequest{Status: "Done"})
+
+		assert.ErrorIs(t, err, notes.ErrInvalidStatus)
+	})
+
+	t.Run("foreign note", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(notes.Note{NID: "123", UID: "uid-2"}, nil)
+
+		_, err := service.MoveOnBoard("uid-1", "123", notes.BoardMoveRequest{Status: "Active"})
+
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+
+	t.Run("foreign neighbour", func(t *testing.T) {
+		mockRepo := mocks.NewRepositoryNote(t)
+		service := New(mockRepo)
+
+		mockRepo.On("GetNoteID", "123").Return(notes.Note{NID: "123", UID: "uid-1"}, nil)
+		mockRepo.On("GetNoteID", "2").Return(notes.Note{NID: "2", UID: "uid-2"}, nil)
+
+		_, err := service.MoveOnBoard("uid-1", "123", notes.BoardMoveRequest{Status: "Active", Before: "2"})
+
+		assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	})
+}
+
+func TestNoteService_CountByStatus(t *testing.T) {
+	mockRepo := mocks.NewRepositoryNote(t)
+	mockRepo.On("GetNotes").Return([]notes.Note{
+		{NID: "1", Status: notes.New},
+		{NID: "2", Status: notes.New},
+		{NID: "3", Status: notes.Status(42)},
+	}, nil)
+
+	counts, err := New(mockRepo).CountByStatus()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"New": 2, unknownStatus: 1}, counts)
+}
@@ -0,0 +1,107 @@
+package note
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/google/uuid"
+)
+
+// Методы для /api/v1: заметки видны и изменяются только владельцем. Чужая заметка
+// неотличима от отсутствующей, чтобы не раскрывать чужие идентификаторы.
+
+func (ns *Service) ListUserNotes(uid string, sortBy notes.SortBy) ([]notes.Note, error) {
+	notesSlice := make([]notes.Note, 0)
+	err := ns.StreamUserNotes(uid, func(note notes.Note) error {
+		notesSlice = append(notesSlice, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sortBy != notes.SortDefault {
+		sort.SliceStable(notesSlice, func(i, j int) bool {
+			return sortBy.Less(notesSlice[i], notesSlice[j])
+		})
+	}
+	return notesSlice, nil
+}
+
+// StreamUserNotes передаёт fn заметки владельца в порядке позиций, не собирая их в память.
+func (ns *Service) StreamUserNotes(uid string, fn func(note notes.Note) error) error {
+	return ns.repo.StreamNotes(uid, fn)
+}
+
+func (ns *Service) GetUserNote(uid, noteID string) (notes.Note, error) {
+	note, err := ns.repo.GetNoteID(noteID)
+	if err != nil {
+		return notes.Note{}, err
+	}
+	if note.UID != uid || note.Deleted {
+		return notes.Note{}, notes.ErrNoteNotFound
+	}
+	return note, nil
+}
+
+func (ns *Service) CreateUserNote(uid string, note notes.Note) (notes.Note, error) {
+	if err := validation.Struct(note); err != nil {
+		return notes.Note{}, err
+	}
+	now := time.Now()
+	note.NID = uuid.New().String()
+	note.Position = notes.InitialRank(now)
+	note.CreatedAt = now.UTC()
+	note.UID = uid
+
+	if err := ns.repo.AddNote(note); err != nil {
+		return notes.Note{}, err
+	}
+	ns.publish(notes.Event{Type: notes.EventCreated, NID: note.NID, UID: uid, Note: note})
+	return note, nil
+}
+
+// ReplaceUserNote заменяет редактируемые поля заметки; позиция, автор и время создания сохраняются.
+func (ns *Service) ReplaceUserNote(uid, noteID string, note notes.Note) (notes.Note, error) {
+	if err := validation.Struct(note); err != nil {
+		return notes.Note{}, err
+	}
+	return ns.PatchUserNote(uid, noteID, notes.NotePatch{
+		Title:       &note.Title,
+		Description: &note.Description,
+		Status:      &note.Status,
+		Priority:    &note.Priority,
+	})
+}
+
+func (ns *Service) PatchUserNote(uid, noteID string, patch notes.NotePatch) (notes.Note, error) {
+	stored, err := ns.GetUserNote(uid, noteID)
+	if err != nil {
+		return notes.Note{}, err
+	}
+	note := patch.Apply(stored)
+	// UID проверяется правилом isdefault, поэтому проверяется копия без него.
+	check := note
+	check.UID = ``
+	if err = validation.Struct(check); err != nil {
+		return notes.Note{}, err
+	}
+	if err = ns.repo.UpdateNote(noteID, note); err != nil {
+		return notes.Note{}, err
+	}
+	ns.publish(notes.ChangeEvent(stored, note))
+	return note, nil
+}
+
+func (ns *Service) DeleteUserNote(uid, noteID string) error {
+	stored, err := ns.GetUserNote(uid, noteID)
+	if err != nil {
+		return err
+	}
+	if err = ns.repo.DeleteNote(noteID); err != nil {
+		return err
+	}
+	ns.publishDeleted(noteID, stored)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Package secretbox шифрует секреты, которые сервис должен уметь прочитать обратно
+// (секреты TOTP, ключи подписи вебхуков), перед записью в хранилище.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var (
+	// ErrNoKey — ключ не задан: шифровать ключом sha256("") бессмысленно.
+	ErrNoKey              = errors.New("encryption key not configured")
+	errCiphertextTooShort = errors.New("ciphertext too short")
+)
+
+// Seal шифрует secret в AES-GCM ключом, выведенным из key, и возвращает base64 с nonce в начале.
+func Seal(key []byte, secret string) (string, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return ``, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return ``, err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open расшифровывает результат Seal.
+func Open(key []byte, encrypted string) (string, error) {
+	gcm, err := newCipher(key)
+	if err != nil {
+		return ``, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return ``, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return ``, errCiphertextTooShort
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	secret, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return ``, err
+	}
+	return string(secret), nil
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, ErrNoKey
+	}
+	digest := sha256.Sum256(key)
+	block, err := aes.NewCipher(digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,63 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+// AuditLog — журнал событий безопасности; хранилище само продолжает цепочку хэшей.
+type AuditLog interface {
+	AppendAudit(event audit.Event) (audit.Event, error)
+	ListAudit(filter audit.Filter) ([]audit.Event, error)
+	WalkAudit(fn func(event audit.Event) error) error
+}
+
+func WithAuditLog(log AuditLog) Option {
+	return func(us *Service) {
+		us.auditLog = log
+	}
+}
+
+// Record пишет событие с исходом по err. Сбой журнала не отменяет уже выполненное
+// действие: ошибку записи логирует само хранилище.
+func (us *Service) Record(event audit.Event, err error) {
+	if us.auditLog == nil {
+		return
+	}
+	event.Outcome = audit.OutcomeOf(err)
+	var secondFactor *users.SecondFactorError
+	switch {
+	case errors.As(err, &secondFactor):
+		event.Outcome = audit.OutcomePending
+	case err != nil:
+		event.Detail = err.Error()
+	}
+	_, _ = us.auditLog.AppendAudit(event)
+}
+
+// ListAudit возвращает события журнала, начиная с самых новых.
+func (us *Service) ListAudit(filter audit.Filter) ([]audit.Event, error) {
+	if us.auditLog == nil {
+		return nil, audit.ErrNotConfigured
+	}
+	return us.auditLog.ListAudit(filter)
+}
+
+// VerifyAudit проходит журнал целиком и сверяет цепочку хэшей.
+func (us *Service) VerifyAudit() (audit.Report, error) {
+	if us.auditLog == nil {
+		return audit.Report{}, audit.ErrNotConfigured
+	}
+	var verifier audit.Verifier
+	if tail, ok := us.auditLog.(interface{ Truncated() bool }); ok {
+		// Кольцевой буфер в памяти хранит только последние записи.
+		verifier.Tail = tail.Truncated()
+	}
+	err := us.auditLog.WalkAudit(verifier.Check)
+	if err != nil && !errors.Is(err, audit.ErrChainBroken) {
+		return audit.Report{}, err
+	}
+	return verifier.Report(err), nil
+}
@@ -0,0 +1,53 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRecordsLogins(t *testing.T) {
+	auditLog := inmemory.NewAuditLog(100)
+	service := New(inmemory.NewUsers(), WithAuditLog(auditLog))
+
+	uid, err := service.RegisterUser(users.User{Name: "Bob", Email: "Bob@Example.com", Password: "password1"})
+	require.NoError(t, err)
+	_, err = service.LoginUser(users.UserRequest{Email: "bob@example.com", Password: "wrong"}, "10.0.0.1")
+	require.Error(t, err)
+	_, err = service.LoginUser(users.UserRequest{Email: "bob@example.com", Password: "password1"}, "10.0.0.1")
+	require.NoError(t, err)
+
+	events, err := service.ListAudit(audit.Filter{Type: audit.EventLogin})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, audit.OutcomeSuccess, events[0].Outcome)
+	assert.Equal(t, uid, events[0].ActorUID)
+	assert.Equal(t, "10.0.0.1", events[0].IP)
+	assert.Equal(t, audit.OutcomeFailure, events[1].Outcome)
+	assert.Empty(t, events[1].ActorUID)
+	assert.Equal(t, "bob@example.com", events[1].Target)
+	assert.Equal(t, users.ErrInvalidUserCreds.Error(), events[1].Detail)
+
+	registered, err := service.ListAudit(audit.Filter{Type: audit.EventRegister})
+	require.NoError(t, err)
+	require.Len(t, registered, 1)
+	assert.Equal(t, uid, registered[0].ActorUID)
+
+	report, err := service.VerifyAudit()
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 3, report.Checked)
+}
+
+func TestAuditLogNotConfigured(t *testing.T) {
+	service := New(inmemory.NewUsers())
+
+	_, err := service.ListAudit(audit.Filter{})
+	assert.ErrorIs(t, err, audit.ErrNotConfigured)
+	_, err = service.VerifyAudit()
+	assert.ErrorIs(t, err, audit.ErrNotConfigured)
+}
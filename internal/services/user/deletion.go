@@ -0,0 +1,160 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+// NoteStore даёт сервису пользователей доступ к заметкам для выгрузки и удаления аккаунта.
+type NoteStore interface {
+	StreamNotes(uid string, fn func(note notes.Note) error) error
+	DeleteUserNotes(uid string) (int, error)
+}
+
+func WithNotes(store NoteStore) Option {
+	return func(us *Service) {
+		us.notes = store
+	}
+}
+
+// WithDeletionGrace задаёт срок, в течение которого удаление аккаунта можно отменить.
+// Нулевой срок удаляет аккаунт сразу.
+func WithDeletionGrace(grace time.Duration) Option {
+	return func(us *Service) {
+		us.deletionGrace = grace
+	}
+}
+
+// RequestDeletion планирует удаление аккаунта и возвращает время, когда оно произойдёт.
+func (us *Service) RequestDeletion(userID string) (deleteAt time.Time, err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventDeletionRequested, ActorUID: userID, Target: userID}, err)
+	}()
+
+	now := time.Now().UTC()
+	if us.deletionGrace <= 0 {
+		return now, us.purgeUser(userID)
+	}
+
+	if _, err = us.repo.GetUserID(userID); err != nil {
+		return time.Time{}, err
+	}
+	deleteAt = now.Add(us.deletionGrace)
+	if err = us.repo.ScheduleDeletion(userID, &deleteAt); err != nil {
+		return time.Time{}, err
+	}
+	return deleteAt, nil
+}
+
+func (us *Service) CancelDeletion(userID string) (err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventDeletionCanceled, ActorUID: userID, Target: userID}, err)
+	}()
+
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return err
+	}
+	if user.DeleteAt == nil {
+		return users.ErrDeletionNotScheduled
+	}
+	return us.repo.ScheduleDeletion(userID, nil)
+}
+
+// PurgeDeletedUsers удаляет аккаунты, у которых истёк срок отмены удаления.
+func (us *Service) PurgeDeletedUsers(now time.Time) (int, error) {
+	due, err := us.repo.GetUsersDueForDeletion(now)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	purged := 0
+	for _, user := range due {
+		err = us.purgeUser(user.UID)
+		us.Record(audit.Event{Type: audit.EventUserPurged, Target: user.UID}, err)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purgeUser удаляет заметки до аккаунта: при сбое аккаунт остаётся и удаление можно повторить,
+// а заметки без владельца не появляются.
+func (us *Service) purgeUser(userID string) error {
+	// Адрес нужен только для сброса счётчика неудачных входов по аккаунту.
+	var email string
+	if us.attempts != nil {
+		user, err := us.repo.GetUserID(userID)
+		if err != nil {
+			return err
+		}
+		email = user.Email
+	}
+	if us.notes != nil {
+		if _, err := us.notes.DeleteUserNotes(userID); err != nil {
+			return err
+		}
+	}
+	if err := us.repo.DeleteUser(userID); err != nil {
+		return err
+	}
+	if us.attempts != nil {
+		return us.attempts.ResetAttempts(accountKey(email))
+	}
+	return nil
+}
+
+// ExportUser собирает всё, что хранится о пользователе.
+func (us *Service) ExportUser(userID string) (users.Export, error) {
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return users.Export{}, err
+	}
+	export := users.Export{
+		ExportedAt:       time.Now().UTC(),
+		TwoFactorEnabled: user.TOTP.Enabled,
+		Notes:            make([]notes.Note, 0),
+	}
+	user.Password = ``
+	export.User = user
+
+	if us.notes != nil {
+		err = us.notes.StreamNotes(userID, func(note notes.Note) error {
+			export.Notes = append(export.Notes, note)
+			return nil
+		})
+		if err != nil {
+			return users.Export{}, err
+		}
+	}
+	if export.Preferences, err = us.GetPreferences(userID); err != nil {
+		return users.Export{}, err
+	}
+	if export.Sessions, err = us.repo.GetSessions(userID); err != nil {
+		return users.Export{}, err
+	}
+	if export.AccessTokens, err = us.repo.GetAccessTokens(userID); err != nil {
+		return users.Export{}, err
+	}
+	if export.Identities, err = us.repo.GetIdentities(userID); err != nil {
+		return users.Export{}, err
+	}
+	if us.attempts != nil {
+		attempts, attemptsErr := us.attempts.GetAttempts(accountKey(user.Email))
+		if attemptsErr != nil {
+			return users.Export{}, attemptsErr
+		}
+		if attempts.Failures > 0 {
+			export.LoginAttempts = &attempts
+		}
+	}
+	return export, nil
+}
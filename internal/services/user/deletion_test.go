@@ -0,0 +1,91 @@
+package user
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeletionFixture(t *testing.T) (*inmemory.Users, *inmemory.Notes) {
+	t.Helper()
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com", Password: "secret"}))
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-2", Email: "alice@example.com", Password: "secret"}))
+
+	noteRepo := inmemory.NewNotes(false, filepath.Join(t.TempDir(), "notes.json"))
+	require.NoError(t, noteRepo.AddNote(notes.Note{NID: "n-1", Title: "bob 1", UID: "uid-1"}))
+	require.NoError(t, noteRepo.AddNote(notes.Note{NID: "n-2", Title: "bob 2", UID: "uid-1"}))
+	require.NoError(t, noteRepo.AddNote(notes.Note{NID: "n-3", Title: "alice", UID: "uid-2"}))
+	return repo, noteRepo
+}
+
+func TestRequestDeletionWithGrace(t *testing.T) {
+	repo, noteRepo := newDeletionFixture(t)
+	service := New(repo, WithNotes(noteRepo), WithDeletionGrace(24*time.Hour))
+
+	deleteAt, err := service.RequestDeletion("uid-1")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), deleteAt, time.Minute)
+
+	purged, err := service.PurgeDeletedUsers(time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	require.NoError(t, service.CancelDeletion("uid-1"))
+	assert.ErrorIs(t, service.CancelDeletion("uid-1"), users.ErrDeletionNotScheduled)
+
+	_, err = service.RequestDeletion("uid-1")
+	require.NoError(t, err)
+	purged, err = service.PurgeDeletedUsers(time.Now().Add(25 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = repo.GetUserID("uid-1")
+	assert.ErrorIs(t, err, users.ErrUserNotFound)
+	_, err = noteRepo.GetNoteID("n-1")
+	assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+	_, err = noteRepo.GetNoteID("n-3")
+	assert.NoError(t, err)
+}
+
+func TestRequestDeletionWithoutGrace(t *testing.T) {
+	repo, noteRepo := newDeletionFixture(t)
+	service := New(repo, WithNotes(noteRepo))
+
+	session, err := service.CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+
+	_, err = service.RequestDeletion("uid-1")
+	require.NoError(t, err)
+
+	_, err = repo.GetUserID("uid-1")
+	assert.ErrorIs(t, err, users.ErrUserNotFound)
+	_, err = repo.GetSession(session.ID)
+	assert.ErrorIs(t, err, users.ErrSessionNotFound)
+	_, err = noteRepo.GetNoteID("n-2")
+	assert.ErrorIs(t, err, notes.ErrNoteNotFound)
+}
+
+func TestExportUser(t *testing.T) {
+	repo, noteRepo := newDeletionFixture(t)
+	service := New(repo, WithNotes(noteRepo))
+
+	_, err := service.CreateSession("uid-1", "test", "127.0.0.1", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveIdentity(users.Identity{Issuer: "https://id.example.com", Subject: "sub-1", UID: "uid-1"}))
+
+	export, err := service.ExportUser("uid-1")
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", export.User.Email)
+	assert.Empty(t, export.User.Password)
+	assert.Len(t, export.Notes, 2)
+	assert.Len(t, export.Sessions, 1)
+	assert.Len(t, export.Identities, 1)
+	assert.Empty(t, export.AccessTokens)
+}
@@ -0,0 +1,179 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+const (
+	accountFreeAttempts = 5
+	ipFreeAttempts      = 20
+	lockoutBaseDelay    = 30 * time.Second
+	lockoutMaxDelay     = time.Hour
+	lockoutWindow       = time.Hour
+)
+
+// AttemptStore хранит счётчики неудачных входов. Ключи — "account:<email>" и "ip:<addr>".
+type AttemptStore interface {
+	GetAttempts(key string) (users.LoginAttempts, error)
+	// RecordFailure увеличивает счётчик; если прошлая неудача старше window, счёт начинается заново.
+	RecordFailure(key string, now time.Time, window time.Duration) (users.LoginAttempts, error)
+	LockUntil(key string, until time.Time) error
+	ResetAttempts(key string) error
+}
+
+// LockoutPolicy задаёт, сколько ошибок прощается и как растёт блокировка после них.
+type LockoutPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+// Delay возвращает длительность блокировки после failures неудач: BaseDelay, затем вдвое больше за каждую следующую.
+func (p LockoutPolicy) Delay(failures int) time.Duration {
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+	delay := p.BaseDelay
+	for range failures - p.FreeAttempts - 1 {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return min(delay, p.MaxDelay)
+}
+
+func WithAttemptStore(store AttemptStore) Option {
+	return func(us *Service) {
+		us.attempts = store
+	}
+}
+
+func WithLockoutPolicy(account, ip LockoutPolicy) Option {
+	return func(us *Service) {
+		us.accountPolicy = account
+		us.ipPolicy = ip
+	}
+}
+
+func defaultAccountPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts: accountFreeAttempts,
+		BaseDelay:    lockoutBaseDelay,
+		MaxDelay:     lockoutMaxDelay,
+		Window:       lockoutWindow,
+	}
+}
+
+func defaultIPPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts: ipFreeAttempts,
+		BaseDelay:    lockoutBaseDelay,
+		MaxDelay:     lockoutMaxDelay,
+		Window:       lockoutWindow,
+	}
+}
+
+// UnlockAccount снимает блокировку с аккаунта и/или IP.
+func (us *Service) UnlockAccount(req users.UnlockRequest) error {
+	if req.Email == `` && req.IP == `` {
+		return users.ErrEmptyUnlock
+	}
+	if us.attempts == nil {
+		return nil
+	}
+	if req.Email != `` {
+		if err := us.attempts.ResetAttempts(accountKey(req.Email)); err != nil {
+			return err
+		}
+	}
+	if req.IP != `` {
+		if err := us.attempts.ResetAttempts(ipKey(req.IP)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (us *Service) checkLockout(email, ip string, now time.Time) error {
+	if us.attempts == nil {
+		return nil
+	}
+
+	var retryAfter time.Duration
+	for _, key := range loginKeys(email, ip) {
+		attempts, err := us.attempts.GetAttempts(key)
+		if err != nil {
+			return err
+		}
+		retryAfter = max(retryAfter, attempts.LockedUntil.Sub(now))
+	}
+	if retryAfter > 0 {
+		return &users.LockoutError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordFailure учитывает неудачу и возвращает LockoutError, если она привела к блокировке.
+func (us *Service) recordFailure(email, ip string, now time.Time) error {
+	if us.attempts == nil {
+		return nil
+	}
+
+	var retryAfter time.Duration
+	for _, key := range loginKeys(email, ip) {
+		policy := us.accountPolicy
+		if strings.HasPrefix(key, ipKeyPrefix) {
+			policy = us.ipPolicy
+		}
+
+		attempts, err := us.attempts.RecordFailure(key, now, policy.Window)
+		if err != nil {
+			return err
+		}
+		delay := policy.Delay(attempts.Failures)
+		if delay == 0 {
+			continue
+		}
+		if err = us.attempts.LockUntil(key, now.Add(delay)); err != nil {
+			return err
+		}
+		retryAfter = max(retryAfter, delay)
+	}
+	if retryAfter > 0 {
+		return &users.LockoutError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (us *Service) resetAccountAttempts(email string) error {
+	if us.attempts == nil {
+		return nil
+	}
+	return us.attempts.ResetAttempts(accountKey(email))
+}
+
+const (
+	accountKeyPrefix = "account:"
+	ipKeyPrefix      = "ip:"
+)
+
+func accountKey(email string) string {
+	return accountKeyPrefix + users.NormalizeEmail(email)
+}
+
+func ipKey(ip string) string {
+	return ipKeyPrefix + ip
+}
+
+func loginKeys(email, ip string) []string {
+	keys := []string{accountKey(email)}
+	if ip != `` {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
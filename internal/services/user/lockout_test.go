@@ -0,0 +1,74 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/services/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	policy := LockoutPolicy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	assert.Equal(t, time.Duration(0), policy.Delay(3))
+	assert.Equal(t, time.Second, policy.Delay(4))
+	assert.Equal(t, 2*time.Second, policy.Delay(5))
+	assert.Equal(t, 8*time.Second, policy.Delay(7))
+	assert.Equal(t, 10*time.Second, policy.Delay(8))
+	assert.Equal(t, 10*time.Second, policy.Delay(100))
+}
+
+func TestLoginUserLockout(t *testing.T) {
+	user := users.User{UID: "uid-1", Email: "bob@example.com", Password: "secret"}
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUser", user.Email).Return(user, nil)
+
+	policy := LockoutPolicy{FreeAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	service := New(repoMock, WithAttemptStore(inmemory.NewAttempts()), WithLockoutPolicy(policy, policy))
+	bad := users.UserRequest{Email: user.Email, Password: "wrong"}
+
+	for range 2 {
+		_, err := service.LoginUser(bad, "10.0.0.1")
+		assert.ErrorIs(t, err, users.ErrInvalidUserCreds)
+	}
+
+	_, err := service.LoginUser(bad, "10.0.0.1")
+	var lockErr *users.LockoutError
+	require.True(t, errors.As(err, &lockErr), err)
+	assert.Equal(t, time.Minute, lockErr.RetryAfter)
+
+	// Даже верный пароль не принимается, пока аккаунт заблокирован.
+	_, err = service.LoginUser(users.UserRequest{Email: "BOB@example.com", Password: "secret"}, "10.0.0.2")
+	assert.ErrorIs(t, err, users.ErrTooManyAttempts)
+
+	require.NoError(t, service.UnlockAccount(users.UnlockRequest{Email: user.Email}))
+
+	userID, err := service.LoginUser(users.UserRequest{Email: user.Email, Password: "secret"}, "10.0.0.2")
+	require.NoError(t, err)
+	assert.Equal(t, user.UID, userID)
+}
+
+func TestLoginUserIPLockout(t *testing.T) {
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUser", "a@example.com").Return(users.User{}, users.ErrUserNotFound)
+	repoMock.On("GetUser", "b@example.com").Return(users.User{}, users.ErrUserNotFound)
+
+	account := LockoutPolicy{FreeAttempts: 10, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	ip := LockoutPolicy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	service := New(repoMock, WithAttemptStore(inmemory.NewAttempts()), WithLockoutPolicy(account, ip))
+
+	_, err := service.LoginUser(users.UserRequest{Email: "a@example.com"}, "10.0.0.1")
+	assert.ErrorIs(t, err, users.ErrUserNotFound)
+	_, err = service.LoginUser(users.UserRequest{Email: "b@example.com"}, "10.0.0.1")
+	assert.ErrorIs(t, err, users.ErrTooManyAttempts)
+}
+
+func TestUnlockAccountEmpty(t *testing.T) {
+	service := New(mocks.NewRepository(t))
+	assert.ErrorIs(t, service.UnlockAccount(users.UnlockRequest{}), users.ErrEmptyUnlock)
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/google/uuid"
+)
+
+// LoginExternal входит по проверенной личности OIDC. Пользователь ищется по паре
+// issuer+subject; при первом входе создаётся (JIT). Существующий аккаунт с тем же
+// адресом привязывается, только если провайдер подтвердил email. Для аккаунтов с 2FA,
+// как и при входе по паролю, возвращается SecondFactorError с pending-токеном.
+func (us *Service) LoginExternal(identity users.Identity) (uid string, err error) {
+	defer func() {
+		event := audit.Event{Type: audit.EventLoginExternal, ActorUID: uid, Target: identity.Issuer + "#" + identity.Subject}
+		us.Record(event, err)
+	}()
+
+	user, err := us.repo.GetUserByIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		return us.completeExternal(user)
+	}
+	if !errors.Is(err, users.ErrIdentityNotFound) {
+		return ``, err
+	}
+	identity.Email = users.NormalizeEmail(identity.Email)
+	if identity.Email == `` {
+		return ``, users.ErrIdentityNoEmail
+	}
+
+	if existing, getErr := us.repo.GetUser(identity.Email); getErr == nil {
+		if !identity.EmailVerified {
+			return ``, users.ErrUserAlredyExists
+		}
+		identity.UID = existing.UID
+		if err = us.repo.SaveIdentity(identity); err != nil {
+			return ``, err
+		}
+		return us.completeExternal(existing)
+	}
+
+	name := identity.Name
+	if name == `` {
+		name = identity.Email
+	}
+	user = users.User{
+		UID:       uuid.New().String(),
+		Name:      name,
+		Email:     identity.Email,
+		Verified:  identity.EmailVerified,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err = us.repo.SaveUser(user); err != nil {
+		return ``, err
+	}
+
+	identity.UID = user.UID
+	if err = us.repo.SaveIdentity(identity); err != nil {
+		return ``, err
+	}
+	return user.UID, nil
+}
+
+// completeExternal не пропускает второй фактор: внешний провайдер подтверждает только адрес.
+func (us *Service) completeExternal(user users.User) (string, error) {
+	if user.TOTP.Enabled {
+		return ``, us.pendingLogin(user)
+	}
+	return user.UID, nil
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+)
+
+const (
+	resetTokenTTL   = time.Hour
+	resetTokenBytes = 32
+)
+
+// ForgotPassword отправляет ссылку сброса пароля. Для неизвестного адреса молча
+// ничего не делает, чтобы по ответу нельзя было узнать, зарегистрирован ли email.
+func (us *Service) ForgotPassword(email string) error {
+	if us.mailer == nil {
+		return users.ErrMailerNotSet
+	}
+
+	user, err := us.repo.GetUser(users.NormalizeEmail(email))
+	if err != nil {
+		return nil //nolint:nilerr // не раскрываем, существует ли пользователь
+	}
+
+	raw := make([]byte, resetTokenBytes)
+	if _, err = rand.Read(raw); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err = us.repo.SaveResetToken(users.ResetToken{
+		Hash:      hashToken(token),
+		UID:       user.UID,
+		ExpiresAt: time.Now().Add(resetTokenTTL).UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(us.baseURL, "/") + "/users/password/reset?token=" + url.QueryEscape(token)
+	return us.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Someone requested a password reset for your account. Use the link below to set a new password:\n\n" +
+			link + "\n\n" +
+			"The link expires in 1 hour and can be used once. If you did not request it, ignore this email.\n",
+	})
+}
+
+// ResetPassword погашает токен и меняет пароль. Пароль проверяется до погашения, чтобы
+// отклонённый пароль не сжигал ссылку. Хранилище вместе с паролем удаляет сессии
+// пользователя, поэтому JWT, выданные раньше, перестают действовать даже в ту же секунду.
+func (us *Service) ResetPassword(req users.ResetPasswordRequest) (err error) {
+	event := audit.Event{Type: audit.EventPasswordReset}
+	defer func() {
+		us.Record(event, err)
+	}()
+
+	if req.Password == `` {
+		return users.ErrEmptyPassword
+	}
+	if err = validation.Struct(req); err != nil {
+		return err
+	}
+
+	token, err := us.repo.ConsumeResetToken(hashToken(req.Token))
+	if err != nil {
+		return err
+	}
+	event.ActorUID, event.Target = token.UID, token.UID
+	now := time.Now().UTC()
+	if now.After(token.ExpiresAt) {
+		return users.ErrInvalidToken
+	}
+
+	return us.repo.ResetPassword(token.UID, req.Password, now)
+}
+
+// CheckToken возвращает ErrTokenRevoked для токенов, выданных до смены пароля.
+func (us *Service) CheckToken(userID string, issuedAt time.Time) error {
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return err
+	}
+	if user.TokenRevoked(issuedAt) {
+		return users.ErrTokenRevoked
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
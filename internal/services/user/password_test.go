@@ -0,0 +1,123 @@
+package user
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/mailer"
+	"github.com/Snoop-Duck/ToDoList/internal/services/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgotAndResetPassword(t *testing.T) {
+	user := users.User{UID: "uid-1", Name: "Bob", Email: "bob@example.com", Password: "old"}
+
+	var saved users.ResetToken
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUser", user.Email).Return(user, nil)
+	repoMock.On("SaveResetToken", mock.Anything).Run(func(args mock.Arguments) {
+		saved, _ = args.Get(0).(users.ResetToken)
+	}).Return(nil)
+
+	memMailer := mailer.NewMemory()
+	service := New(repoMock, WithMailer(memMailer), WithVerification(testKey, "http://notes.test", time.Hour))
+
+	require.NoError(t, service.ForgotPassword(user.Email))
+	require.Len(t, memMailer.Messages(), 1)
+
+	_, link, ok := strings.Cut(memMailer.Messages()[0].Body, "/users/password/reset?token=")
+	require.True(t, ok)
+	token, err := url.QueryUnescape(strings.Fields(link)[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, user.UID, saved.UID)
+	assert.NotEqual(t, token, saved.Hash, "token must be stored hashed")
+	assert.Equal(t, hashToken(token), saved.Hash)
+
+	repoMock.On("ConsumeResetToken", saved.Hash).Return(saved, nil).Once()
+	repoMock.On("ResetPassword", user.UID, "new-password1", mock.Anything).Return(nil).Once()
+
+	require.NoError(t, service.ResetPassword(users.ResetPasswordRequest{Token: token, Password: "new-password1"}))
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUser", "nobody@example.com").Return(users.User{}, users.ErrUserNotFound)
+
+	memMailer := mailer.NewMemory()
+	service := New(repoMock, WithMailer(memMailer))
+
+	assert.NoError(t, service.ForgotPassword("nobody@example.com"))
+	assert.Empty(t, memMailer.Messages())
+}
+
+func TestResetPasswordErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       users.ResetPasswordRequest
+		token     users.ResetToken
+		repoErr   error
+		wantErr   error
+		consulted bool
+	}{
+		{
+			name:    "empty password",
+			req:     users.ResetPasswordRequest{Token: "t"},
+			wantErr: users.ErrEmptyPassword,
+		},
+		{
+			name:      "unknown token",
+			req:       users.ResetPasswordRequest{Token: "t", Password: "passw0rd"},
+			repoErr:   users.ErrInvalidToken,
+			wantErr:   users.ErrInvalidToken,
+			consulted: true,
+		},
+		{
+			name:      "expired token",
+			req:       users.ResetPasswordRequest{Token: "t", Password: "passw0rd"},
+			token:     users.ResetToken{UID: "uid-1", ExpiresAt: time.Now().Add(-time.Minute)},
+			wantErr:   users.ErrInvalidToken,
+			consulted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := mocks.NewRepository(t)
+			if tc.consulted {
+				repoMock.On("ConsumeResetToken", hashToken(tc.req.Token)).Return(tc.token, tc.repoErr)
+			}
+
+			err := New(repoMock).ResetPassword(tc.req)
+			assert.True(t, errors.Is(err, tc.wantErr), err)
+		})
+	}
+}
+
+func TestResetPasswordWeakPasswordKeepsToken(t *testing.T) {
+	// Мок без ожиданий: погашение токена провалило бы тест.
+	service := New(mocks.NewRepository(t))
+
+	err := service.ResetPassword(users.ResetPasswordRequest{Token: "t", Password: "short"})
+
+	var fieldErrs validation.Errors
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Equal(t, "password", fieldErrs[0].Field)
+}
+
+func TestCheckToken(t *testing.T) {
+	changedAt := time.Now()
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUserID", "uid-1").Return(users.User{UID: "uid-1", PasswordChangedAt: changedAt}, nil)
+
+	service := New(repoMock)
+	assert.ErrorIs(t, service.CheckToken("uid-1", changedAt.Add(-time.Hour)), users.ErrTokenRevoked)
+	assert.NoError(t, service.CheckToken("uid-1", changedAt.Add(time.Second)))
+}
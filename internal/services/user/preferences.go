@@ -0,0 +1,37 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+)
+
+// GetPreferences возвращает настройки пользователя; пока он их не менял — настройки по умолчанию.
+func (us *Service) GetPreferences(userID string) (users.Preferences, error) {
+	prefs, err := us.repo.GetPreferences(userID)
+	if errors.Is(err, users.ErrPreferencesNotFound) {
+		return users.DefaultPreferences(), nil
+	}
+	return prefs, err
+}
+
+// UpdatePreferences применяет патч к текущим настройкам и сохраняет результат.
+func (us *Service) UpdatePreferences(userID string, patch users.PreferencesPatch) (users.Preferences, error) {
+	if _, err := us.repo.GetUserID(userID); err != nil {
+		return users.Preferences{}, err
+	}
+	prefs, err := us.GetPreferences(userID)
+	if err != nil {
+		return users.Preferences{}, err
+	}
+
+	prefs = patch.Apply(prefs)
+	if err = validation.Struct(prefs); err != nil {
+		return users.Preferences{}, err
+	}
+	if err = us.repo.SavePreferences(userID, prefs); err != nil {
+		return users.Preferences{}, err
+	}
+	return prefs, nil
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferences(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com"}))
+	service := New(repo)
+
+	prefs, err := service.GetPreferences("uid-1")
+	require.NoError(t, err)
+	assert.Equal(t, users.DefaultPreferences(), prefs)
+
+	timezone := "Asia/Tokyo"
+	sortBy := notes.SortPriority
+	prefs, err = service.UpdatePreferences("uid-1", users.PreferencesPatch{Timezone: &timezone, DefaultSort: &sortBy})
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", prefs.Location().String())
+	assert.Equal(t, users.DefaultLocale, prefs.Locale)
+
+	locale := "ru-RU"
+	prefs, err = service.UpdatePreferences("uid-1", users.PreferencesPatch{Locale: &locale})
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", prefs.Timezone)
+	assert.Equal(t, notes.SortPriority, prefs.DefaultSort)
+
+	stored, err := service.GetPreferences("uid-1")
+	require.NoError(t, err)
+	assert.Equal(t, prefs, stored)
+}
+
+func TestUpdatePreferencesValidation(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com"}))
+	service := New(repo)
+
+	timezone := "Mars/Olympus"
+	weekStart := "friday"
+	status := notes.Status(10)
+	_, err := service.UpdatePreferences("uid-1", users.PreferencesPatch{
+		Timezone: &timezone, WeekStart: &weekStart, DefaultStatus: &status,
+	})
+
+	var fieldErrs validation.Errors
+	require.ErrorAs(t, err, &fieldErrs)
+	fields := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		assert.Equal(t, "invalid_value", fieldErr.Code)
+		fields = append(fields, fieldErr.Field)
+	}
+	assert.ElementsMatch(t, []string{"timezone", "default_status", "week_start"}, fields)
+
+	prefs, err := service.GetPreferences("uid-1")
+	require.NoError(t, err)
+	assert.Equal(t, users.DefaultPreferences(), prefs)
+
+	_, err = service.UpdatePreferences("missing", users.PreferencesPatch{})
+	assert.ErrorIs(t, err, users.ErrUserNotFound)
+}
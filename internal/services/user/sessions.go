@@ -0,0 +1,72 @@
+package user
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/google/uuid"
+)
+
+// sessionTouchInterval ограничивает частоту записи last_seen при каждом запросе.
+const sessionTouchInterval = time.Minute
+
+// CreateSession регистрирует вход с устройства; ID сессии попадает в JWT.
+func (us *Service) CreateSession(userID, userAgent, ip string, ttl time.Duration) (users.Session, error) {
+	now := time.Now().UTC()
+	session := users.Session{
+		ID:         uuid.New().String(),
+		UID:        userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if err := us.repo.SaveSession(session); err != nil {
+		return users.Session{}, err
+	}
+	return session, nil
+}
+
+// ListSessions возвращает действующие сессии пользователя.
+func (us *Service) ListSessions(userID string) ([]users.Session, error) {
+	sessions, err := us.repo.GetSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	active := make([]users.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.Expired(now) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (us *Service) RevokeSession(userID, sessionID string) error {
+	err := us.repo.DeleteSession(userID, sessionID)
+	us.Record(audit.Event{Type: audit.EventSessionRevoked, ActorUID: userID, Target: sessionID}, err)
+	return err
+}
+
+// CheckSession отклоняет токены отозванных или чужих сессий и обновляет время последней активности.
+func (us *Service) CheckSession(userID, sessionID string) error {
+	session, err := us.repo.GetSession(sessionID)
+	if err != nil {
+		if errors.Is(err, users.ErrSessionNotFound) {
+			return users.ErrTokenRevoked
+		}
+		return err
+	}
+	now := time.Now().UTC()
+	if session.UID != userID || session.Expired(now) {
+		return users.ErrTokenRevoked
+	}
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		return us.repo.TouchSession(sessionID, now)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com"}))
+	service := New(repo)
+
+	laptop, err := service.CreateSession("uid-1", "laptop", "10.0.0.1", time.Hour)
+	require.NoError(t, err)
+	phone, err := service.CreateSession("uid-1", "phone", "10.0.0.2", time.Hour)
+	require.NoError(t, err)
+	_, err = service.CreateSession("uid-1", "old", "10.0.0.3", -time.Minute)
+	require.NoError(t, err)
+
+	list, err := service.ListSessions("uid-1")
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	require.NoError(t, service.CheckSession("uid-1", laptop.ID))
+	assert.ErrorIs(t, service.CheckSession("uid-2", laptop.ID), users.ErrTokenRevoked)
+
+	assert.ErrorIs(t, service.RevokeSession("uid-2", laptop.ID), users.ErrSessionNotFound)
+	require.NoError(t, service.RevokeSession("uid-1", laptop.ID))
+	assert.ErrorIs(t, service.CheckSession("uid-1", laptop.ID), users.ErrTokenRevoked)
+	require.NoError(t, service.CheckSession("uid-1", phone.ID))
+}
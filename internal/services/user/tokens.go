@@ -0,0 +1,124 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/google/uuid"
+)
+
+const (
+	accessTokenBytes = 32
+
+	streamTicketPurpose = "notes-stream"
+	// streamTicketTTL покрывает переподключения EventSource; потом клиент берёт новый билет.
+	streamTicketTTL = time.Minute
+)
+
+// CreateAccessToken выпускает персональный токен. Открытое значение возвращается один раз.
+func (us *Service) CreateAccessToken(
+	userID string,
+	req users.AccessTokenRequest,
+) (created users.CreatedAccessToken, err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventTokenCreated, ActorUID: userID, Target: created.ID}, err)
+	}()
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == `` {
+		return users.CreatedAccessToken{}, users.ErrEmptyTokenName
+	}
+	if len(req.Scopes) == 0 {
+		return users.CreatedAccessToken{}, users.ErrInvalidScope
+	}
+	for _, scope := range req.Scopes {
+		if !scope.Valid() {
+			return users.CreatedAccessToken{}, users.ErrInvalidScope
+		}
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return users.CreatedAccessToken{}, users.ErrTokenExpired
+	}
+
+	raw := make([]byte, accessTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return users.CreatedAccessToken{}, err
+	}
+	plain := users.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token := users.AccessToken{
+		ID:        uuid.New().String(),
+		UID:       userID,
+		Name:      req.Name,
+		Hash:      hashToken(plain),
+		Scopes:    req.Scopes,
+		CreatedAt: now,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := us.repo.SaveAccessToken(token); err != nil {
+		return users.CreatedAccessToken{}, err
+	}
+	return users.CreatedAccessToken{AccessToken: token, Token: plain}, nil
+}
+
+func (us *Service) ListAccessTokens(userID string) ([]users.AccessToken, error) {
+	return us.repo.GetAccessTokens(userID)
+}
+
+func (us *Service) RevokeAccessToken(userID, tokenID string) error {
+	err := us.repo.DeleteAccessToken(userID, tokenID)
+	us.Record(audit.Event{Type: audit.EventTokenRevoked, ActorUID: userID, Target: tokenID}, err)
+	return err
+}
+
+// AuthenticateAccessToken находит токен по хэшу и проверяет срок действия.
+func (us *Service) AuthenticateAccessToken(plain string) (users.AccessToken, error) {
+	if !strings.HasPrefix(plain, users.AccessTokenPrefix) {
+		return users.AccessToken{}, users.ErrInvalidToken
+	}
+	token, err := us.repo.GetAccessTokenByHash(hashToken(plain))
+	if err != nil {
+		return users.AccessToken{}, err
+	}
+	if token.Expired(time.Now()) {
+		return users.AccessToken{}, users.ErrTokenExpired
+	}
+	return token, nil
+}
+
+// IssueStreamTicket выдаёт билет на чтение потока событий. Билет, выданный по JWT, перестаёт
+// действовать вместе с сессией; выданный по персональному токену живёт до истечения streamTicketTTL.
+func (us *Service) IssueStreamTicket(principal users.Principal) (users.StreamTicket, error) {
+	if len(us.signingKey) == 0 {
+		return users.StreamTicket{}, users.ErrSigningKeyNotSet
+	}
+	expiresAt := time.Now().Add(streamTicketTTL).UTC()
+	return users.StreamTicket{
+		Ticket:    us.signToken(streamTicketPurpose, principal.UID, principal.SessionID, expiresAt),
+		ExpiresAt: expiresAt.Truncate(time.Second),
+	}, nil
+}
+
+// AuthenticateStreamTicket проверяет билет; он даёт только право notes:read.
+func (us *Service) AuthenticateStreamTicket(ticket string) (users.Principal, error) {
+	userID, sessionID, err := us.parseToken(streamTicketPurpose, ticket, time.Now())
+	if err != nil {
+		return users.Principal{}, err
+	}
+	if sessionID != `` {
+		if err = us.CheckSession(userID, sessionID); err != nil {
+			return users.Principal{}, err
+		}
+	}
+	return users.Principal{
+		UID:         userID,
+		SessionID:   sessionID,
+		Scopes:      []users.Scope{users.ScopeNotesRead},
+		AccessToken: true,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package user
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessTokens(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com"}))
+	service := New(repo)
+
+	created, err := service.CreateAccessToken("uid-1", users.AccessTokenRequest{
+		Name:   "ci",
+		Scopes: []users.Scope{users.ScopeNotesRead},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, users.AccessTokenPrefix))
+	assert.NotEqual(t, created.Token, created.Hash)
+
+	token, err := service.AuthenticateAccessToken(created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "uid-1", token.UID)
+	assert.True(t, token.HasScope(users.ScopeNotesRead))
+	assert.False(t, token.HasScope(users.ScopeNotesWrite))
+
+	list, err := service.ListAccessTokens("uid-1")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "ci", list[0].Name)
+
+	assert.ErrorIs(t, service.RevokeAccessToken("uid-2", created.ID), users.ErrTokenNotFound)
+	require.NoError(t, service.RevokeAccessToken("uid-1", created.ID))
+
+	_, err = service.AuthenticateAccessToken(created.Token)
+	assert.ErrorIs(t, err, users.ErrTokenNotFound)
+}
+
+func TestCreateAccessTokenValidation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		req  users.AccessTokenRequest
+		want error
+	}{
+		{name: "empty name", req: users.AccessTokenRequest{Scopes: []users.Scope{users.ScopeNotesRead}}, want: users.ErrEmptyTokenName},
+		{name: "no scopes", req: users.AccessTokenRequest{Name: "ci"}, want: users.ErrInvalidScope},
+		{name: "unknown scope", req: users.AccessTokenRequest{Name: "ci", Scopes: []users.Scope{"admin"}}, want: users.ErrInvalidScope},
+		{
+			name: "already expired",
+			req:  users.AccessTokenRequest{Name: "ci", Scopes: []users.Scope{users.ScopeNotesRead}, ExpiresAt: &past},
+			want: users.ErrTokenExpired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(inmemory.NewUsers()).CreateAccessToken("uid-1", tc.req)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestAuthenticateExpiredAccessToken(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1"}))
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, repo.SaveAccessToken(users.AccessToken{
+		ID: "t1", UID: "uid-1", Hash: hashToken("tdl_old"), ExpiresAt: &expired,
+	}))
+
+	_, err := New(repo).AuthenticateAccessToken("tdl_old")
+	assert.ErrorIs(t, err, users.ErrTokenExpired)
+}
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // RFC 6238 по умолчанию использует HMAC-SHA1
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/services/secretbox"
+)
+
+const (
+	totpIssuer      = "ToDoList"
+	totpSecretBytes = 20
+	totpPeriod      = 30
+	totpDigits      = 6
+	totpModulo      = 1_000_000
+	// totpSkew — сколько соседних интервалов принимаем из-за расхождения часов.
+	totpSkew = 1
+)
+
+//nolint:gochecknoglobals // its ok
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func newTOTPSecret() (string, error) {
+	raw := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return ``, err
+	}
+	return totpEncoding.EncodeToString(raw), nil
+}
+
+// totpURI строит otpauth:// ссылку для приложений-аутентификаторов.
+func totpURI(email, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + email,
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
+
+// totpStep — номер интервала RFC 6238, в который попадает at.
+func totpStep(at time.Time) int64 {
+	return at.Unix() / totpPeriod
+}
+
+// totpCode считает код RFC 6238 (HOTP из RFC 4226 по номеру интервала).
+func totpCode(secret string, at time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return ``, err
+	}
+	return hotp(key, uint64(totpStep(at))), nil //nolint:gosec // время после 1970 года
+}
+
+func hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulo)
+}
+
+// matchTOTP возвращает интервал, которому соответствует код.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	for skew := -totpSkew; skew <= totpSkew; skew++ {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		expected, err := totpCode(secret, at)
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return totpStep(at), true
+		}
+	}
+	return 0, false
+}
+
+// encryptSecret шифрует секрет AES-GCM; ключ выводится из ключа сервиса через SHA-256.
+func (us *Service) encryptSecret(secret string) (string, error) {
+	return secretbox.Seal(us.encryptionKey, secret)
+}
+
+func (us *Service) decryptSecret(encrypted string) (string, error) {
+	return secretbox.Open(us.encryptionKey, encrypted)
+}
@@ -0,0 +1,213 @@
+package user
+
+import (
+	"crypto/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+const (
+	pendingLoginTTL     = 5 * time.Minute
+	pendingLoginPurpose = "login-2fa"
+	recoveryCodesCount  = 10
+	recoveryCodeBytes   = 5
+	recoveryCodeHalf    = 4
+)
+
+func WithEncryptionKey(key []byte) Option {
+	return func(us *Service) {
+		us.encryptionKey = key
+	}
+}
+
+// EnrollTOTP создаёт новый секрет; 2FA включится только после ConfirmTOTP.
+// Без ключа шифрования секрет негде безопасно хранить, а без ключа подписи нельзя выдать
+// токен второго шага входа, поэтому 2FA недоступна.
+func (us *Service) EnrollTOTP(userID string) (users.TOTPEnrollment, error) {
+	if len(us.encryptionKey) == 0 || len(us.signingKey) == 0 {
+		return users.TOTPEnrollment{}, users.ErrTOTPNotConfigured
+	}
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return users.TOTPEnrollment{}, err
+	}
+	if user.TOTP.Enabled {
+		return users.TOTPEnrollment{}, users.ErrTOTPEnabled
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return users.TOTPEnrollment{}, err
+	}
+	encrypted, err := us.encryptSecret(secret)
+	if err != nil {
+		return users.TOTPEnrollment{}, err
+	}
+	if err = us.repo.UpdateTOTP(user.UID, users.TOTP{Secret: encrypted}); err != nil {
+		return users.TOTPEnrollment{}, err
+	}
+	return users.TOTPEnrollment{Secret: secret, URI: totpURI(user.Email, secret)}, nil
+}
+
+// ConfirmTOTP включает 2FA по первому верному коду и возвращает одноразовые коды восстановления.
+func (us *Service) ConfirmTOTP(userID, code string) (_ users.RecoveryCodes, err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventTOTPEnabled, ActorUID: userID, Target: userID}, err)
+	}()
+
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return users.RecoveryCodes{}, err
+	}
+	if user.TOTP.Enabled {
+		return users.RecoveryCodes{}, users.ErrTOTPEnabled
+	}
+	if user.TOTP.Secret == `` {
+		return users.RecoveryCodes{}, users.ErrTOTPNotEnrolled
+	}
+
+	secret, err := us.decryptSecret(user.TOTP.Secret)
+	if err != nil {
+		return users.RecoveryCodes{}, err
+	}
+	step, ok := matchTOTP(secret, normalizeCode(code), time.Now())
+	if !ok {
+		return users.RecoveryCodes{}, users.ErrInvalidCode
+	}
+	if err = us.repo.UseTOTPStep(user.UID, step); err != nil {
+		return users.RecoveryCodes{}, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return users.RecoveryCodes{}, err
+	}
+	err = us.repo.UpdateTOTP(user.UID, users.TOTP{Secret: user.TOTP.Secret, Enabled: true, RecoveryCodes: hashes})
+	if err != nil {
+		return users.RecoveryCodes{}, err
+	}
+	return users.RecoveryCodes{Codes: codes}, nil
+}
+
+func (us *Service) DisableTOTP(userID, code string) (err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventTOTPDisabled, ActorUID: userID, Target: userID}, err)
+	}()
+
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTP.Enabled {
+		return users.ErrTOTPNotEnrolled
+	}
+	if err = us.verifySecondFactor(user, code); err != nil {
+		return err
+	}
+	return us.repo.UpdateTOTP(user.UID, users.TOTP{})
+}
+
+// pendingLogin выдаёт короткоживущий токен второго шага входа.
+// Это не JWT, поэтому JWTMiddleware его не примет.
+func (us *Service) pendingLogin(user users.User) error {
+	if len(us.signingKey) == 0 {
+		return users.ErrTOTPNotConfigured
+	}
+	return &users.SecondFactorError{
+		PendingToken: us.signToken(pendingLoginPurpose, user.UID, pendingSubject(user), time.Now().Add(pendingLoginTTL)),
+	}
+}
+
+// pendingSubject привязывает pending-токен к адресу и последнему принятому интервалу TOTP.
+// Успешный второй шаг сдвигает интервал, поэтому токен действует один раз.
+func pendingSubject(user users.User) string {
+	return user.Email + "/" + strconv.FormatInt(user.TOTP.LastStep, 10)
+}
+
+// CompleteSecondFactor проверяет код TOTP или код восстановления. Ошибки учитываются
+// тем же счётчиком попыток, что и неверные пароли.
+func (us *Service) CompleteSecondFactor(req users.SecondFactorRequest, ip string) (uid string, err error) {
+	event := audit.Event{Type: audit.EventLoginSecondFactor, IP: ip}
+	defer func() {
+		event.ActorUID = uid
+		us.Record(event, err)
+	}()
+
+	now := time.Now()
+	userID, subject, err := us.parseToken(pendingLoginPurpose, req.PendingToken, now)
+	if err != nil {
+		return ``, err
+	}
+	email := subject[:max(strings.LastIndexByte(subject, '/'), 0)]
+	event.Target = email
+	if err = us.checkLockout(email, ip, now); err != nil {
+		return ``, err
+	}
+
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return ``, err
+	}
+	if subject != pendingSubject(user) || !user.TOTP.Enabled {
+		return ``, users.ErrInvalidToken
+	}
+
+	if err = us.verifySecondFactor(user, req.Code); err != nil {
+		if lockErr := us.recordFailure(email, ip, now); lockErr != nil {
+			return ``, lockErr
+		}
+		return ``, err
+	}
+
+	if err = us.resetAccountAttempts(email); err != nil {
+		return ``, err
+	}
+	return user.UID, nil
+}
+
+// verifySecondFactor принимает код TOTP не больше одного раза. Код восстановления тоже
+// сдвигает последний интервал, чтобы выданные до него pending-токены перестали действовать.
+func (us *Service) verifySecondFactor(user users.User, code string) error {
+	code = normalizeCode(code)
+	now := time.Now()
+	if len(code) == totpDigits {
+		secret, err := us.decryptSecret(user.TOTP.Secret)
+		if err != nil {
+			return err
+		}
+		step, ok := matchTOTP(secret, code, now)
+		if !ok {
+			return users.ErrInvalidCode
+		}
+		return us.repo.UseTOTPStep(user.UID, step)
+	}
+	if err := us.repo.ConsumeRecoveryCode(user.UID, hashToken(code)); err != nil {
+		return err
+	}
+	return us.repo.UseTOTPStep(user.UID, max(totpStep(now), user.TOTP.LastStep+1))
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	raw := make([]byte, recoveryCodeBytes)
+	for range recoveryCodesCount {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(totpEncoding.EncodeToString(raw))
+		codes = append(codes, code[:recoveryCodeHalf]+"-"+code[recoveryCodeHalf:])
+		hashes = append(hashes, hashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeCode убирает пробелы и дефисы, которые пользователи копируют вместе с кодом.
+func normalizeCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
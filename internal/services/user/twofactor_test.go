@@ -0,0 +1,135 @@
+package user
+
+import (
+	"encoding/base32"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	// Тестовые векторы RFC 6238 (SHA1), последние 6 цифр.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tc := range tests {
+		code, err := totpCode(secret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, code)
+	}
+
+	step, ok := matchTOTP(secret, "287082", time.Unix(59+totpPeriod, 0))
+	assert.True(t, ok, "previous step is accepted")
+	assert.Equal(t, int64(1), step)
+	_, ok = matchTOTP(secret, "287082", time.Unix(59+3*totpPeriod, 0))
+	assert.False(t, ok)
+}
+
+func TestEnrollTOTPRequiresEncryptionKey(t *testing.T) {
+	service := New(nil)
+
+	_, err := service.EnrollTOTP("uid-1")
+	assert.ErrorIs(t, err, users.ErrTOTPNotConfigured)
+}
+
+func TestEnrollTOTPRequiresSigningKey(t *testing.T) {
+	service := New(nil, WithEncryptionKey([]byte("key")), WithVerification(nil, ``, time.Hour))
+
+	_, err := service.EnrollTOTP("uid-1")
+	assert.ErrorIs(t, err, users.ErrTOTPNotConfigured)
+}
+
+func TestEncryptSecret(t *testing.T) {
+	service := New(nil, WithEncryptionKey([]byte("key")))
+
+	encrypted, err := service.encryptSecret("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "JBSWY3DPEHPK3PXP")
+
+	secret, err := service.decryptSecret(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", secret)
+
+	_, err = New(nil, WithEncryptionKey([]byte("other"))).decryptSecret(encrypted)
+	assert.Error(t, err)
+}
+
+func TestTwoFactorFlow(t *testing.T) {
+	repo := inmemory.NewUsers()
+	user := users.User{UID: "uid-1", Email: "bob@example.com", Password: "secret"}
+	require.NoError(t, repo.SaveUser(user))
+
+	service := New(repo, WithEncryptionKey([]byte("key")), WithVerification(testKey, ``, time.Hour))
+
+	enrollment, err := service.EnrollTOTP(user.UID)
+	require.NoError(t, err)
+	uri, err := url.Parse(enrollment.URI)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, enrollment.Secret, uri.Query().Get("secret"))
+
+	stored, err := repo.GetUserID(user.UID)
+	require.NoError(t, err)
+	assert.NotEqual(t, enrollment.Secret, stored.TOTP.Secret, "secret must be encrypted at rest")
+
+	_, err = service.ConfirmTOTP(user.UID, "000000")
+	assert.ErrorIs(t, err, users.ErrInvalidCode)
+
+	code, err := totpCode(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	recovery, err := service.ConfirmTOTP(user.UID, code)
+	require.NoError(t, err)
+	require.Len(t, recovery.Codes, recoveryCodesCount)
+
+	login := func() string {
+		_, err := service.LoginUser(users.UserRequest{Email: user.Email, Password: "secret"}, ``)
+		var pending *users.SecondFactorError
+		require.True(t, errors.As(err, &pending), err)
+		return pending.PendingToken
+	}
+	pending := login()
+
+	// Код, уже принятый при подтверждении, второй раз не подходит.
+	_, err = service.CompleteSecondFactor(users.SecondFactorRequest{PendingToken: pending, Code: code}, ``)
+	assert.ErrorIs(t, err, users.ErrInvalidCode)
+
+	next, err := totpCode(enrollment.Secret, time.Now().Add(totpPeriod*time.Second))
+	require.NoError(t, err)
+	userID, err := service.CompleteSecondFactor(users.SecondFactorRequest{PendingToken: pending, Code: next}, ``)
+	require.NoError(t, err)
+	assert.Equal(t, user.UID, userID)
+
+	// Pending-токен одноразовый.
+	_, err = service.CompleteSecondFactor(users.SecondFactorRequest{PendingToken: pending, Code: recovery.Codes[0]}, ``)
+	assert.ErrorIs(t, err, users.ErrInvalidToken)
+
+	// Код восстановления одноразовый.
+	_, err = service.CompleteSecondFactor(users.SecondFactorRequest{PendingToken: login(), Code: recovery.Codes[0]}, ``)
+	require.NoError(t, err)
+	_, err = service.CompleteSecondFactor(users.SecondFactorRequest{PendingToken: login(), Code: recovery.Codes[0]}, ``)
+	assert.ErrorIs(t, err, users.ErrInvalidCode)
+
+	// Pending-токен нельзя подделать токеном подтверждения почты.
+	verifyToken := service.signToken(verificationPurpose, user.UID, user.Email, time.Now().Add(time.Hour))
+	_, err = service.CompleteSecondFactor(users.SecondFactorRequest{PendingToken: verifyToken, Code: next}, ``)
+	assert.ErrorIs(t, err, users.ErrInvalidToken)
+
+	require.NoError(t, service.DisableTOTP(user.UID, recovery.Codes[1]))
+	userID, err = service.LoginUser(users.UserRequest{Email: user.Email, Password: "secret"}, ``)
+	require.NoError(t, err)
+	assert.Equal(t, user.UID, userID)
+}
@@ -1,7 +1,13 @@
 package user
 
 import (
+	"errors"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/audit"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
 
 	"github.com/google/uuid"
 )
@@ -13,42 +19,140 @@ type Repository interface {
 	GetAllUsers() ([]users.User, error)
 	GetUserID(userID string) (users.User, error)
 	UpdateUserID(userID string, user users.User) error
+	VerifyUser(userID string) error
+	SaveResetToken(token users.ResetToken) error
+	ConsumeResetToken(hash string) (users.ResetToken, error)
+	ResetPassword(userID, password string, changedAt time.Time) error
+	UpdateTOTP(userID string, totp users.TOTP) error
+	ConsumeRecoveryCode(userID, codeHash string) error
+	UseTOTPStep(userID string, step int64) error
+	SaveAccessToken(token users.AccessToken) error
+	GetAccessTokens(userID string) ([]users.AccessToken, error)
+	GetAccessTokenByHash(hash string) (users.AccessToken, error)
+	DeleteAccessToken(userID, tokenID string) error
+	SaveIdentity(identity users.Identity) error
+	GetUserByIdentity(issuer, subject string) (users.User, error)
+	GetIdentities(userID string) ([]users.Identity, error)
+	SaveSession(session users.Session) error
+	GetSessions(userID string) ([]users.Session, error)
+	GetSession(sessionID string) (users.Session, error)
+	TouchSession(sessionID string, lastSeen time.Time) error
+	DeleteSession(userID, sessionID string) error
+	ScheduleDeletion(userID string, deleteAt *time.Time) error
+	GetUsersDueForDeletion(now time.Time) ([]users.User, error)
+	GetPreferences(userID string) (users.Preferences, error)
+	SavePreferences(userID string, prefs users.Preferences) error
 	Close() error
 }
 
+type Mailer interface {
+	Send(msg mail.Message) error
+}
+
 type Service struct {
-	repo Repository
+	repo          Repository
+	mailer        Mailer
+	signingKey    []byte
+	baseURL       string
+	verifyGrace   time.Duration
+	attempts      AttemptStore
+	accountPolicy LockoutPolicy
+	ipPolicy      LockoutPolicy
+	encryptionKey []byte
+	notes         NoteStore
+	deletionGrace time.Duration
+	auditLog      AuditLog
 }
 
-func New(repo Repository) *Service {
-	return &Service{repo: repo}
+type Option func(*Service)
+
+func WithMailer(mailer Mailer) Option {
+	return func(us *Service) {
+		us.mailer = mailer
+	}
 }
 
-func (us *Service) RegisterUser(user users.User) (string, error) {
+// WithVerification задаёт ключ подписи ссылок, адрес сервиса для ссылок и льготный период.
+func WithVerification(signingKey []byte, baseURL string, grace time.Duration) Option {
+	return func(us *Service) {
+		us.signingKey = signingKey
+		us.baseURL = baseURL
+		us.verifyGrace = grace
+	}
+}
+
+func New(repo Repository, opts ...Option) *Service {
+	us := &Service{
+		repo:          repo,
+		accountPolicy: defaultAccountPolicy(),
+		ipPolicy:      defaultIPPolicy(),
+	}
+	for _, opt := range opts {
+		opt(us)
+	}
+	return us
+}
+
+func (us *Service) RegisterUser(user users.User) (uid string, err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventRegister, ActorUID: uid, Target: user.Email}, err)
+	}()
+
+	user.Email = users.NormalizeEmail(user.Email)
+	if err := validation.Struct(user); err != nil {
+		return ``, err
+	}
 	user.UID = uuid.New().String()
+	user.Verified = false
+	user.CreatedAt = time.Now().UTC()
 
-	err := us.repo.SaveUser(user)
+	err = us.repo.SaveUser(user)
 	if err != nil {
 		return ``, err
 	}
 	return user.UID, nil
 }
 
-func (us *Service) LoginUser(userCreds users.UserRequest) (string, error) {
+// LoginUser проверяет пароль; неудачи считаются по аккаунту и по IP, после лимита вход блокируется.
+func (us *Service) LoginUser(userCreds users.UserRequest, ip string) (uid string, err error) {
+	defer func() {
+		us.Record(audit.Event{Type: audit.EventLogin, ActorUID: uid, Target: userCreds.Email, IP: ip}, err)
+	}()
+
+	userCreds.Email = users.NormalizeEmail(userCreds.Email)
+	if err := validation.Struct(userCreds); err != nil {
+		return ``, err
+	}
+	now := time.Now()
+	if err := us.checkLockout(userCreds.Email, ip, now); err != nil {
+		return ``, err
+	}
+
 	dbUser, err := us.repo.GetUser(userCreds.Email)
+	// Пустой пароль у аккаунтов, созданных через OIDC: вход по паролю для них закрыт.
+	if err == nil && (dbUser.Password == `` || dbUser.Password != userCreds.Password) {
+		err = users.ErrInvalidUserCreds
+	}
 	if err != nil {
+		if lockErr := us.recordFailure(userCreds.Email, ip, now); lockErr != nil {
+			return ``, lockErr
+		}
 		return ``, err
 	}
 
-	if dbUser.Password != userCreds.Password {
-		return ``, users.ErrInvalidUserCreds
+	// С включённой 2FA счётчик сбрасывается только после второго шага,
+	// иначе знание пароля позволило бы перебирать коды без блокировки.
+	if dbUser.TOTP.Enabled {
+		return ``, us.pendingLogin(dbUser)
+	}
+	if err = us.resetAccountAttempts(userCreds.Email); err != nil {
+		return ``, err
 	}
-
 	return dbUser.UID, nil
 }
 
 func (us *Service) DeleteUserID(userID string) error {
-	err := us.repo.DeleteUser(userID)
+	err := us.purgeUser(userID)
 	if err != nil {
 		return err
 	}
@@ -67,10 +171,45 @@ func (us *Service) GetUser(userID string) (users.User, error) {
 	return user, nil
 }
 
+// UpdateUser меняет профиль; пароль здесь не обновляется и потому не проверяется.
+// Новый адрес считается неподтверждённым: хранилище сбрасывает Verified, а на адрес
+// уходит письмо со ссылкой подтверждения.
 func (us *Service) UpdateUser(userID string, user users.User) error {
-	err := us.repo.UpdateUserID(userID, user)
+	user.Email = users.NormalizeEmail(user.Email)
+	if err := validation.StructExcept(user, "Password"); err != nil {
+		return err
+	}
+	stored, err := us.repo.GetUserID(userID)
 	if err != nil {
 		return err
 	}
+	if err = us.repo.UpdateUserID(userID, user); err != nil {
+		return err
+	}
+	if user.Email == users.NormalizeEmail(stored.Email) {
+		return nil
+	}
+	err = us.SendVerification(userID)
+	if err != nil && !errors.Is(err, users.ErrMailerNotSet) && !errors.Is(err, users.ErrSigningKeyNotSet) {
+		return err
+	}
 	return nil
 }
+
+// UpdateProfile меняет только переданные поля профиля.
+func (us *Service) UpdateProfile(userID string, patch users.ProfilePatch) (users.User, error) {
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return users.User{}, err
+	}
+	if patch.Name != nil {
+		user.Name = *patch.Name
+	}
+	if patch.Email != nil {
+		user.Email = *patch.Email
+	}
+	if err = us.UpdateUser(userID, user); err != nil {
+		return users.User{}, err
+	}
+	return us.repo.GetUserID(userID)
+}
@@ -3,11 +3,15 @@ package user
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/mailer"
 	"github.com/Snoop-Duck/ToDoList/internal/services/user/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoginUser(t *testing.T) {
@@ -27,14 +31,14 @@ func TestLoginUser(t *testing.T) {
 		{
 			name: "test 1: success call",
 			userReq: users.UserRequest{
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			user: users.User{
 				UID:      "uuid",
 				Name:     "John Doe",
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			want: want{
 				userID: "uuid",
@@ -44,13 +48,13 @@ func TestLoginUser(t *testing.T) {
 		{
 			name: "test 2: fail call",
 			userReq: users.UserRequest{
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			user: users.User{
 				UID:      "uuid",
 				Name:     "John Doe",
-				Email:    "email",
+				Email:    "john@example.com",
 				Password: "password1234",
 			},
 			want: want{
@@ -67,7 +71,7 @@ func TestLoginUser(t *testing.T) {
 
 			testUserService := New(repoMock)
 
-			userID, err := testUserService.LoginUser(tc.userReq)
+			userID, err := testUserService.LoginUser(tc.userReq, "")
 			if tc.want.err != nil {
 				assert.ErrorIs(t, err, tc.want.err)
 				return
@@ -96,8 +100,8 @@ func TestRegisterUser(t *testing.T) {
 			user: users.User{
 				UID:      "uuid-1234-55rr",
 				Name:     "John Doe",
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			want: want{
 				err: nil,
@@ -108,8 +112,8 @@ func TestRegisterUser(t *testing.T) {
 			user: users.User{
 				UID:      "uuid-1234-55rr",
 				Name:     "John Doe",
-				Email:    "email",
-				Password: "password",
+				Email:    "john@example.com",
+				Password: "password1",
 			},
 			want: want{
 				err: users.ErrUserAlredyExists,
@@ -141,6 +145,25 @@ func TestRegisterUser(t *testing.T) {
 	}
 }
 
+func TestEmailIsCaseInsensitive(t *testing.T) {
+	repo := inmemory.NewUsers()
+	service := New(repo)
+
+	userID, err := service.RegisterUser(users.User{Name: "Bob", Email: "  Bob@X.io ", Password: "password1"})
+	require.NoError(t, err)
+
+	stored, err := repo.GetUserID(userID)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@x.io", stored.Email)
+
+	_, err = service.RegisterUser(users.User{Name: "Bob", Email: "bob@x.io", Password: "password1"})
+	assert.ErrorIs(t, err, users.ErrUserAlredyExists)
+
+	loggedIn, err := service.LoginUser(users.UserRequest{Email: "BOB@x.io", Password: "password1"}, ``)
+	require.NoError(t, err)
+	assert.Equal(t, userID, loggedIn)
+}
+
 func TestDeleteUserID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -314,7 +337,8 @@ func TestUpdateUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repoMock := mocks.NewRepository(t)
-			repoMock.On("UpdateUserID", tt.userID, tt.user).Return(tt.mockErr)
+			repoMock.On("GetUserID", tt.userID).Return(users.User{UID: tt.userID, Email: tt.user.Email}, tt.mockErr)
+			repoMock.On("UpdateUserID", tt.userID, tt.user).Return(nil).Maybe()
 
 			service := New(repoMock)
 			err := service.UpdateUser(tt.userID, tt.user)
@@ -327,3 +351,50 @@ func TestUpdateUser(t *testing.T) {
 		})
 	}
 }
+
+func TestLoginExternal(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Email: "bob@example.com", Password: "secret"}))
+	service := New(repo)
+
+	identity := users.Identity{Issuer: "https://id.example.com", Subject: "bob", Email: "bob@example.com"}
+
+	_, err := service.LoginExternal(identity)
+	assert.ErrorIs(t, err, users.ErrUserAlredyExists, "unverified email must not take over an account")
+
+	identity.EmailVerified = true
+	userID, err := service.LoginExternal(identity)
+	require.NoError(t, err)
+	assert.Equal(t, "uid-1", userID)
+
+	created, err := service.LoginExternal(users.Identity{
+		Issuer: "https://id.example.com", Subject: "carol", Email: "carol@example.com", Name: "Carol",
+	})
+	require.NoError(t, err)
+	user, err := repo.GetUserID(created)
+	require.NoError(t, err)
+	assert.Equal(t, "Carol", user.Name)
+
+	// Аккаунт без пароля не открывается пустым паролем.
+	_, err = service.LoginUser(users.UserRequest{Email: "carol@example.com"}, ``)
+	assert.ErrorIs(t, err, users.ErrInvalidUserCreds)
+}
+
+func TestUpdateUserEmailNeedsVerification(t *testing.T) {
+	repo := inmemory.NewUsers()
+	require.NoError(t, repo.SaveUser(users.User{UID: "uid-1", Name: "Bob", Email: "bob@example.com", Verified: true}))
+	memMailer := mailer.NewMemory()
+	service := New(repo, WithMailer(memMailer), WithVerification(testKey, "http://notes.test", time.Hour))
+
+	name := "Robert"
+	_, err := service.UpdateProfile("uid-1", users.ProfilePatch{Name: &name})
+	require.NoError(t, err)
+	require.Empty(t, memMailer.Messages())
+
+	email := "Robert@Example.com"
+	user, err := service.UpdateProfile("uid-1", users.ProfilePatch{Email: &email})
+	require.NoError(t, err)
+	assert.False(t, user.Verified, "a new address is not verified")
+	require.Len(t, memMailer.Messages(), 1)
+	assert.Equal(t, "robert@example.com", memMailer.Messages()[0].To)
+}
@@ -0,0 +1,128 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/mail"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+)
+
+const (
+	verificationTTL     = 24 * time.Hour
+	verificationPurpose = "verify-email"
+	tokenParts          = 3
+)
+
+// SendVerification отправляет пользователю подписанную ссылку подтверждения почты.
+func (us *Service) SendVerification(userID string) error {
+	if us.mailer == nil {
+		return users.ErrMailerNotSet
+	}
+	if len(us.signingKey) == 0 {
+		return users.ErrSigningKeyNotSet
+	}
+
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return err
+	}
+	if user.Verified {
+		return users.ErrAlreadyVerified
+	}
+
+	token := us.signToken(verificationPurpose, user.UID, user.Email, time.Now().Add(verificationTTL))
+	link := strings.TrimRight(us.baseURL, "/") + "/users/verify?token=" + url.QueryEscape(token)
+	return us.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body: "Hi " + user.Name + ",\n\n" +
+			"Please confirm your email address by opening the link below:\n\n" + link + "\n\n" +
+			"The link expires in 24 hours.\n",
+	})
+}
+
+// VerifyEmail помечает почту подтверждённой, если токен подписан нами, не истёк
+// и выдан на текущий адрес пользователя.
+func (us *Service) VerifyEmail(token string) (string, error) {
+	userID, email, err := us.parseToken(verificationPurpose, token, time.Now())
+	if err != nil {
+		return ``, err
+	}
+
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return ``, err
+	}
+	if user.Email != email {
+		return ``, users.ErrInvalidToken
+	}
+	if user.Verified {
+		return user.UID, nil
+	}
+
+	if err = us.repo.VerifyUser(user.UID); err != nil {
+		return ``, err
+	}
+	return user.UID, nil
+}
+
+// CheckVerified возвращает ErrEmailNotVerified, если льготный период пользователя истёк.
+// Без ключа подписи подтвердить почту нельзя, поэтому проверка отключена.
+func (us *Service) CheckVerified(userID string) error {
+	if len(us.signingKey) == 0 {
+		return nil
+	}
+	user, err := us.repo.GetUserID(userID)
+	if err != nil {
+		return err
+	}
+	if user.VerificationOverdue(us.verifyGrace, time.Now()) {
+		return users.ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (us *Service) signToken(purpose, userID, subject string, expiresAt time.Time) string {
+	payload := strings.Join([]string{userID, subject, strconv.FormatInt(expiresAt.Unix(), 10)}, "|")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(us.sign(purpose, encoded))
+}
+
+func (us *Service) parseToken(purpose, token string, now time.Time) (string, string, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || len(us.signingKey) == 0 {
+		return ``, ``, users.ErrInvalidToken
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, us.sign(purpose, encoded)) {
+		return ``, ``, users.ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ``, ``, users.ErrInvalidToken
+	}
+	parts := strings.Split(string(payload), "|")
+	if len(parts) != tokenParts {
+		return ``, ``, users.ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return ``, ``, users.ErrInvalidToken
+	}
+	return parts[0], parts[1], nil
+}
+
+// sign подмешивает назначение токена, чтобы токен одного назначения нельзя было использовать для другого.
+func (us *Service) sign(purpose, payload string) []byte {
+	mac := hmac.New(sha256.New, us.signingKey)
+	mac.Write([]byte(purpose + ":" + payload))
+	return mac.Sum(nil)
+}
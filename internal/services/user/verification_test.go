@@ -0,0 +1,121 @@
+package user
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/users"
+	"github.com/Snoop-Duck/ToDoList/internal/infrastructure/mailer"
+	"github.com/Snoop-Duck/ToDoList/internal/services/user/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("test-key") //nolint:gochecknoglobals // its ok
+
+func TestSendVerificationAndVerify(t *testing.T) {
+	user := users.User{UID: "uid-1", Name: "Bob", Email: "bob@example.com"}
+
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUserID", user.UID).Return(user, nil)
+	repoMock.On("VerifyUser", user.UID).Return(nil)
+
+	memMailer := mailer.NewMemory()
+	service := New(repoMock, WithMailer(memMailer), WithVerification(testKey, "http://notes.test/", time.Hour))
+
+	require.NoError(t, service.SendVerification(user.UID))
+
+	messages := memMailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, user.Email, messages[0].To)
+
+	_, link, ok := strings.Cut(messages[0].Body, "http://notes.test/users/verify?token=")
+	require.True(t, ok)
+	token, err := url.QueryUnescape(strings.Fields(link)[0])
+	require.NoError(t, err)
+
+	userID, err := service.VerifyEmail(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.UID, userID)
+}
+
+func TestVerifyEmailRejectsBadTokens(t *testing.T) {
+	user := users.User{UID: "uid-1", Email: "bob@example.com"}
+	service := New(mocks.NewRepository(t), WithVerification(testKey, ``, time.Hour))
+
+	expired := service.signToken(verificationPurpose, user.UID, user.Email, time.Now().Add(-time.Minute))
+	foreign := New(nil, WithVerification([]byte("other"), ``, 0)).
+		signToken(verificationPurpose, user.UID, user.Email, time.Now().Add(time.Hour))
+	otherPurpose := service.signToken("reset-password", user.UID, user.Email, time.Now().Add(time.Hour))
+
+	for _, token := range []string{``, "garbage", expired, foreign, otherPurpose} {
+		_, err := service.VerifyEmail(token)
+		assert.ErrorIs(t, err, users.ErrInvalidToken)
+	}
+}
+
+func TestVerifyEmailChangedAddress(t *testing.T) {
+	repoMock := mocks.NewRepository(t)
+	repoMock.On("GetUserID", "uid-1").Return(users.User{UID: "uid-1", Email: "new@example.com"}, nil)
+
+	service := New(repoMock, WithVerification(testKey, ``, time.Hour))
+	token := service.signToken(verificationPurpose, "uid-1", "old@example.com", time.Now().Add(time.Hour))
+
+	_, err := service.VerifyEmail(token)
+	assert.ErrorIs(t, err, users.ErrInvalidToken)
+}
+
+func TestSendVerification(t *testing.T) {
+	t.Run("mailer not set", func(t *testing.T) {
+		service := New(mocks.NewRepository(t))
+		assert.ErrorIs(t, service.SendVerification("uid-1"), users.ErrMailerNotSet)
+	})
+
+	t.Run("signing key not set", func(t *testing.T) {
+		service := New(mocks.NewRepository(t), WithMailer(mailer.NewMemory()), WithVerification(nil, ``, time.Hour))
+		assert.ErrorIs(t, service.SendVerification("uid-1"), users.ErrSigningKeyNotSet)
+	})
+
+	t.Run("already verified", func(t *testing.T) {
+		repoMock := mocks.NewRepository(t)
+		repoMock.On("GetUserID", "uid-1").Return(users.User{UID: "uid-1", Verified: true}, nil)
+
+		service := New(repoMock, WithMailer(mailer.NewMemory()), WithVerification(testKey, ``, time.Hour))
+		assert.ErrorIs(t, service.SendVerification("uid-1"), users.ErrAlreadyVerified)
+	})
+}
+
+func TestCheckVerified(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		user users.User
+		want error
+	}{
+		{name: "verified", user: users.User{Verified: true, CreatedAt: now.Add(-48 * time.Hour)}},
+		{name: "within grace", user: users.User{CreatedAt: now.Add(-time.Minute)}},
+		{name: "grace expired", user: users.User{CreatedAt: now.Add(-48 * time.Hour)}, want: users.ErrEmailNotVerified},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := mocks.NewRepository(t)
+			repoMock.On("GetUserID", "uid-1").Return(tc.user, nil)
+
+			service := New(repoMock, WithVerification(testKey, ``, 24*time.Hour))
+			err := service.CheckVerified("uid-1")
+			if tc.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+
+	t.Run("disabled without signing key", func(t *testing.T) {
+		service := New(mocks.NewRepository(t), WithVerification(nil, ``, 24*time.Hour))
+		assert.NoError(t, service.CheckVerified("uid-1"))
+	})
+}
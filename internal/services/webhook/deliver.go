@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	"github.com/Snoop-Duck/ToDoList/internal/services/secretbox"
+)
+
+const (
+	deliveryTimeout = 10 * time.Second
+	// deliveryLease больше deliveryTimeout: доставку не заберут повторно, пока она отправляется.
+	deliveryLease = time.Minute
+	pollInterval  = 5 * time.Second
+	claimBatch    = 20
+	// maxErrorLength ограничивает сохраняемый текст ошибки.
+	maxErrorLength = 512
+)
+
+// nonPublicPrefixes дополняют проверки netip: «эта сеть» и адреса операторского NAT.
+//
+//nolint:gochecknoglobals // its ok
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Doer отправляет HTTP-запрос; *http.Client подходит.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// newClient собирает клиент доставок. Адрес проверяется при соединении, уже после
+// разрешения имени, поэтому DNS-запись на внутренний адрес не откроет внутреннюю сеть.
+// Прокси не используется: через него проверка адреса теряет смысл.
+func newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: publicOnly}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: deliveryTimeout,
+			ForceAttemptHTTP2:   true,
+		},
+		Timeout: deliveryTimeout,
+		// Перенаправления не выполняются: подпись выдана для адреса подписки.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// publicOnly запрещает соединения с loopback, частными, link-local и прочими немаршрутизируемыми адресами.
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("%w: %s", webhooks.ErrForbiddenAddress, ip)
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", webhooks.ErrForbiddenAddress, ip)
+		}
+	}
+	return nil
+}
+
+// Run отправляет доставки из очереди, пока не отменён ctx.
+func (ws *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		ws.DeliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-ws.wake:
+		}
+	}
+}
+
+// DeliverDue отправляет все доставки, время которых подошло, и возвращает их число.
+func (ws *Service) DeliverDue(ctx context.Context) int {
+	sent := 0
+	for ctx.Err() == nil {
+		claimed, err := ws.store.ClaimDeliveries(time.Now().UTC(), deliveryLease, claimBatch)
+		if err != nil {
+			ws.log.Error().Err(err).Msg("failed to claim webhook deliveries")
+			return sent
+		}
+		for _, delivery := range claimed {
+			ws.deliver(ctx, delivery)
+		}
+		sent += len(claimed)
+		if len(claimed) < claimBatch {
+			return sent
+		}
+	}
+	return sent
+}
+
+func (ws *Service) deliver(ctx context.Context, delivery webhooks.Delivery) {
+	code, err := ws.send(ctx, delivery)
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseCode = code
+	delivery.LastError = ``
+
+	switch {
+	case err == nil:
+		delivery.Status = webhooks.StatusDelivered
+	case delivery.Attempts >= ws.policy.MaxAttempts:
+		delivery.Status = webhooks.StatusDead
+		delivery.LastError = truncate(err.Error())
+	default:
+		delivery.Status = webhooks.StatusPending
+		delivery.LastError = truncate(err.Error())
+		delivery.NextAttemptAt = now.Add(ws.policy.Backoff(delivery.Attempts))
+	}
+	if err != nil {
+		ws.log.Warn().Err(err).
+			Str("delivery", delivery.ID).
+			Int("attempts", delivery.Attempts).
+			Str("status", string(delivery.Status)).
+			Msg("webhook delivery failed")
+	}
+	if updateErr := ws.store.UpdateDelivery(delivery); updateErr != nil {
+		ws.log.Error().Err(updateErr).Str("delivery", delivery.ID).Msg("failed to save webhook delivery")
+	}
+}
+
+// send подписывает и отправляет тело доставки; успехом считается любой ответ 2xx.
+func (ws *Service) send(ctx context.Context, delivery webhooks.Delivery) (int, error) {
+	hook, err := ws.store.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		return 0, err
+	}
+	secret, err := secretbox.Open(ws.encryptionKey, hook.Secret)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ToDoList-Webhooks")
+	req.Header.Set(webhooks.EventHeader, string(delivery.Event))
+	req.Header.Set(webhooks.DeliveryHeader, delivery.ID)
+	req.Header.Set(webhooks.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(secret, timestamp, delivery.Payload))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp.StatusCode, nil
+	}
+	// Тело ответа не сохраняется: через last_error получатель мог бы показать владельцу
+	// подписки содержимое внутреннего сервиса.
+	return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+func truncate(message string) string {
+	if len(message) <= maxErrorLength {
+		return message
+	}
+	return message[:maxErrorLength]
+}
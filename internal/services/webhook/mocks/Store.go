@@ -0,0 +1,248 @@
+// Code generated by mockery v2.53.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webhooks "github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// ClaimDeliveries provides a mock function with given fields: now, lease, limit
+func (_m *Store) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]webhooks.Delivery, error) {
+	ret := _m.Called(now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDeliveries")
+	}
+
+	var r0 []webhooks.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) ([]webhooks.Delivery, error)); ok {
+		return rf(now, lease, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) []webhooks.Delivery); ok {
+		r0 = rf(now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]webhooks.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Duration, int) error); ok {
+		r1 = rf(now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: userID, webhookID
+func (_m *Store) DeleteWebhook(userID string, webhookID string) error {
+	ret := _m.Called(userID, webhookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userID, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnqueueDeliveries provides a mock function with given fields: deliveries
+func (_m *Store) EnqueueDeliveries(deliveries []webhooks.Delivery) error {
+	ret := _m.Called(deliveries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]webhooks.Delivery) error); ok {
+		r0 = rf(deliveries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeliveries provides a mock function with given fields: filter
+func (_m *Store) GetDeliveries(filter webhooks.DeliveryFilter) ([]webhooks.Delivery, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveries")
+	}
+
+	var r0 []webhooks.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(webhooks.DeliveryFilter) ([]webhooks.Delivery, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(webhooks.DeliveryFilter) []webhooks.Delivery); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]webhooks.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(webhooks.DeliveryFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelivery provides a mock function with given fields: deliveryID
+func (_m *Store) GetDelivery(deliveryID string) (webhooks.Delivery, error) {
+	ret := _m.Called(deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelivery")
+	}
+
+	var r0 webhooks.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (webhooks.Delivery, error)); ok {
+		return rf(deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(string) webhooks.Delivery); ok {
+		r0 = rf(deliveryID)
+	} else {
+		r0 = ret.Get(0).(webhooks.Delivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhook provides a mock function with given fields: webhookID
+func (_m *Store) GetWebhook(webhookID string) (webhooks.Webhook, error) {
+	ret := _m.Called(webhookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhook")
+	}
+
+	var r0 webhooks.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (webhooks.Webhook, error)); ok {
+		return rf(webhookID)
+	}
+	if rf, ok := ret.Get(0).(func(string) webhooks.Webhook); ok {
+		r0 = rf(webhookID)
+	} else {
+		r0 = ret.Get(0).(webhooks.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(webhookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhooks provides a mock function with given fields: userID
+func (_m *Store) GetWebhooks(userID string) ([]webhooks.Webhook, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooks")
+	}
+
+	var r0 []webhooks.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]webhooks.Webhook, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []webhooks.Webhook); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]webhooks.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveWebhook provides a mock function with given fields: hook
+func (_m *Store) SaveWebhook(hook webhooks.Webhook) error {
+	ret := _m.Called(hook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(webhooks.Webhook) error); ok {
+		r0 = rf(hook)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDelivery provides a mock function with given fields: delivery
+func (_m *Store) UpdateDelivery(delivery webhooks.Delivery) error {
+	ret := _m.Called(delivery)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(webhooks.Delivery) error); ok {
+		r0 = rf(delivery)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,254 @@
+// Package webhook управляет подписками пользователей и доставляет им события заметок.
+// Доставки сначала пишутся в очередь хранилища, а отправляет их фоновый Run, поэтому
+// недоставленное переживает перезапуск сервиса.
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/validation"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	"github.com/Snoop-Duck/ToDoList/internal/services/secretbox"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+)
+
+type Store interface {
+	SaveWebhook(hook webhooks.Webhook) error
+	GetWebhooks(userID string) ([]webhooks.Webhook, error)
+	GetWebhook(webhookID string) (webhooks.Webhook, error)
+	// DeleteWebhook удаляет подписку вместе с её доставками.
+	DeleteWebhook(userID, webhookID string) error
+	EnqueueDeliveries(deliveries []webhooks.Delivery) error
+	// ClaimDeliveries выдаёт до limit ожидающих доставок, время которых подошло, и откладывает
+	// их на lease: если процесс упадёт посреди отправки, доставка повторится после перезапуска.
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]webhooks.Delivery, error)
+	UpdateDelivery(delivery webhooks.Delivery) error
+	// GetDeliveries возвращает подходящие доставки, начиная с самых новых.
+	GetDeliveries(filter webhooks.DeliveryFilter) ([]webhooks.Delivery, error)
+	GetDelivery(deliveryID string) (webhooks.Delivery, error)
+}
+
+type Service struct {
+	store         Store
+	client        Doer
+	policy        webhooks.RetryPolicy
+	encryptionKey []byte
+	wake          chan struct{}
+	log           zerolog.Logger
+}
+
+type Option func(*Service)
+
+// WithEncryptionKey задаёт ключ, которым секреты подписок шифруются в хранилище.
+func WithEncryptionKey(key []byte) Option {
+	return func(ws *Service) {
+		ws.encryptionKey = key
+	}
+}
+
+func WithRetryPolicy(policy webhooks.RetryPolicy) Option {
+	return func(ws *Service) {
+		ws.policy = policy
+	}
+}
+
+// WithClient подменяет HTTP-клиент доставок, например в тестах.
+func WithClient(client Doer) Option {
+	return func(ws *Service) {
+		ws.client = client
+	}
+}
+
+func WithLogger(log zerolog.Logger) Option {
+	return func(ws *Service) {
+		ws.log = log
+	}
+}
+
+func New(store Store, opts ...Option) *Service {
+	ws := &Service{
+		store:  store,
+		client: newClient(),
+		policy: webhooks.DefaultRetryPolicy(),
+		wake:   make(chan struct{}, 1),
+		log:    zerolog.Nop(),
+	}
+	for _, opt := range opts {
+		opt(ws)
+	}
+	return ws
+}
+
+func (ws *Service) CreateWebhook(userID string, req webhooks.WebhookRequest) (webhooks.CreatedWebhook, error) {
+	if err := validation.Struct(req); err != nil {
+		return webhooks.CreatedWebhook{}, err
+	}
+	existing, err := ws.store.GetWebhooks(userID)
+	if err != nil {
+		return webhooks.CreatedWebhook{}, err
+	}
+	if len(existing) >= webhooks.MaxPerUser {
+		return webhooks.CreatedWebhook{}, webhooks.ErrTooManyWebhooks
+	}
+
+	secret := req.Secret
+	if secret == `` {
+		raw := make([]byte, secretBytes)
+		if _, err = rand.Read(raw); err != nil {
+			return webhooks.CreatedWebhook{}, err
+		}
+		secret = secretPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	}
+	encrypted, err := secretbox.Seal(ws.encryptionKey, secret)
+	if err != nil {
+		return webhooks.CreatedWebhook{}, err
+	}
+
+	events := slices.Clone(req.Events)
+	slices.Sort(events)
+	hook := webhooks.Webhook{
+		ID:        uuid.New().String(),
+		UID:       userID,
+		URL:       req.URL,
+		Events:    slices.Compact(events),
+		Secret:    encrypted,
+		CreatedAt: time.Now().UTC(),
+	}
+	if hook.Events == nil {
+		hook.Events = []notes.EventType{}
+	}
+	if err = ws.store.SaveWebhook(hook); err != nil {
+		return webhooks.CreatedWebhook{}, err
+	}
+	return webhooks.CreatedWebhook{Webhook: hook, Secret: secret}, nil
+}
+
+func (ws *Service) ListWebhooks(userID string) ([]webhooks.Webhook, error) {
+	return ws.store.GetWebhooks(userID)
+}
+
+// GetWebhook возвращает подписку владельца; чужая неотличима от отсутствующей.
+func (ws *Service) GetWebhook(userID, webhookID string) (webhooks.Webhook, error) {
+	hook, err := ws.store.GetWebhook(webhookID)
+	if err != nil {
+		return webhooks.Webhook{}, err
+	}
+	if hook.UID != userID {
+		return webhooks.Webhook{}, webhooks.ErrWebhookNotFound
+	}
+	return hook, nil
+}
+
+func (ws *Service) DeleteWebhook(userID, webhookID string) error {
+	return ws.store.DeleteWebhook(userID, webhookID)
+}
+
+// ListDeliveries возвращает историю доставок подписки.
+func (ws *Service) ListDeliveries(userID, webhookID string, filter webhooks.DeliveryFilter) ([]webhooks.Delivery, error) {
+	if _, err := ws.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	filter.UID = userID
+	filter.WebhookID = webhookID
+	return ws.store.GetDeliveries(filter)
+}
+
+// ListDeadLetters возвращает доставки пользователя, для которых исчерпаны попытки.
+func (ws *Service) ListDeadLetters(userID string, filter webhooks.DeliveryFilter) ([]webhooks.Delivery, error) {
+	filter.UID = userID
+	filter.Status = webhooks.StatusDead
+	return ws.store.GetDeliveries(filter)
+}
+
+// RetryDelivery возвращает недоставленное событие в очередь с новым запасом попыток.
+func (ws *Service) RetryDelivery(userID, deliveryID string) (webhooks.Delivery, error) {
+	delivery, err := ws.store.GetDelivery(deliveryID)
+	if err != nil {
+		return webhooks.Delivery{}, err
+	}
+	if delivery.UID != userID {
+		return webhooks.Delivery{}, webhooks.ErrDeliveryNotFound
+	}
+	if delivery.Status != webhooks.StatusDead {
+		return webhooks.Delivery{}, webhooks.ErrDeliveryNotDead
+	}
+	delivery.Status = webhooks.StatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err = ws.store.UpdateDelivery(delivery); err != nil {
+		return webhooks.Delivery{}, err
+	}
+	ws.notify()
+	return delivery, nil
+}
+
+// Publish ставит событие в очередь для подписок владельца заметки. Событие без владельца
+// не доставляется никому: чужие заметки подписчикам не принадлежат. Ошибка хранилища
+// только логируется: из-за неё не должно откатываться изменение заметки.
+func (ws *Service) Publish(event notes.Event) {
+	if event.UID == `` {
+		return
+	}
+	hooks, err := ws.store.GetWebhooks(event.UID)
+	if err != nil {
+		ws.log.Error().Err(err).Str("nid", event.NID).Msg("failed to find webhooks for event")
+		return
+	}
+
+	now := time.Now().UTC()
+	payload := notes.NewEventPayload(event, time.UTC)
+	deliveries := make([]webhooks.Delivery, 0, len(hooks))
+	for _, hook := range hooks {
+		if !hook.Subscribed(event) {
+			continue
+		}
+		delivery := webhooks.Delivery{
+			ID:            uuid.New().String(),
+			WebhookID:     hook.ID,
+			UID:           hook.UID,
+			Event:         event.Type,
+			NID:           event.NID,
+			Status:        webhooks.StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		delivery.Payload, err = json.Marshal(webhooks.Envelope{
+			DeliveryID: delivery.ID,
+			WebhookID:  hook.ID,
+			Event:      payload,
+		})
+		if err != nil {
+			ws.log.Error().Err(err).Msg("failed to encode webhook payload")
+			return
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	if err = ws.store.EnqueueDeliveries(deliveries); err != nil {
+		ws.log.Error().Err(err).Str("nid", event.NID).Msg("failed to enqueue webhook deliveries")
+		return
+	}
+	ws.notify()
+}
+
+// notify будит Run, не дожидаясь очередного опроса очереди.
+func (ws *Service) notify() {
+	select {
+	case ws.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Snoop-Duck/ToDoList/internal/domain/notes"
+	"github.com/Snoop-Duck/ToDoList/internal/domain/webhooks"
+	inmemory "github.com/Snoop-Duck/ToDoList/internal/infrastructure/in-memory"
+	"github.com/Snoop-Duck/ToDoList/internal/services/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("test-encryption-key")
+
+func TestService_CreateWebhook(t *testing.T) {
+	t.Run("generates secret", func(t *testing.T) {
+		store := mocks.NewStore(t)
+		service := New(store, WithEncryptionKey(testKey))
+
+		store.On("GetWebhooks", "alice").Return([]webhooks.Webhook{}, nil)
+		store.On("SaveWebhook", mock.MatchedBy(func(hook webhooks.Webhook) bool {
+			return hook.UID == "alice" && hook.Secret != `` && !strings.HasPrefix(hook.Secret, secretPrefix)
+		})).Return(nil)
+
+		created, err := service.CreateWebhook("alice", webhooks.WebhookRequest{
+			URL:    "https://example.com/hook",
+			Events: []notes.EventType{notes.EventDeleted, notes.EventCreated, notes.EventDeleted},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Secret, secretPrefix))
+		assert.Equal(t, []notes.EventType{notes.EventCreated, notes.EventDeleted}, created.Events)
+	})
+
+	t.Run("limit per user", func(t *testing.T) {
+		store := mocks.NewStore(t)
+		service := New(store, WithEncryptionKey(testKey))
+
+		store.On("GetWebhooks", "alice").Return(make([]webhooks.Webhook, webhooks.MaxPerUser), nil)
+
+		_, err := service.CreateWebhook("alice", webhooks.WebhookRequest{URL: "https://example.com/hook"})
+		require.ErrorIs(t, err, webhooks.ErrTooManyWebhooks)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		service := New(mocks.NewStore(t), WithEncryptionKey(testKey))
+
+		_, err := service.CreateWebhook("alice", webhooks.WebhookRequest{
+			URL:    "ftp://example.com",
+			Events: []notes.EventType{"note.unknown"},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestService_RetryDelivery(t *testing.T) {
+	store := mocks.NewStore(t)
+	service := New(store)
+
+	store.On("GetDelivery", "d1").Return(webhooks.Delivery{ID: "d1", UID: "alice", Status: webhooks.StatusDead, Attempts: 8}, nil)
+	store.On("GetDelivery", "d2").Return(webhooks.Delivery{ID: "d2", UID: "alice", Status: webhooks.StatusDelivered}, nil)
+	store.On("UpdateDelivery", mock.MatchedBy(func(d webhooks.Delivery) bool {
+		return d.ID == "d1" && d.Status == webhooks.StatusPending && d.Attempts == 0
+	})).Return(nil)
+
+	_, err := service.RetryDelivery("bob", "d1")
+	require.ErrorIs(t, err, webhooks.ErrDeliveryNotFound)
+	_, err = service.RetryDelivery("alice", "d2")
+	require.ErrorIs(t, err, webhooks.ErrDeliveryNotDead)
+	delivery, err := service.RetryDelivery("alice", "d1")
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.StatusPending, delivery.Status)
+}
+
+func TestService_Deliver(t *testing.T) {
+	type received struct {
+		body      []byte
+		signature string
+		timestamp int64
+	}
+
+	newReceiver := func(t *testing.T, status int) (*httptest.Server, chan received) {
+		requests := make(chan received, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			timestamp, _ := strconv.ParseInt(r.Header.Get(webhooks.TimestampHeader), 10, 64)
+			requests <- received{body: body, signature: r.Header.Get(webhooks.SignatureHeader), timestamp: timestamp}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("internal details"))
+		}))
+		t.Cleanup(srv.Close)
+		return srv, requests
+	}
+
+	subscribe := func(t *testing.T, service *Service, url string) webhooks.CreatedWebhook {
+		created, err := service.CreateWebhook("alice", webhooks.WebhookRequest{
+			URL:    url,
+			Events: []notes.EventType{notes.EventCreated},
+		})
+		require.NoError(t, err)
+		return created
+	}
+
+	t.Run("signed delivery", func(t *testing.T) {
+		srv, requests := newReceiver(t, http.StatusNoContent)
+		store := inmemory.NewWebhooks()
+		service := New(store, WithEncryptionKey(testKey), WithClient(srv.Client()))
+		created := subscribe(t, service, srv.URL)
+
+		service.Publish(notes.Event{Type: notes.EventCreated, NID: "n1", UID: "alice", At: time.Now()})
+		service.Publish(notes.Event{Type: notes.EventDeleted, NID: "n1", UID: "alice", At: time.Now()})
+		service.Publish(notes.Event{Type: notes.EventCreated, NID: "n2", UID: "bob", At: time.Now()})
+		service.Publish(notes.Event{Type: notes.EventCreated, NID: "n3", At: time.Now()})
+
+		assert.Equal(t, 1, service.DeliverDue(context.Background()), "only alice's own note is delivered")
+		req := <-requests
+		assert.True(t, webhooks.Verify(created.Secret, req.timestamp, req.body, req.signature))
+
+		var envelope webhooks.Envelope
+		require.NoError(t, json.Unmarshal(req.body, &envelope))
+		assert.Equal(t, created.ID, envelope.WebhookID)
+		assert.Equal(t, "n1", envelope.Event.NID)
+
+		deliveries, err := service.ListDeliveries("alice", created.ID, webhooks.DeliveryFilter{})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, webhooks.StatusDelivered, deliveries[0].Status)
+		assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseCode)
+	})
+
+	t.Run("retries then dead letter", func(t *testing.T) {
+		srv, requests := newReceiver(t, http.StatusInternalServerError)
+		store := inmemory.NewWebhooks()
+		policy := webhooks.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour}
+		service := New(store, WithEncryptionKey(testKey), WithRetryPolicy(policy), WithClient(srv.Client()))
+		subscribe(t, service, srv.URL)
+
+		service.Publish(notes.Event{Type: notes.EventCreated, NID: "n1", UID: "alice", At: time.Now()})
+		assert.Equal(t, 1, service.DeliverDue(context.Background()))
+		<-requests
+
+		deliveries, err := store.GetDeliveries(webhooks.DeliveryFilter{})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		delivery := deliveries[0]
+		assert.Equal(t, webhooks.StatusPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), delivery.NextAttemptAt, time.Minute)
+		assert.Equal(t, 0, service.DeliverDue(context.Background()), "backoff postpones next attempt")
+
+		delivery.NextAttemptAt = time.Now().UTC()
+		require.NoError(t, store.UpdateDelivery(delivery))
+		assert.Equal(t, 1, service.DeliverDue(context.Background()))
+		<-requests
+
+		dead, err := service.ListDeadLetters("alice", webhooks.DeliveryFilter{})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, 2, dead[0].Attempts)
+		assert.Equal(t, "unexpected status 500", dead[0].LastError, "the response body is not stored")
+
+		_, err = service.RetryDelivery("alice", dead[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, service.DeliverDue(context.Background()))
+	})
+	t.Run("internal addresses are refused", func(t *testing.T) {
+		srv, requests := newReceiver(t, http.StatusNoContent)
+		store := inmemory.NewWebhooks()
+		service := New(store, WithEncryptionKey(testKey))
+		subscribe(t, service, srv.URL)
+
+		service.Publish(notes.Event{Type: notes.EventCreated, NID: "n1", UID: "alice", At: time.Now()})
+		assert.Equal(t, 1, service.DeliverDue(context.Background()))
+		assert.Empty(t, requests, "the loopback receiver must not be reached")
+
+		deliveries, err := store.GetDeliveries(webhooks.DeliveryFilter{})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Contains(t, deliveries[0].LastError, webhooks.ErrForbiddenAddress.Error())
+	})
+}
+
+func TestPublicOnly(t *testing.T) {
+	for _, address := range []string{
+		"127.0.0.1:80", "10.1.2.3:443", "192.168.0.10:80", "169.254.169.254:80",
+		"0.0.0.0:80", "100.64.0.1:80", "[::1]:80", "[fd00::1]:443", "[::ffff:127.0.0.1]:80",
+	} {
+		assert.ErrorIs(t, publicOnly("tcp", address, nil), webhooks.ErrForbiddenAddress, address)
+	}
+	assert.NoError(t, publicOnly("tcp", "93.184.216.34:443", nil))
+	assert.NoError(t, publicOnly("tcp6", "[2606:2800:220:1::1]:443", nil))
+}
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notes_priority_position;
+DROP INDEX IF EXISTS idx_notes_position;
+ALTER TABLE notes DROP COLUMN position;
+ALTER TABLE notes DROP COLUMN priority;
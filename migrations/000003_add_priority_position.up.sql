@@ -0,0 +1,4 @@
+ALTER TABLE notes ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notes ADD COLUMN position TEXT COLLATE "C" NOT NULL DEFAULT '';
+CREATE INDEX idx_notes_position ON notes(position);
+CREATE INDEX idx_notes_priority_position ON notes(priority DESC, position);
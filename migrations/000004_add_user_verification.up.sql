@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN verified BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+-- Существующие аккаунты созданы до появления подтверждения почты.
+UPDATE users SET verified = true;
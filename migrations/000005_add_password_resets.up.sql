@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN password_changed_at TIMESTAMP NOT NULL DEFAULT 'epoch';
+
+CREATE TABLE IF NOT EXISTS password_resets(
+    token_hash TEXT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(uid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_resets_user_id ON password_resets(user_id);
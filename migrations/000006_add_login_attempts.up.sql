@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS login_attempts(
+    key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP NOT NULL DEFAULT 'epoch',
+    locked_until TIMESTAMP NOT NULL DEFAULT 'epoch'
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS access_tokens(
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(uid) ON DELETE CASCADE,
+    UNIQUE(token_hash)
+);
+
+CREATE INDEX idx_access_tokens_user_id ON access_tokens(user_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_identities(
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer, subject),
+    FOREIGN KEY (user_id) REFERENCES users(uid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
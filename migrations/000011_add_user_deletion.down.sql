@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_delete_at;
+
+ALTER TABLE users DROP COLUMN delete_at;
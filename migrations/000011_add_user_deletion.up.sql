@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN delete_at TIMESTAMP;
+
+CREATE INDEX idx_users_delete_at ON users(delete_at) WHERE delete_at IS NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_preferences(
+    user_id VARCHAR(36) PRIMARY KEY,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    locale TEXT NOT NULL DEFAULT 'en',
+    default_status INTEGER NOT NULL DEFAULT 0,
+    default_sort TEXT NOT NULL DEFAULT '',
+    week_start TEXT NOT NULL DEFAULT 'monday',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(uid) ON DELETE CASCADE
+);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS audit_log(
+    seq BIGINT PRIMARY KEY,
+    type TEXT NOT NULL,
+    actor_uid TEXT NOT NULL DEFAULT '',
+    target TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    outcome TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor_uid ON audit_log(actor_uid);
+
+-- Журнал только пополняется: изменение и удаление записей запрещены.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS webhooks(
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    url TEXT NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(uid) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhooks_user_id ON webhooks(user_id);
+
+-- Очередь доставок: строки переживают перезапуск сервиса и остаются историей доставок.
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+    id VARCHAR(36) PRIMARY KEY,
+    webhook_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    event TEXT NOT NULL,
+    nid TEXT NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_attempt_at TIMESTAMP,
+    response_code INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at);
+CREATE INDEX idx_webhook_deliveries_user_status ON webhook_deliveries(user_id, status, created_at);
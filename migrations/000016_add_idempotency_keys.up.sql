@@ -0,0 +1,12 @@
+-- Ключи Idempotency-Key и сохранённые ответы; status = 0, пока запрос выполняется.
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+    key TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    status INT NOT NULL DEFAULT 0,
+    headers JSONB NOT NULL DEFAULT '{}',
+    body BYTEA,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,9 @@
+-- Корзины токенов ограничения частоты запросов, общие для всех реплик.
+-- updated_at пуст у только что созданной корзины: она считается полной.
+CREATE TABLE IF NOT EXISTS rate_limits(
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_rate_limits_updated_at ON rate_limits(updated_at);
@@ -0,0 +1,2 @@
+-- Последний принятый интервал TOTP: коды не новее него отклоняются как повтор.
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
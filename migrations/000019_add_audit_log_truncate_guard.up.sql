@@ -0,0 +1,4 @@
+-- TRUNCATE обходит построчный триггер, поэтому запрещается отдельным триггером.
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
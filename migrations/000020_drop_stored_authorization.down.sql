@@ -0,0 +1,2 @@
+-- Удалённые токены не восстановить, а повторы обходятся без них.
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Выданные при регистрации JWT больше не сохраняются вместе с ответом.
+UPDATE idempotency_keys SET headers = headers - 'Authorization';